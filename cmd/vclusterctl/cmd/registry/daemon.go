@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// dockerDaemon talks to the docker daemon over its api, so that we don't need the docker cli
+type dockerDaemon struct {
+	client client.APIClient
+}
+
+// newDockerDaemon returns a client for the docker daemon configured by the DOCKER_HOST, DOCKER_TLS_VERIFY,
+// DOCKER_CERT_PATH and DOCKER_API_VERSION environment variables, or the default socket. The api version is
+// negotiated with the daemon if DOCKER_API_VERSION is not set. ssh:// docker hosts require the docker cli
+// to connect and are not supported.
+func newDockerDaemon(opts ...client.Opt) (*dockerDaemon, error) {
+	if dockerHost := os.Getenv(client.EnvOverrideHost); strings.HasPrefix(dockerHost, "ssh://") {
+		return nil, fmt.Errorf("docker host %s is not supported, ssh docker hosts require the docker cli", dockerHost)
+	}
+
+	dockerClient, err := client.NewClientWithOpts(append([]client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+
+	return &dockerDaemon{client: dockerClient}, nil
+}
+
+// ping checks if the docker daemon is reachable
+func (d *dockerDaemon) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := d.client.Ping(ctx)
+	return err
+}
+
+// exportImage writes the image in the docker save format into a new file in dir and returns its path
+func (d *dockerDaemon) exportImage(ctx context.Context, image, dir string) (string, error) {
+	reader, err := d.client.ImageSave(ctx, []string{image})
+	if err != nil {
+		return "", fmt.Errorf("export image %s from docker daemon: %w", image, err)
+	}
+	defer reader.Close()
+
+	file, err := os.CreateTemp(dir, "image-*.tar")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	// remove the partially written file on errors
+	_, err = io.Copy(file, reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write image %s: %w", image, err)
+	}
+
+	return file.Name(), nil
+}
+
+// loadImage loads the image in the docker save format from reader into the docker daemon and writes
+// the messages of the daemon to out
+func (d *dockerDaemon) loadImage(ctx context.Context, reader io.Reader, out io.Writer) error {
+	response, err := d.client.ImageLoad(ctx, reader)
+	if err != nil {
+		return fmt.Errorf("load image into docker daemon: %w", err)
+	}
+	defer response.Body.Close()
+
+	if !response.JSON {
+		_, err = io.Copy(out, response.Body)
+		return err
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	for {
+		message := struct {
+			Stream string `json:"stream"`
+			Error  string `json:"error"`
+		}{}
+		if err := decoder.Decode(&message); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("read docker daemon response: %w", err)
+		} else if message.Error != "" {
+			return fmt.Errorf("load image into docker daemon: %s", message.Error)
+		}
+
+		_, _ = io.WriteString(out, message.Stream)
+	}
+}
+
+// dockerCLIAvailable returns true if the docker cli is on the PATH
+func dockerCLIAvailable() bool {
+	_, err := exec.LookPath("docker")
+	return err == nil
+}
@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
+)
+
+// manifestAcceptHeader are the manifest media types we accept when resolving digests
+var manifestAcceptHeader = strings.Join([]string{
+	imgspecv1.MediaTypeImageIndex,
+	imgspecv1.MediaTypeImageManifest,
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+// registryClient sends the registry requests that are not covered by go-containerregistry, e.g. manifestDigests
+// during a push. Its transport is shared across all requests.
+var registryClient = &http.Client{Transport: http.DefaultTransport}
+
+// manifestDigests resolves the digests of the given references (repository:tag or repository@digest) in the
+// registry at host by sending HEAD requests for their manifests. The requests share the given client and at most
+// concurrency requests are in flight at the same time. References that don't exist in the registry are omitted
+// from the returned reference to digest map.
+func manifestDigests(ctx context.Context, client *http.Client, host string, references []string, concurrency int) (map[string]string, error) {
+	if concurrency <= 0 {
+		return nil, fmt.Errorf("concurrency must be greater than 0")
+	}
+
+	digests := map[string]string{}
+	digestsMutex := sync.Mutex{}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for _, reference := range references {
+		group.Go(func() error {
+			digest, err := headManifest(groupCtx, client, host, reference)
+			if err != nil {
+				return err
+			} else if digest == "" {
+				return nil
+			}
+
+			digestsMutex.Lock()
+			defer digestsMutex.Unlock()
+			digests[reference] = digest
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return digests, nil
+}
+
+// headManifest returns the digest of the manifest of reference or an empty string if it doesn't exist.
+func headManifest(ctx context.Context, client *http.Client, host, reference string) (string, error) {
+	repository, tagOrDigest, err := splitReference(reference)
+	if err != nil {
+		return "", err
+	}
+
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", strings.TrimSuffix(host, "/"), repository, tagOrDigest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", manifestAcceptHeader)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, manifestURL)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("missing digest for %s", reference)
+	}
+
+	return digest, nil
+}
+
+// splitReference splits a repository:tag or repository@digest reference. References without a tag use latest.
+func splitReference(reference string) (string, string, error) {
+	if repository, digest, ok := strings.Cut(reference, "@"); ok {
+		if repository == "" || digest == "" {
+			return "", "", fmt.Errorf("invalid reference %q", reference)
+		}
+		return repository, digest, nil
+	}
+
+	repository, tag := reference, "latest"
+	if idx := strings.LastIndex(reference, ":"); idx > strings.LastIndex(reference, "/") {
+		repository, tag = reference[:idx], reference[idx+1:]
+	}
+	if repository == "" || tag == "" {
+		return "", "", fmt.Errorf("invalid reference %q", reference)
+	}
+
+	return repository, tag, nil
+}
@@ -0,0 +1,85 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManifestDigests(t *testing.T) {
+	const concurrency = 3
+
+	inFlight := atomic.Int32{}
+	maxInFlight := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			observed := maxInFlight.Load()
+			if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if r.Method != http.MethodHead {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+
+		repository, tagOrDigest, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		if !ok || repository == "missing" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Docker-Content-Digest", "sha256:"+repository+"-"+tagOrDigest)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	references := []string{"missing:v1", "library/nginx", "app@sha256:1234"}
+	for i := range 17 {
+		references = append(references, fmt.Sprintf("image-%d:v%d", i, i))
+	}
+
+	digests, err := manifestDigests(context.Background(), server.Client(), server.URL, references, concurrency)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if maxInFlight.Load() > concurrency {
+		t.Errorf("expected at most %d concurrent requests, got %d", concurrency, maxInFlight.Load())
+	}
+	if len(digests) != len(references)-1 {
+		t.Fatalf("expected %d digests, got %d: %v", len(references)-1, len(digests), digests)
+	}
+	if _, ok := digests["missing:v1"]; ok {
+		t.Errorf("expected missing reference to be omitted")
+	}
+	for reference, expected := range map[string]string{
+		"library/nginx":   "sha256:library/nginx-latest",
+		"app@sha256:1234": "sha256:app-sha256:1234",
+		"image-16:v16":    "sha256:image-16-v16",
+	} {
+		if digests[reference] != expected {
+			t.Errorf("expected digest %s for %s, got %s", expected, reference, digests[reference])
+		}
+	}
+}
+
+func TestManifestDigestsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := manifestDigests(context.Background(), server.Client(), server.URL, []string{"app:v1"}, 2)
+	if err == nil || !strings.Contains(err.Error(), "unexpected status code 500") {
+		t.Errorf("expected status code error, got %v", err)
+	}
+}
@@ -0,0 +1,349 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+)
+
+// errStopListing is returned by a list callback to stop paginating early
+var errStopListing = errors.New("stop listing")
+
+type ListOptions struct {
+	*flags.GlobalFlags
+
+	Limit    int
+	PageSize int
+	Parallel int
+	Output   string
+
+	Log log.Logger
+}
+
+// ListImage is a single tag of a repository in the vCluster registry
+type ListImage struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Digest     string `json:"digest"`
+}
+
+func NewListCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &ListOptions{
+		GlobalFlags: globalFlags,
+
+		Log: log.GetInstance(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the repositories, tags and digests in the vCluster registry",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.Run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().IntVar(&o.Limit, "limit", 0, "Maximum number of repositories to print. 0 means no limit.")
+	cmd.Flags().IntVar(&o.PageSize, "page-size", 100, "Number of repositories and tags to request from the registry per page")
+	cmd.Flags().IntVar(&o.Parallel, "parallel", 10, "Number of tag digests to resolve in parallel")
+	cmd.Flags().StringVar(&o.Output, "output", "table", "Choose the format of the output. [table|json]")
+
+	return cmd
+}
+
+func (o *ListOptions) Run(ctx context.Context) error {
+	if o.Limit < 0 {
+		return fmt.Errorf("--limit must be 0 or greater")
+	} else if o.PageSize <= 0 {
+		return fmt.Errorf("--page-size must be greater than 0")
+	} else if o.Parallel <= 0 {
+		return fmt.Errorf("--parallel must be greater than 0")
+	} else if o.Output != "table" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, use table or json", o.Output)
+	}
+
+	// get the client config
+	restConfig, err := getConfig(ctx, o.GlobalFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client config: %w", err)
+	}
+
+	transport, err := rest.TransportFor(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to get transport: %w", err)
+	}
+
+	// print the images of every repository as they come in, so we never hold the whole catalog in memory
+	out := o.Log.Writer(logrus.InfoLevel, true)
+	defer out.Close()
+
+	printer := newImagePrinter(out, o.Output)
+	err = listImages(ctx, &http.Client{Transport: transport}, restConfig.Host, o.PageSize, o.Limit, o.Parallel, printer.Print)
+	if err != nil {
+		return err
+	}
+
+	return printer.Done()
+}
+
+// listImages walks the repositories of the registry at host and calls fn with the tags and their digests of
+// every repository, one repository at a time. At most limit repositories are listed, a limit of 0 lists all repositories.
+// The digests of the tags of a repository are resolved with at most parallel concurrent requests.
+func listImages(ctx context.Context, client *http.Client, host string, pageSize, limit, parallel int, fn func(images []ListImage) error) error {
+	listed := 0
+	err := listRepositories(ctx, client, host, pageSize, func(repository string) error {
+		if limit > 0 && listed >= limit {
+			return errStopListing
+		}
+		listed++
+
+		tags := []string{}
+		err := listTags(ctx, client, host, repository, pageSize, func(tag string) error {
+			tags = append(tags, tag)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list tags of %s: %w", repository, err)
+		}
+
+		references := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			references = append(references, repository+":"+tag)
+		}
+		digests, err := manifestDigests(ctx, client, host, references, parallel)
+		if err != nil {
+			return fmt.Errorf("failed to resolve digests of %s: %w", repository, err)
+		}
+
+		images := make([]ListImage, 0, len(tags))
+		for i, tag := range tags {
+			images = append(images, ListImage{Repository: repository, Tag: tag, Digest: digests[references[i]]})
+		}
+
+		return fn(images)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	return nil
+}
+
+// imagePrinter prints the images one repository at a time. Table rows are flushed after every repository and
+// json output is written as a streamed array.
+type imagePrinter struct {
+	out    io.Writer
+	table  *tabwriter.Writer
+	output string
+
+	printed int
+}
+
+func newImagePrinter(out io.Writer, output string) *imagePrinter {
+	return &imagePrinter{
+		out:    out,
+		table:  tabwriter.NewWriter(out, 0, 8, 2, ' ', 0),
+		output: output,
+	}
+}
+
+// Print prints the images of a single repository
+func (p *imagePrinter) Print(images []ListImage) error {
+	if p.output == "json" {
+		for _, image := range images {
+			raw, err := json.MarshalIndent(image, "    ", "    ")
+			if err != nil {
+				return fmt.Errorf("json marshal image: %w", err)
+			}
+
+			prefix := ",\n    "
+			if p.printed == 0 {
+				prefix = "[\n    "
+			}
+			if _, err := io.WriteString(p.out, prefix+string(raw)); err != nil {
+				return err
+			}
+			p.printed++
+		}
+
+		return nil
+	}
+
+	if p.printed == 0 && len(images) > 0 {
+		_, _ = fmt.Fprintln(p.table, "REPOSITORY\tTAG\tDIGEST")
+	}
+	for _, image := range images {
+		_, _ = fmt.Fprintf(p.table, "%s\t%s\t%s\n", image.Repository, image.Tag, image.Digest)
+		p.printed++
+	}
+
+	return p.table.Flush()
+}
+
+// Done finishes the output after all repositories were printed
+func (p *imagePrinter) Done() error {
+	if p.output == "json" {
+		end := "\n]\n"
+		if p.printed == 0 {
+			end = "[]\n"
+		}
+
+		_, err := io.WriteString(p.out, end)
+		return err
+	}
+
+	if p.printed == 0 {
+		_, _ = fmt.Fprintln(p.table, "REPOSITORY\tTAG\tDIGEST")
+	}
+
+	return p.table.Flush()
+}
+
+type catalogResponse struct {
+	Repositories []string `json:"repositories"`
+}
+
+type tagsResponse struct {
+	Tags []string `json:"tags"`
+}
+
+// listRepositories walks the paginated /v2/_catalog endpoint of the registry at host and
+// calls fn for every repository. Pages are requested one at a time by following the Link header.
+func listRepositories(ctx context.Context, client *http.Client, host string, pageSize int, fn func(repository string) error) error {
+	return listPages(ctx, client, strings.TrimSuffix(host, "/")+"/v2/_catalog", pageSize, func(body io.Reader) ([]string, error) {
+		catalog := &catalogResponse{}
+		if err := json.NewDecoder(body).Decode(catalog); err != nil {
+			return nil, fmt.Errorf("failed to decode catalog: %w", err)
+		}
+
+		return catalog.Repositories, nil
+	}, fn)
+}
+
+// listTags walks the paginated /v2/<repository>/tags/list endpoint of the registry at host and
+// calls fn for every tag of the repository.
+func listTags(ctx context.Context, client *http.Client, host, repository string, pageSize int, fn func(tag string) error) error {
+	return listPages(ctx, client, strings.TrimSuffix(host, "/")+"/v2/"+repository+"/tags/list", pageSize, func(body io.Reader) ([]string, error) {
+		tags := &tagsResponse{}
+		if err := json.NewDecoder(body).Decode(tags); err != nil {
+			return nil, fmt.Errorf("failed to decode tags: %w", err)
+		}
+
+		return tags.Tags, nil
+	}, fn)
+}
+
+// listPages requests the pages of the endpoint one at a time by following the Link header
+// and calls fn for every entry decoded from the pages. It fails instead of looping forever if the
+// registry links to the same page again or returns an empty page with a next page link.
+func listPages(ctx context.Context, client *http.Client, endpoint string, pageSize int, decode func(body io.Reader) ([]string, error), fn func(entry string) error) error {
+	pageURL, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to parse url %s: %w", endpoint, err)
+	}
+
+	query := url.Values{}
+	query.Set("n", strconv.Itoa(pageSize))
+	for {
+		pageURL.RawQuery = query.Encode()
+		entries, next, err := getPage(ctx, client, pageURL.String(), decode)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				if errors.Is(err, errStopListing) {
+					return nil
+				}
+
+				return err
+			}
+		}
+
+		if next == "" {
+			return nil
+		} else if len(entries) == 0 {
+			return fmt.Errorf("registry returned an empty page with a next page link from %s", pageURL.String())
+		}
+
+		// we only take over the query of the next link, because the registry might be served
+		// behind a path-prefixed proxy that the registry itself doesn't know about
+		nextQuery, err := url.ParseQuery(next)
+		if err != nil {
+			return fmt.Errorf("failed to parse next page query %q: %w", next, err)
+		} else if nextQuery.Encode() == pageURL.RawQuery {
+			return fmt.Errorf("registry returned a next page link to the same page from %s", pageURL.String())
+		}
+		query = nextQuery
+	}
+}
+
+func getPage(ctx context.Context, client *http.Client, pageURL string, decode func(body io.Reader) ([]string, error)) ([]string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, pageURL)
+	}
+
+	entries, err := decode(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next, err := parseNextLink(resp.Header.Get("Link"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return entries, next, nil
+}
+
+// parseNextLink parses a Link header of the form `</v2/_catalog?last=repo&n=100>; rel="next"`
+// and returns the raw query of the next page or an empty string if there is no next page.
+func parseNextLink(header string) (string, error) {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			if strings.ReplaceAll(strings.TrimSpace(param), " ", "") != `rel="next"` {
+				continue
+			}
+
+			next, err := url.Parse(strings.Trim(target, "<>"))
+			if err != nil {
+				return "", fmt.Errorf("failed to parse link header %q: %w", header, err)
+			}
+
+			return next.RawQuery, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,280 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newPaginatedCatalogServer serves a /v2/_catalog endpoint that paginates the given repositories
+// the same way the distribution registry does, by returning a Link header with the next page.
+func newPaginatedCatalogServer(t *testing.T, repositories []string) (*httptest.Server, *int) {
+	t.Helper()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/_catalog" {
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+
+		n, err := strconv.Atoi(r.URL.Query().Get("n"))
+		if err != nil || n <= 0 {
+			t.Errorf("unexpected page size %q", r.URL.Query().Get("n"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		start := 0
+		if last := r.URL.Query().Get("last"); last != "" {
+			start = slices.Index(repositories, last) + 1
+		}
+		end := min(start+n, len(repositories))
+		if end < len(repositories) {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/_catalog?last=%s&n=%d>; rel="next"`, repositories[end-1], n))
+		}
+
+		_ = json.NewEncoder(w).Encode(&catalogResponse{Repositories: repositories[start:end]})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestListRepositoriesFollowsPages(t *testing.T) {
+	repositories := []string{}
+	for i := range 7 {
+		repositories = append(repositories, fmt.Sprintf("library/repo-%d", i))
+	}
+	server, requests := newPaginatedCatalogServer(t, repositories)
+
+	got := []string{}
+	err := listRepositories(context.Background(), server.Client(), server.URL, 3, func(repository string) error {
+		got = append(got, repository)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("listRepositories() error = %v", err)
+	}
+	if !slices.Equal(got, repositories) {
+		t.Fatalf("listRepositories() = %v, want %v", got, repositories)
+	}
+	if *requests != 3 {
+		t.Fatalf("expected 3 catalog requests, got %d", *requests)
+	}
+}
+
+func TestListRepositoriesStopsEarly(t *testing.T) {
+	repositories := []string{"a", "b", "c", "d", "e", "f"}
+	server, requests := newPaginatedCatalogServer(t, repositories)
+
+	got := []string{}
+	err := listRepositories(context.Background(), server.Client(), server.URL+"/", 2, func(repository string) error {
+		if len(got) == 3 {
+			return errStopListing
+		}
+
+		got = append(got, repository)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("listRepositories() error = %v", err)
+	}
+	if !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatalf("listRepositories() = %v", got)
+	}
+	if *requests != 2 {
+		t.Fatalf("expected the listing to stop after 2 catalog requests, got %d", *requests)
+	}
+}
+
+func TestListRepositoriesStopsOnBrokenPagination(t *testing.T) {
+	tests := map[string]struct {
+		link         string
+		repositories []string
+		want         string
+	}{
+		"same page": {
+			link:         `</v2/_catalog?n=2>; rel="next"`,
+			repositories: []string{"a", "b"},
+			want:         "same page",
+		},
+		"empty page": {
+			link: `</v2/_catalog?last=b&n=2>; rel="next"`,
+			want: "empty page",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				w.Header().Set("Link", tt.link)
+				_ = json.NewEncoder(w).Encode(&catalogResponse{Repositories: tt.repositories})
+			}))
+			defer server.Close()
+
+			err := listRepositories(context.Background(), server.Client(), server.URL, 2, func(string) error {
+				return nil
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("listRepositories() error = %v, want %q", err, tt.want)
+			}
+			if requests != 1 {
+				t.Fatalf("expected the listing to stop after 1 catalog request, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestParseNextLink(t *testing.T) {
+	next, err := parseNextLink(`</v2/_catalog?last=foo&n=100>; rel="next"`)
+	if err != nil {
+		t.Fatalf("parseNextLink() error = %v", err)
+	}
+	if next != "last=foo&n=100" {
+		t.Fatalf("parseNextLink() = %q", next)
+	}
+
+	next, err = parseNextLink("")
+	if err != nil || next != "" {
+		t.Fatalf("parseNextLink(\"\") = %q, %v, want empty", next, err)
+	}
+}
+
+func TestListImages(t *testing.T) {
+	tags := map[string][]string{
+		"library/nginx": {"1.25", "1.26", "latest"},
+		"loft-sh/empty": nil,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/_catalog":
+			_ = json.NewEncoder(w).Encode(&catalogResponse{Repositories: []string{"library/nginx", "loft-sh/empty"}})
+		case strings.HasSuffix(r.URL.Path, "/tags/list"):
+			repositoryTags := tags[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/"), "/tags/list")]
+
+			// return the tags one by one to check that the tag pages are followed as well
+			start := 0
+			if last := r.URL.Query().Get("last"); last != "" {
+				start = slices.Index(repositoryTags, last) + 1
+			}
+			end := min(start+1, len(repositoryTags))
+			if end < len(repositoryTags) {
+				w.Header().Set("Link", fmt.Sprintf(`<%s?last=%s&n=1>; rel="next"`, r.URL.Path, repositoryTags[end-1]))
+			}
+			_ = json.NewEncoder(w).Encode(&tagsResponse{Tags: repositoryTags[start:end]})
+		case strings.Contains(r.URL.Path, "/manifests/"):
+			if r.Method != http.MethodHead || !strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json") {
+				t.Errorf("unexpected manifest request %s with accept %q", r.Method, r.Header.Get("Accept"))
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:"+path.Base(r.URL.Path))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	batches := []int{}
+	images := []ListImage{}
+	err := listImages(context.Background(), server.Client(), server.URL, 1, 0, 2, func(repositoryImages []ListImage) error {
+		batches = append(batches, len(repositoryImages))
+		images = append(images, repositoryImages...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("listImages() error = %v", err)
+	}
+
+	expected := []ListImage{
+		{Repository: "library/nginx", Tag: "1.25", Digest: "sha256:1.25"},
+		{Repository: "library/nginx", Tag: "1.26", Digest: "sha256:1.26"},
+		{Repository: "library/nginx", Tag: "latest", Digest: "sha256:latest"},
+	}
+	if !slices.Equal(images, expected) {
+		t.Fatalf("listImages() = %v, want %v", images, expected)
+	}
+	if !slices.Equal(batches, []int{3, 0}) {
+		t.Fatalf("expected the images to be passed one repository at a time, got batches of %v", batches)
+	}
+
+	images = []ListImage{}
+	err = listImages(context.Background(), server.Client(), server.URL, 100, 1, 2, func(repositoryImages []ListImage) error {
+		images = append(images, repositoryImages...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("listImages() error = %v", err)
+	} else if len(images) != 3 {
+		t.Fatalf("expected the limit to only apply to repositories, got %v", images)
+	}
+}
+
+func TestImagePrinter(t *testing.T) {
+	repositories := [][]ListImage{
+		{
+			{Repository: "library/nginx", Tag: "1.25", Digest: "sha256:a"},
+			{Repository: "library/nginx", Tag: "latest", Digest: "sha256:b"},
+		},
+		nil,
+		{
+			{Repository: "loft-sh/vcluster", Tag: "0.21", Digest: "sha256:c"},
+		},
+	}
+
+	// json output is a single array across all repositories
+	out := &bytes.Buffer{}
+	printer := newImagePrinter(out, "json")
+	for _, images := range repositories {
+		if err := printer.Print(images); err != nil {
+			t.Fatalf("Print() error = %v", err)
+		}
+	}
+	if err := printer.Done(); err != nil {
+		t.Fatalf("Done() error = %v", err)
+	}
+
+	images := []ListImage{}
+	if err := json.Unmarshal(out.Bytes(), &images); err != nil {
+		t.Fatalf("invalid json output %q: %v", out.String(), err)
+	}
+	if !slices.Equal(images, slices.Concat(repositories...)) {
+		t.Fatalf("json output = %v, want %v", images, slices.Concat(repositories...))
+	}
+
+	// the table header is only printed once
+	out.Reset()
+	printer = newImagePrinter(out, "table")
+	for _, images := range repositories {
+		if err := printer.Print(images); err != nil {
+			t.Fatalf("Print() error = %v", err)
+		}
+	}
+	if err := printer.Done(); err != nil {
+		t.Fatalf("Done() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || strings.Fields(lines[0])[0] != "REPOSITORY" || !slices.Equal(strings.Fields(lines[3]), []string{"loft-sh/vcluster", "0.21", "sha256:c"}) {
+		t.Fatalf("unexpected table output:\n%s", out.String())
+	}
+
+	// empty registries still produce valid output
+	for output, expected := range map[string]string{"json": "[]\n", "table": "REPOSITORY  TAG  DIGEST\n"} {
+		out.Reset()
+		if err := newImagePrinter(out, output).Done(); err != nil {
+			t.Fatalf("Done() error = %v", err)
+		} else if out.String() != expected {
+			t.Fatalf("empty %s output = %q, want %q", output, out.String(), expected)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
@@ -53,7 +54,7 @@ func (o *ProxyOptions) Run(ctx context.Context) error {
 	}
 
 	// create the proxy server
-	if err := startReverseProxy(restConfig, o.Port, o.Log); err != nil {
+	if err := startReverseProxy(ctx, restConfig, o.Port, o.Log); err != nil {
 		return fmt.Errorf("failed to start reverse proxy: %w", err)
 	}
 
@@ -64,7 +65,8 @@ func (o *ProxyOptions) Run(ctx context.Context) error {
 	return nil
 }
 
-func startReverseProxy(restConfig *rest.Config, port int, log log.Logger) error {
+// startReverseProxy serves the registry of the virtual cluster on the given local port until ctx is done
+func startReverseProxy(ctx context.Context, restConfig *rest.Config, port int, log log.Logger) error {
 	// get the transport
 	transport, err := rest.TransportFor(restConfig)
 	if err != nil {
@@ -108,10 +110,14 @@ func startReverseProxy(restConfig *rest.Config, port int, log log.Logger) error
 
 	// start the server
 	go func() {
-		if err := server.ListenAndServe(); err != nil {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Errorf("Failed to serve proxy server: %v", err)
 		}
 	}()
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
 
 	// wait for the server to be ready
 	now := time.Now()
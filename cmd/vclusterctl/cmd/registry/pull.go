@@ -1,18 +1,30 @@
 package registry
 
 import (
+	"archive/tar"
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/loft-sh/image/copy"
 	"github.com/loft-sh/image/transports/alltransports"
 	"github.com/loft-sh/image/types"
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/clihelper"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +35,9 @@ type PullOptions struct {
 
 	Architecture string
 
+	FromVCluster bool
+	Output       string
+
 	Log log.Logger
 }
 
@@ -36,7 +51,11 @@ func NewPullCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pull",
 		Short: "Pull a docker image from a container registry and save it to a tarball that can be imported into the vCluster registry",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Pull a docker image from a container registry and save it to a tarball that can be imported into the vCluster registry.
+
+With --from-vcluster the images are pulled out of the vCluster registry instead and either loaded into the local
+docker daemon or, if --output is specified, written to a single OCI layout tarball.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return o.Run(cmd.Context(), args)
 		},
@@ -44,11 +63,19 @@ func NewPullCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 
 	cmd.Flags().StringVar(&o.Destination, "destination", "", "Path to the destination directory. If not specified, the images will be pulled to the current directory.")
 	cmd.Flags().StringVar(&o.Architecture, "architecture", runtime.GOARCH, "Architecture of the image to pull. If not specified, the architecture will be detected automatically. Use 'all' to pull all architectures.")
+	cmd.Flags().BoolVar(&o.FromVCluster, "from-vcluster", false, "Pull the images from the vCluster registry instead of their original registry. E.g. vcluster registry pull nginx --from-vcluster")
+	cmd.Flags().StringVar(&o.Output, "output", "", "Path to the OCI layout tarball to write the images pulled from the vCluster registry to. If not specified, the images are loaded into the local docker daemon via docker load. Only valid together with --from-vcluster")
 
 	return cmd
 }
 
 func (o *PullOptions) Run(ctx context.Context, images []string) error {
+	if o.FromVCluster {
+		return o.runFromVCluster(ctx, images)
+	} else if o.Output != "" {
+		return fmt.Errorf("--output can only be used together with --from-vcluster")
+	}
+
 	for _, image := range images {
 		if err := o.pullImage(ctx, image); err != nil {
 			return fmt.Errorf("failed to pull image %s: %w", image, err)
@@ -115,3 +142,239 @@ func (o *PullOptions) pullImage(ctx context.Context, image string) error {
 
 	return nil
 }
+
+func (o *PullOptions) runFromVCluster(ctx context.Context, images []string) error {
+	if o.Destination != "" {
+		return fmt.Errorf("cannot use --destination with --from-vcluster, use --output instead")
+	} else if o.Architecture == "all" {
+		return fmt.Errorf("cannot use --architecture all with --from-vcluster")
+	}
+
+	// get the client config
+	restConfig, err := getConfig(ctx, o.GlobalFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client config: %w", err)
+	}
+
+	// start the reverse proxy
+	localPort := clihelper.RandomPort()
+	if err := startReverseProxy(ctx, restConfig, localPort, o.Log); err != nil {
+		return fmt.Errorf("failed to start reverse proxy: %w", err)
+	}
+
+	return o.pullFromRegistry(ctx, fmt.Sprintf("127.0.0.1:%d", localPort), images)
+}
+
+// pullFromRegistry pulls the images from the registry at registryHost and either writes them into the
+// OCI layout tarball at Output or loads them into the local docker daemon.
+func (o *PullOptions) pullFromRegistry(ctx context.Context, registryHost string, images []string) error {
+	var layoutPath layout.Path
+	if o.Output != "" {
+		tempDir, err := os.MkdirTemp("", "vcluster-registry-pull-")
+		if err != nil {
+			return fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		layoutPath, err = layout.Write(tempDir, empty.Index)
+		if err != nil {
+			return fmt.Errorf("failed to create oci layout: %w", err)
+		}
+	}
+
+	for _, image := range images {
+		srcRef, err := name.ParseReference(image)
+		if err != nil {
+			return fmt.Errorf("failed to parse image reference %s: %w", image, err)
+		}
+
+		vClusterRef, err := vClusterReference(srcRef, registryHost)
+		if err != nil {
+			return err
+		}
+
+		o.Log.Infof("Pulling %s from vCluster at %s", image, registryHost)
+		img, err := remote.Image(vClusterRef, remote.WithContext(ctx), remote.WithPlatform(v1.Platform{OS: "linux", Architecture: o.Architecture}))
+		if err != nil {
+			return fmt.Errorf("failed to get image %s: %w", image, err)
+		}
+
+		if o.Output != "" {
+			err = layoutPath.AppendImage(img, layout.WithAnnotations(map[string]string{imgspecv1.AnnotationRefName: srcRef.String()}))
+			if err != nil {
+				return fmt.Errorf("failed to write image %s to oci layout: %w", image, err)
+			}
+
+			continue
+		}
+
+		if err := o.dockerLoad(ctx, srcRef, img); err != nil {
+			return fmt.Errorf("failed to load image %s into docker: %w", image, err)
+		}
+	}
+
+	if o.Output != "" {
+		o.Log.Infof("Writing images to %s", o.Output)
+		err := o.withProgress(o.Output, func(progressChan chan<- v1.Update) error {
+			return archiveDirectory(string(layoutPath), o.Output, progressChan)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", o.Output, err)
+		}
+	}
+
+	return nil
+}
+
+// dockerLoad streams the image as docker tarball into docker load. If the docker cli is not available,
+// the image is loaded through the docker daemon api instead.
+func (o *PullOptions) dockerLoad(ctx context.Context, ref name.Reference, img v1.Image) error {
+	reader, writer := io.Pipe()
+	go func() {
+		err := o.withProgress(ref.String(), func(progressChan chan<- v1.Update) error {
+			return tarball.Write(ref, img, writer, tarball.WithProgress(progressChan))
+		})
+		_ = writer.CloseWithError(err)
+	}()
+
+	if !dockerCLIAvailable() {
+		daemon, err := newDockerDaemon()
+		if err == nil {
+			err = daemon.loadImage(ctx, reader, os.Stdout)
+		}
+		_ = reader.CloseWithError(err)
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", "load")
+	cmd.Stdin = reader
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	_ = reader.CloseWithError(err)
+	return err
+}
+
+// withProgress calls fn with a progress channel and logs the received updates in steps of 10%
+func (o *PullOptions) withProgress(target string, fn func(progressChan chan<- v1.Update) error) error {
+	progressChan := make(chan v1.Update, 100)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		lastStep := int64(-1)
+		for update := range progressChan {
+			if update.Error != nil || update.Total <= 0 {
+				continue
+			}
+
+			step := update.Complete * 10 / update.Total
+			if step > lastStep {
+				lastStep = step
+				o.Log.Infof("%s: %d%%", target, step*10)
+			}
+		}
+	}()
+
+	err := fn(progressChan)
+	close(progressChan)
+	<-done
+	return err
+}
+
+// vClusterReference replaces the registry of ref with registryHost, in the same way images are pushed
+// into the vCluster registry
+func vClusterReference(ref name.Reference, registryHost string) (name.Reference, error) {
+	separator := ":"
+	if _, ok := ref.(name.Digest); ok {
+		separator = "@"
+	}
+
+	vClusterRef, err := name.ParseReference(registryHost+"/"+ref.Context().RepositoryStr()+separator+ref.Identifier(), name.Insecure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse vCluster reference for %s: %w", ref.String(), err)
+	}
+
+	return vClusterRef, nil
+}
+
+// archiveDirectory writes all files below dir into the tarball at target
+func archiveDirectory(dir, target string, progressChan chan<- v1.Update) error {
+	total := int64(0)
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tarWriter := tar.NewWriter(file)
+	complete := int64(0)
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(relPath)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		} else if entry.IsDir() {
+			return nil
+		}
+
+		content, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer content.Close()
+
+		written, err := io.Copy(tarWriter, content)
+		if err != nil {
+			return err
+		}
+
+		complete += written
+		progressChan <- v1.Update{Total: total, Complete: complete}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
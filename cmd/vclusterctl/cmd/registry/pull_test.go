@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/loft-sh/log"
+)
+
+func TestVClusterReference(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                         "127.0.0.1:5000/library/nginx:latest",
+		"ghcr.io/loft-sh/vcluster:0.20": "127.0.0.1:5000/loft-sh/vcluster:0.20",
+		"docker.io/library/nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000": "127.0.0.1:5000/library/nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+	}
+	for image, expected := range tests {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			t.Fatalf("parse %s: %v", image, err)
+		}
+
+		vClusterRef, err := vClusterReference(ref, "127.0.0.1:5000")
+		if err != nil {
+			t.Fatalf("vClusterReference(%s) error = %v", image, err)
+		} else if vClusterRef.String() != expected {
+			t.Fatalf("vClusterReference(%s) = %s, want %s", image, vClusterRef.String(), expected)
+		}
+	}
+}
+
+func TestPullFromRegistryOutput(t *testing.T) {
+	server := httptest.NewServer(newFakeRegistry())
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse server url: %v", err)
+	}
+	localPort, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("parse server port: %v", err)
+	}
+
+	// push an archive first, so that we can pull it again
+	tempDir := t.TempDir()
+	archive := filepath.Join(tempDir, "registry_charts_my-chart+1.0.0.tar")
+	writeOCIArchive(t, archive, helmChartConfigMediaType)
+	pushOptions := &PushOptions{Archives: []string{archive}, Log: log.Discard}
+	if err := pushOptions.pushImagesAndArchives(context.Background(), localPort); err != nil {
+		t.Fatalf("push archive: %v", err)
+	}
+
+	output := filepath.Join(tempDir, "output.tar")
+	o := &PullOptions{Architecture: "amd64", Output: output, Log: log.Discard}
+	if err := o.pullFromRegistry(context.Background(), serverURL.Host, []string{"ghcr.io/charts/my-chart:1.0.0"}); err != nil {
+		t.Fatalf("pullFromRegistry() error = %v", err)
+	}
+
+	// the written archive is a valid oci archive that can be pushed again
+	artifactType, err := detectArtifactType(output)
+	if err != nil {
+		t.Fatalf("read output archive: %v", err)
+	} else if artifactType != helmChartConfigMediaType {
+		t.Fatalf("expected pulled artifact type %q, got %q", helmChartConfigMediaType, artifactType)
+	}
+}
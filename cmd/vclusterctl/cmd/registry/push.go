@@ -1,22 +1,45 @@
 package registry
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/loft-sh/image/copy"
+	"github.com/loft-sh/image/docker"
+	"github.com/loft-sh/image/manifest"
 	"github.com/loft-sh/image/transports/alltransports"
 	"github.com/loft-sh/image/types"
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/util/clihelper"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -24,7 +47,16 @@ import (
 type PushOptions struct {
 	*flags.GlobalFlags
 
-	Architecture string
+	Architecture  string
+	Artifact      bool
+	Transactional bool
+	DockerDaemon  bool
+
+	Tag              string
+	RepositoryPrefix string
+
+	Parallel int
+	Retries  int
 
 	Images   []string
 	Archives []string
@@ -33,6 +65,17 @@ type PushOptions struct {
 	HelmChartRepository string
 
 	Log log.Logger
+
+	// pushed are the references (repository@digest) of the images pushed by this invocation,
+	// which are deleted again if a later push fails and Transactional is set
+	pushed      []string
+	pushedMutex sync.Mutex
+
+	// pushedTags are the references (repository:tag) of the tags written by this invocation
+	pushedTags []string
+
+	// progress receives the copy progress instead of stdout if set
+	progress func(image, line string)
 }
 
 func NewPushCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
@@ -51,7 +94,14 @@ func NewPushCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&o.Architecture, "architecture", runtime.GOARCH, "Architecture of the image. E.g. amd64, arm64, etc. Only valid if used together with an image argument. E.g. vcluster registry push nginx --architecture amd64. Use 'all' to push all architectures.")
-	cmd.Flags().StringSliceVar(&o.Archives, "archive", []string{}, "Path to the archive.tar file. Can also be a directory with .tar files. Needs to have the format registry_repository+tag.tar")
+	cmd.Flags().BoolVar(&o.Artifact, "artifact", false, "Push the images or archives as generic OCI artifacts, e.g. helm charts or wasm modules, without any image specific handling. Archives are detected automatically.")
+	cmd.Flags().BoolVar(&o.Transactional, "transactional", false, "Delete the images and archives that were already pushed by this command if a later push fails. Requires delete permission on the vCluster registry.")
+	cmd.Flags().BoolVar(&o.DockerDaemon, "docker-daemon", false, "Push the images from the local docker daemon via its api instead of pulling them from their registry. Doesn't require the docker cli. The daemon is configured by DOCKER_HOST, DOCKER_TLS_VERIFY and DOCKER_CERT_PATH, ssh:// docker hosts are not supported. Falls back to the registry if the docker daemon is not reachable.")
+	cmd.Flags().StringVar(&o.Tag, "tag", "", "Repository and optional tag to push the image or archive as, e.g. internal/nginx:1.25. Keeps the original tag if none is specified. Only valid for a single image or archive.")
+	cmd.Flags().StringVar(&o.RepositoryPrefix, "repository-prefix", "", "Prefix to add to the repository of every pushed image or archive, e.g. internal will push nginx:1.25 as internal/library/nginx:1.25")
+	cmd.Flags().IntVar(&o.Parallel, "parallel", 1, "Number of archives to push in parallel")
+	cmd.Flags().IntVar(&o.Retries, "retries", 3, "Number of times to retry a push that failed with a transient error, e.g. a 5xx status code or a reset connection")
+	cmd.Flags().StringSliceVar(&o.Archives, "archive", []string{}, "Path to the archive.tar, archive.tar.gz or archive.tgz file or an OCI layout directory. Can also be a directory with archives and OCI layout directories. Needs to have the format registry_repository+tag.tar")
 	cmd.Flags().StringSliceVar(&o.HelmCharts, "helm-chart", []string{}, "Path to the helm chart. Can also be a directory with .tgz files.")
 	cmd.Flags().StringVar(&o.HelmChartRepository, "helm-chart-repository", "charts", "Repository in the vCluster registry to push the helm chart to. E.g. charts will allow you to use the helm chart with oci://<vcluster-host>/charts/my-chart-name:version.")
 
@@ -68,29 +118,168 @@ func (o *PushOptions) Run(ctx context.Context, args []string) error {
 		return fmt.Errorf("either image or --archive or --helm-chart is required")
 	} else if (len(o.Images) > 0 || len(o.Archives) > 0) && len(o.HelmCharts) > 0 {
 		return fmt.Errorf("cannot use --helm-chart with --image or --archive")
+	} else if o.Transactional && len(o.HelmCharts) > 0 {
+		return fmt.Errorf("cannot use --transactional with --helm-chart")
+	} else if (o.Tag != "" || o.RepositoryPrefix != "") && len(o.HelmCharts) > 0 {
+		return fmt.Errorf("cannot use --tag or --repository-prefix with --helm-chart, use --helm-chart-repository instead")
 	}
 
 	// get the client config
-	restConfig, err := getConfig(ctx, o.GlobalFlags)
+	restConfig, err := loadConfig(o.GlobalFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client config: %w", err)
 	}
 
+	// push images and archives
+	if len(o.HelmCharts) == 0 {
+		return PushImages(ctx, restConfig, o.Images, o.pushImagesOptions())
+	}
+
+	if err := ensureRegistryEnabled(ctx, restConfig); err != nil {
+		return err
+	}
+
 	// start the reverse proxy
 	localPort := clihelper.RandomPort()
-	if err := startReverseProxy(restConfig, localPort, o.Log); err != nil {
+	if err := startReverseProxy(ctx, restConfig, localPort, o.Log); err != nil {
 		return fmt.Errorf("failed to start reverse proxy: %w", err)
 	}
 
 	// push helm charts
-	if len(o.HelmCharts) > 0 {
-		if err := o.pushHelmCharts(ctx, localPort); err != nil {
-			return fmt.Errorf("failed to push helm charts: %w", err)
-		}
+	if err := o.pushHelmCharts(ctx, localPort); err != nil {
+		return fmt.Errorf("failed to push helm charts: %w", err)
+	}
 
-		return nil
+	return nil
+}
+
+func (o *PushOptions) pushImagesOptions() PushImagesOptions {
+	return PushImagesOptions{
+		Archives: o.Archives,
+
+		Architecture:  o.Architecture,
+		Artifact:      o.Artifact,
+		Transactional: o.Transactional,
+		DockerDaemon:  o.DockerDaemon,
+
+		Tag:              o.Tag,
+		RepositoryPrefix: o.RepositoryPrefix,
+
+		Parallel: o.Parallel,
+		Retries:  o.Retries,
+
+		Log: o.Log,
+	}
+}
+
+// PushImagesOptions configures PushImages. The fields correspond to the flags of vcluster registry push.
+type PushImagesOptions struct {
+	// Archives are paths to archives or OCI layout directories, or directories containing them, to push
+	Archives []string
+
+	// Architecture of the images to push. Defaults to the architecture of the current machine, use all to push all architectures
+	Architecture string
+	// Artifact pushes the images and archives as generic OCI artifacts without any image specific handling
+	Artifact bool
+	// Transactional deletes the already pushed images again if a later push fails
+	Transactional bool
+	// DockerDaemon pushes the images from the local docker daemon instead of their registry
+	DockerDaemon bool
+
+	// Tag is the repository and optional tag to push a single image or archive as
+	Tag string
+	// RepositoryPrefix is added to the repository of every pushed image or archive
+	RepositoryPrefix string
+
+	// Parallel is the number of archives to push in parallel. Defaults to 1
+	Parallel int
+	// Retries is the number of times to retry a push that failed with a transient error
+	Retries int
+
+	// Progress is called with every progress line of an image or archive push. If nil, the progress is written to stdout
+	Progress func(image, line string)
+
+	// Log receives the log messages of the push. Defaults to log.Discard
+	Log log.Logger
+}
+
+// PushImages pushes the images and archives into the registry of the virtual cluster restConfig points to.
+// It serves the registry on a random local port while pushing, so restConfig can be any virtual cluster kube config.
+func PushImages(ctx context.Context, restConfig *rest.Config, images []string, opts PushImagesOptions) error {
+	if opts.Parallel == 0 {
+		opts.Parallel = 1
+	}
+	if opts.Architecture == "" {
+		opts.Architecture = runtime.GOARCH
+	}
+	if opts.Log == nil {
+		opts.Log = log.Discard
+	}
+
+	// validate options
+	if len(images) == 0 && len(opts.Archives) == 0 {
+		return fmt.Errorf("either an image or an archive is required")
+	} else if opts.Tag != "" && opts.RepositoryPrefix != "" {
+		return fmt.Errorf("cannot use --tag with --repository-prefix")
+	} else if opts.Tag != "" && len(images)+len(opts.Archives) != 1 {
+		return fmt.Errorf("--tag can only be used with a single image or archive")
+	} else if opts.Parallel < 1 {
+		return fmt.Errorf("--parallel must be greater than 0")
+	} else if opts.Retries < 0 {
+		return fmt.Errorf("--retries must be 0 or greater")
+	}
+
+	if err := ensureRegistryEnabled(ctx, restConfig); err != nil {
+		return err
 	}
 
+	// start the reverse proxy, which is stopped again once the push is done. It is not stopped when ctx is
+	// cancelled, so that a transactional push can still be rolled back.
+	proxyCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	localPort := clihelper.RandomPort()
+	if err := startReverseProxy(proxyCtx, restConfig, localPort, opts.Log); err != nil {
+		return fmt.Errorf("failed to start reverse proxy: %w", err)
+	}
+
+	o := &PushOptions{
+		Architecture:     opts.Architecture,
+		Artifact:         opts.Artifact,
+		Transactional:    opts.Transactional,
+		DockerDaemon:     opts.DockerDaemon,
+		Tag:              opts.Tag,
+		RepositoryPrefix: opts.RepositoryPrefix,
+		Parallel:         opts.Parallel,
+		Retries:          opts.Retries,
+
+		Images:   images,
+		Archives: opts.Archives,
+
+		Log: opts.Log,
+
+		progress: opts.Progress,
+	}
+	return o.pushImagesAndArchives(ctx, localPort)
+}
+
+// pushImagesAndArchives pushes all images and archives. If Transactional is set and a push fails,
+// all images that were pushed before are deleted from the registry again.
+func (o *PushOptions) pushImagesAndArchives(ctx context.Context, localPort int) error {
+	if o.Transactional {
+		o.Log.Warnf("Rolling back a failed transactional push deletes the already pushed images and requires delete permission on the vCluster registry")
+	}
+
+	err := o.pushAll(ctx, localPort)
+	if err != nil && o.Transactional {
+		if rollbackErr := o.rollback(ctx); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back pushed images: %w", rollbackErr))
+		}
+	}
+
+	return err
+}
+
+func (o *PushOptions) pushAll(ctx context.Context, localPort int) error {
 	// push images
 	if len(o.Images) > 0 {
 		// push images directly to vCluster registry
@@ -109,8 +298,105 @@ func (o *PushOptions) Run(ctx context.Context, args []string) error {
 	return nil
 }
 
+// rollbackTimeout is the maximum time a rollback may take
+const rollbackTimeout = 2 * time.Minute
+
+// rollback deletes the pushed images in reverse order. It tries to delete all images, even if some deletions fail.
+// The rollback is not cancelled with ctx, so it also runs if the push was interrupted.
+func (o *PushOptions) rollback(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	// deleting a manifest also deletes its tags, so keep the manifests that are still referenced by tags this
+	// invocation didn't write
+	tagged, err := o.taggedByOthers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check the tags of the pushed images, nothing was deleted: %w", err)
+	}
+
+	var errs []error
+	deleted := map[string]bool{}
+	for i := len(o.pushed) - 1; i >= 0; i-- {
+		if deleted[o.pushed[i]] {
+			continue
+		}
+		deleted[o.pushed[i]] = true
+		if tagged[o.pushed[i]] {
+			o.Log.Infof("Keeping %s, as it is also referenced by a tag that was not pushed", o.pushed[i])
+			continue
+		}
+
+		ref, err := name.ParseReference(o.pushed[i], name.Insecure)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse reference %s: %w", o.pushed[i], err))
+			continue
+		}
+
+		o.Log.Infof("Rolling back %s", o.pushed[i])
+		if err := remote.Delete(ref, remote.WithContext(ctx)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", o.pushed[i], err))
+		}
+	}
+
+	o.pushed = nil
+	o.pushedTags = nil
+	return errors.Join(errs...)
+}
+
+// taggedByOthers returns the pushed references (repository@digest) whose manifests are tagged by a tag that was
+// not written by this invocation. The tags of every repository are resolved with at most Parallel concurrent requests.
+func (o *PushOptions) taggedByOthers(ctx context.Context) (map[string]bool, error) {
+	pushedTags := map[string]bool{}
+	for _, tag := range o.pushedTags {
+		pushedTags[tag] = true
+	}
+
+	repositories := map[string]name.Repository{}
+	for _, pushed := range o.pushed {
+		ref, err := name.ParseReference(pushed, name.Insecure)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reference %s: %w", pushed, err)
+		}
+		repositories[ref.Context().Name()] = ref.Context()
+	}
+
+	tagged := map[string]bool{}
+	for _, repository := range repositories {
+		references := []string{}
+		err := listTags(ctx, registryClient, registryURL(repository), repository.RepositoryStr(), tagsPageSize, func(tag string) error {
+			if !pushedTags[repository.Name()+":"+tag] {
+				references = append(references, repository.RepositoryStr()+":"+tag)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s: %w", repository.Name(), err)
+		}
+
+		digests, err := manifestDigests(ctx, registryClient, registryURL(repository), references, max(o.Parallel, 1))
+		if err != nil {
+			return nil, err
+		}
+		for _, digest := range digests {
+			tagged[repository.Name()+"@"+digest] = true
+		}
+	}
+
+	return tagged, nil
+}
+
 func (o *PushOptions) pushImages(ctx context.Context, localPort int, images []string) error {
+	daemon := o.dockerDaemon(ctx)
 	for _, image := range images {
+		if daemon != nil {
+			o.Log.Infof("Pushing %s from the docker daemon to vCluster at %s", image, fmt.Sprintf("127.0.0.1:%d", localPort))
+			if err := o.pushDaemonImage(ctx, daemon, image, localPort); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		srcRef, err := alltransports.ParseImageName("docker://" + image)
 		if err != nil {
 			return fmt.Errorf("failed to parse image reference: %w", err)
@@ -118,7 +404,7 @@ func (o *PushOptions) pushImages(ctx context.Context, localPort int, images []st
 
 		// push the image
 		o.Log.Infof("Pushing %s to vCluster at %s", image, fmt.Sprintf("127.0.0.1:%d", localPort))
-		if err := o.pushImage(ctx, srcRef, srcRef.DockerReference().String(), localPort); err != nil {
+		if err := o.pushImage(ctx, srcRef, srcRef.DockerReference().String(), localPort, o.Artifact, o.reportWriter(image)); err != nil {
 			return err
 		}
 	}
@@ -126,53 +412,390 @@ func (o *PushOptions) pushImages(ctx context.Context, localPort int, images []st
 	return nil
 }
 
+// reportWriter returns the writer for the copy progress of the image
+func (o *PushOptions) reportWriter(image string) io.Writer {
+	if o.progress == nil {
+		return os.Stdout
+	}
+
+	return &progressWriter{image: image, progress: o.progress}
+}
+
+// progressWriter calls progress for every line written to it
+type progressWriter struct {
+	image    string
+	progress func(image, line string)
+
+	buffer []byte
+}
+
+func (p *progressWriter) Write(data []byte) (int, error) {
+	p.buffer = append(p.buffer, data...)
+	for {
+		index := bytes.IndexByte(p.buffer, '\n')
+		if index < 0 {
+			return len(data), nil
+		}
+
+		p.progress(p.image, string(p.buffer[:index]))
+		p.buffer = p.buffer[index+1:]
+	}
+}
+
+// dockerDaemon returns the docker daemon to push the images from, or nil if DockerDaemon is not set
+// or the daemon is not reachable, in which case the images are pulled from their registry.
+func (o *PushOptions) dockerDaemon(ctx context.Context) *dockerDaemon {
+	if !o.DockerDaemon {
+		return nil
+	}
+
+	daemon, err := newDockerDaemon()
+	if err == nil {
+		err = daemon.ping(ctx)
+	}
+	if err != nil {
+		o.Log.Warnf("Docker daemon is not reachable, pushing the images from their registry instead: %v", err)
+		return nil
+	}
+
+	return daemon
+}
+
+func (o *PushOptions) pushDaemonImage(ctx context.Context, daemon *dockerDaemon, image string, localPort int) error {
+	srcRef, err := name.ParseReference(image)
+	if err != nil {
+		return fmt.Errorf("failed to parse image reference: %w", err)
+	} else if _, ok := srcRef.(name.Digest); ok {
+		// the docker daemon exports a new manifest, so the digest would not match anymore
+		return fmt.Errorf("cannot push image %s by digest from the docker daemon", image)
+	}
+
+	destImageName, err := o.destinationReference(srcRef.Name(), localPort)
+	if err != nil {
+		return err
+	}
+	destRef, err := name.ParseReference(destImageName, name.Insecure)
+	if err != nil {
+		return fmt.Errorf("failed to parse destRef: %w", err)
+	}
+
+	// export into a temp dir instead of the working directory
+	tempDir, err := os.MkdirTemp("", "vcluster-registry-push-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	archive, err := daemon.exportImage(ctx, image, tempDir)
+	if err != nil {
+		return err
+	}
+
+	img, err := tarball.ImageFromPath(archive, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read image %s exported from docker daemon: %w", image, err)
+	}
+
+	// remember the manifests the destination tag and the image digest referenced before the push, a rollback
+	// must never delete them
+	var existing map[string]bool
+	var manifestDigest v1.Hash
+	if o.Transactional {
+		manifestDigest, err = img.Digest()
+		if err != nil {
+			return fmt.Errorf("failed to get digest of image %s: %w", image, err)
+		}
+
+		existing, err = o.existingDigests(ctx, destRef.Name(), destRef.Context().Name()+"@"+manifestDigest.String())
+		if err != nil {
+			return fmt.Errorf("failed to check existing images of %s: %w", destRef.Name(), err)
+		}
+	}
+
+	err = o.withRetries(ctx, image, func(ctx context.Context) error {
+		return remote.Write(destRef, img, remote.WithContext(ctx))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to push image %s: %w", image, err)
+	}
+
+	// remember the pushed image by digest, as registries only delete manifests by digest
+	if o.Transactional {
+		if existing[manifestDigest.String()] {
+			o.Log.Debugf("%s already existed before the push and is not rolled back", destRef.Name())
+			return nil
+		}
+
+		o.pushedMutex.Lock()
+		o.pushed = append(o.pushed, destRef.Context().Name()+"@"+manifestDigest.String())
+		o.pushedTags = append(o.pushedTags, destRef.Name())
+		o.pushedMutex.Unlock()
+	}
+
+	return nil
+}
+
 func (o *PushOptions) pushArchives(ctx context.Context, localPort int, archives []string) error {
+	if o.Parallel > 1 {
+		return o.pushArchivesParallel(ctx, localPort, archives)
+	}
+
+	return forEachArchive(archives, func(archive string) error {
+		return o.pushArchive(ctx, localPort, archive, o.reportWriter(archive))
+	})
+}
+
+// pushArchivesParallel pushes at most Parallel archives at the same time. The first failed push cancels
+// the others and its error is returned.
+func (o *PushOptions) pushArchivesParallel(ctx context.Context, localPort int, archives []string) error {
+	outputMutex := sync.Mutex{}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(o.Parallel)
+	err := forEachArchive(archives, func(archive string) error {
+		if err := groupCtx.Err(); err != nil {
+			return err
+		}
+
+		group.Go(func() error {
+			// buffer the progress of every archive, so that the progress lines of parallel pushes don't interleave
+			output := &bytes.Buffer{}
+			err := o.pushArchive(groupCtx, localPort, archive, output)
+
+			outputMutex.Lock()
+			defer outputMutex.Unlock()
+			_, _ = io.Copy(o.reportWriter(archive), output)
+			return err
+		})
+		return nil
+	})
+	if waitErr := group.Wait(); waitErr != nil {
+		return waitErr
+	}
+
+	return err
+}
+
+// archiveExtensions are the supported archive file extensions. Archives are untarred by the oci-archive
+// transport, which detects the compression automatically.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar"}
+
+// supportedArchiveFormats lists all supported archive formats for error messages
+const supportedArchiveFormats = ".tar, .tar.gz and .tgz archives or OCI layout directories containing an index.json"
+
+// forEachArchive calls fn for every archive. If an archive is a directory, fn is called for all archives and OCI layout
+// directories in the directory, unless the directory is an OCI layout itself.
+func forEachArchive(archives []string, fn func(archive string) error) error {
 	for _, archive := range archives {
 		stat, err := os.Stat(archive)
 		if err != nil {
 			return fmt.Errorf("failed to stat archive: %w", err)
 		}
 
-		// if the archive is a directory, push all tar and tar.gz files in the directory
-		if stat.IsDir() {
-			files, err := os.ReadDir(archive)
-			if err != nil {
-				return fmt.Errorf("failed to read directory: %w", err)
+		// push the archive itself if it's a file or an OCI layout directory
+		if !stat.IsDir() {
+			if archiveExtension(archive) == "" {
+				return fmt.Errorf("unsupported archive %s, supported formats are %s", archive, supportedArchiveFormats)
+			}
+			if err := fn(archive); err != nil {
+				return err
+			}
+			continue
+		} else if isOCILayout(archive) {
+			if err := fn(archive); err != nil {
+				return err
 			}
+			continue
+		}
 
-			// push all tar and tar.gz files in the directory
-			for _, file := range files {
-				if !strings.HasSuffix(file.Name(), ".tar") {
-					continue
-				}
+		// if the archive is a directory, push all archives and OCI layouts in the directory
+		files, err := os.ReadDir(archive)
+		if err != nil {
+			return fmt.Errorf("failed to read directory: %w", err)
+		}
 
-				if err := o.pushArchive(ctx, localPort, filepath.Join(archive, file.Name())); err != nil {
-					return err
-				}
+		for _, file := range files {
+			path := filepath.Join(archive, file.Name())
+			if file.IsDir() && !isOCILayout(path) {
+				continue
+			} else if !file.IsDir() && archiveExtension(file.Name()) == "" {
+				continue
+			}
+
+			if err := fn(path); err != nil {
+				return err
 			}
-		} else if err := o.pushArchive(ctx, localPort, archive); err != nil {
-			return err
 		}
 	}
 
 	return nil
 }
 
-func (o *PushOptions) pushArchive(ctx context.Context, localPort int, archive string) error {
+// archiveExtension returns the supported archive extension of the file or an empty string if it isn't supported
+func archiveExtension(file string) string {
+	for _, extension := range archiveExtensions {
+		if strings.HasSuffix(file, extension) {
+			return extension
+		}
+	}
+
+	return ""
+}
+
+// isOCILayout returns true if dir is an extracted OCI image layout
+func isOCILayout(dir string) bool {
+	stat, err := os.Stat(filepath.Join(dir, imgspecv1.ImageIndexFile))
+	return err == nil && !stat.IsDir()
+}
+
+func (o *PushOptions) pushArchive(ctx context.Context, localPort int, archive string, reportWriter io.Writer) error {
 	imageReference := filepath.Base(archive)
-	imageReference = strings.TrimSuffix(imageReference, filepath.Ext(imageReference))
+	imageReference = strings.TrimSuffix(imageReference, archiveExtension(imageReference))
 	imageReference = strings.ReplaceAll(imageReference, "_", "/")
 	imageReference = strings.ReplaceAll(imageReference, "+", ":")
 
-	// parse the source reference
-	srcRef, err := alltransports.ParseImageName(fmt.Sprintf("oci-archive:%s", archive))
+	// parse the source reference, OCI layout directories can be read directly without extracting them first
+	transport := "oci-archive"
+	if isOCILayout(archive) {
+		transport = "oci"
+	}
+	srcRef, err := alltransports.ParseImageName(fmt.Sprintf("%s:%s", transport, archive))
 	if err != nil {
 		return fmt.Errorf("failed to parse image reference: %w", err)
 	}
 
+	// check if the archive is an artifact instead of a container image
+	artifact := o.Artifact
+	if !artifact {
+		artifactType, err := detectArtifactType(archive)
+		if err != nil {
+			return err
+		} else if artifactType != "" {
+			o.Log.Debugf("Detected artifact type %s in %s", artifactType, archive)
+			artifact = true
+		}
+	}
+
 	// push the image
 	o.Log.Infof("Pushing %s to %s", archive, imageReference)
-	return o.pushImage(ctx, srcRef, imageReference, localPort)
+	return o.pushImage(ctx, srcRef, imageReference, localPort, artifact, reportWriter)
+}
+
+// maxManifestSize is the maximum size of the index.json and manifests read from archives
+const maxManifestSize = 4 * 1024 * 1024
+
+// detectArtifactType returns the artifact type of the given archive or OCI layout directory if its manifest is an
+// OCI artifact such as a helm chart and not a container image. It returns an empty string for container images.
+// The index.json and manifest are read straight from the archive, so that it isn't extracted just for this.
+func detectArtifactType(archive string) (string, error) {
+	readFile, err := ociFileReader(archive)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", archive, err)
+	}
+
+	rawIndex, err := readFile(imgspecv1.ImageIndexFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s of %s: %w", imgspecv1.ImageIndexFile, archive, err)
+	}
+	index := &imgspecv1.Index{}
+	if err := json.Unmarshal(rawIndex, index); err != nil {
+		return "", fmt.Errorf("failed to parse %s of %s: %w", imgspecv1.ImageIndexFile, archive, err)
+	} else if len(index.Manifests) != 1 || index.Manifests[0].MediaType != imgspecv1.MediaTypeImageManifest {
+		return "", nil
+	}
+
+	manifestDigest := index.Manifests[0].Digest
+	if err := manifestDigest.Validate(); err != nil {
+		return "", fmt.Errorf("invalid manifest digest in %s: %w", archive, err)
+	}
+	rawManifest, err := readFile(path.Join(imgspecv1.ImageBlobsDir, manifestDigest.Algorithm().String(), manifestDigest.Encoded()))
+	if err != nil {
+		return "", fmt.Errorf("failed to get manifest of %s: %w", archive, err)
+	}
+
+	ociManifest := &imgspecv1.Manifest{}
+	if err := json.Unmarshal(rawManifest, ociManifest); err != nil {
+		return "", fmt.Errorf("failed to parse manifest of %s: %w", archive, err)
+	} else if ociManifest.Config.MediaType == imgspecv1.MediaTypeImageConfig {
+		return "", nil
+	}
+
+	if ociManifest.ArtifactType != "" {
+		return ociManifest.ArtifactType, nil
+	}
+	return ociManifest.Config.MediaType, nil
+}
+
+// ociFileReader returns a function that reads the files of an OCI layout directory or archive by their slash
+// separated path. Archives are scanned once and only files up to maxManifestSize are kept in memory.
+func ociFileReader(archive string) (func(name string) ([]byte, error), error) {
+	if isOCILayout(archive) {
+		return func(name string) ([]byte, error) {
+			return readLimited(filepath.Join(archive, filepath.FromSlash(name)))
+		}, nil
+	}
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// archives might be gzip compressed, independent of their extension
+	reader := bufio.NewReader(f)
+	var archiveReader io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+
+		archiveReader = gzipReader
+	}
+
+	files := map[string][]byte{}
+	tarReader := tar.NewReader(archiveReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		} else if header.Typeflag != tar.TypeReg || header.Size > maxManifestSize {
+			continue
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(strings.TrimPrefix(header.Name, "./"))] = content
+	}
+
+	return func(name string) ([]byte, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+		}
+		return content, nil
+	}, nil
+}
+
+// readLimited reads the file at name, which must not be larger than maxManifestSize
+func readLimited(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(io.LimitReader(f, maxManifestSize+1))
+	if err != nil {
+		return nil, err
+	} else if len(content) > maxManifestSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxManifestSize)
+	}
+	return content, nil
 }
 
 func (o *PushOptions) pushHelmCharts(ctx context.Context, localPort int) error {
@@ -225,7 +848,149 @@ func (o *PushOptions) pushHelmChart(ctx context.Context, helmChart string, local
 	return nil
 }
 
-func (o *PushOptions) pushImage(ctx context.Context, srcRef types.ImageReference, destImageName string, localPort int) error {
+func (o *PushOptions) pushImage(ctx context.Context, srcRef types.ImageReference, destImageName string, localPort int, artifact bool, reportWriter io.Writer) error {
+	destImageName, err := o.destinationReference(destImageName, localPort)
+	if err != nil {
+		return err
+	}
+	destRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", destImageName))
+	if err != nil {
+		return fmt.Errorf("failed to parse destRef: %w", err)
+	}
+
+	// remember the manifest the destination referenced before the push, a rollback must never delete it
+	var existing map[string]bool
+	if o.Transactional {
+		existing, err = o.existingDigests(ctx, destImageName)
+		if err != nil {
+			return fmt.Errorf("failed to check existing images of %s: %w", destImageName, err)
+		}
+	}
+
+	// copy the image
+	var copiedManifest []byte
+	err = o.withRetries(ctx, destImageName, func(ctx context.Context) error {
+		var copyErr error
+		copiedManifest, copyErr = copy.Image(ctx, destRef, srcRef, o.copyOptions(strings.Contains(destImageName, "@"), artifact, reportWriter))
+		return copyErr
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy image: %w", err)
+	}
+
+	// remember the pushed image by digest, as registries only delete manifests by digest
+	if o.Transactional {
+		manifestDigest, err := manifest.Digest(copiedManifest)
+		if err != nil {
+			return fmt.Errorf("failed to get digest of pushed image %s: %w", destImageName, err)
+		} else if existing[manifestDigest.String()] {
+			o.Log.Debugf("%s already existed before the push and is not rolled back", destImageName)
+			return nil
+		}
+
+		o.pushedMutex.Lock()
+		o.pushed = append(o.pushed, destRef.DockerReference().Name()+"@"+manifestDigest.String())
+		if !strings.Contains(destImageName, "@") {
+			o.pushedTags = append(o.pushedTags, destImageName)
+		}
+		o.pushedMutex.Unlock()
+	}
+
+	return nil
+}
+
+// existingDigests returns the digests of the manifests the given references (repository:tag or repository@digest)
+// point to before they are pushed. Only the pushed references are checked, manifests that are tagged by other tags
+// are kept by the rollback itself.
+func (o *PushOptions) existingDigests(ctx context.Context, references ...string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	for _, reference := range references {
+		ref, err := name.ParseReference(reference, name.Insecure)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reference %s: %w", reference, err)
+		}
+
+		separator := ":"
+		if _, ok := ref.(name.Digest); ok {
+			separator = "@"
+		}
+		digests, err := manifestDigests(ctx, registryClient, registryURL(ref.Context()), []string{ref.Context().RepositoryStr() + separator + ref.Identifier()}, 1)
+		if err != nil {
+			return nil, err
+		}
+		for _, digest := range digests {
+			existing[digest] = true
+		}
+	}
+
+	return existing, nil
+}
+
+// tagsPageSize is the number of tags requested per page when checking the tags of pushed repositories
+const tagsPageSize = 100
+
+// registryURL returns the base url of the registry of the repository, e.g. http://127.0.0.1:5000
+func registryURL(repository name.Repository) string {
+	return repository.Scheme() + "://" + repository.RegistryStr()
+}
+
+// destinationReference replaces the registry of imageName with the vCluster registry and rewrites the repository
+// according to Tag or RepositoryPrefix.
+func (o *PushOptions) destinationReference(imageName string, localPort int) (string, error) {
+	_, repository, ok := strings.Cut(imageName, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid destImageName: %s", imageName)
+	}
+
+	if o.Tag != "" {
+		// keep the original tag or digest if the new one doesn't specify any
+		_, tagOrDigest := splitTagOrDigest(repository)
+		repository = o.Tag
+		if _, newTagOrDigest := splitTagOrDigest(o.Tag); newTagOrDigest == "" {
+			repository += tagOrDigest
+		}
+	} else if o.RepositoryPrefix != "" {
+		repository = strings.Trim(o.RepositoryPrefix, "/") + "/" + repository
+	}
+
+	destImageName := fmt.Sprintf("127.0.0.1:%d/%s", localPort, repository)
+	if _, err := name.ParseReference(destImageName, name.Insecure); err != nil {
+		return "", fmt.Errorf("invalid reference %s after rewriting %s: %w", destImageName, imageName, err)
+	}
+
+	return destImageName, nil
+}
+
+// splitTagOrDigest splits the repository from the tag or digest of the reference. The returned
+// tag or digest includes its separator.
+func splitTagOrDigest(reference string) (string, string) {
+	if index := strings.Index(reference, "@"); index >= 0 {
+		return reference[:index], reference[index:]
+	}
+
+	if index := strings.LastIndex(reference, ":"); index > strings.LastIndex(reference, "/") {
+		return reference[:index], reference[index:]
+	}
+
+	return reference, ""
+}
+
+func (o *PushOptions) copyOptions(isDigest, artifact bool, reportWriter io.Writer) *copy.Options {
+	// artifacts have no image config, so we can't select an os or architecture and must not modify them
+	if artifact {
+		return &copy.Options{
+			SourceCtx:      &types.SystemContext{DockerInsecureSkipTLSVerify: types.OptionalBoolTrue},
+			DestinationCtx: &types.SystemContext{DockerInsecureSkipTLSVerify: types.OptionalBoolTrue},
+
+			PreserveDigests:    true,
+			ImageListSelection: copy.CopyAllImages,
+
+			RemoveSignatures: true,
+
+			ReportWriter: reportWriter,
+		}
+	}
+
 	srcContext := &types.SystemContext{
 		OSChoice:                    "linux",
 		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
@@ -236,7 +1001,6 @@ func (o *PushOptions) pushImage(ctx context.Context, srcRef types.ImageReference
 	}
 
 	// check if the image is a digest
-	isDigest := strings.Contains(destImageName, "@")
 	imageListSelection := copy.CopySystemImage
 	if isDigest || o.Architecture == "all" {
 		imageListSelection = copy.CopyAllImages
@@ -245,20 +1009,7 @@ func (o *PushOptions) pushImage(ctx context.Context, srcRef types.ImageReference
 		destContext.ArchitectureChoice = o.Architecture
 	}
 
-	// replace the registry with the vCluster registry
-	parts := strings.Split(destImageName, "/")
-	if len(parts) < 2 {
-		return fmt.Errorf("invalid destImageName: %s", destImageName)
-	}
-	parts[0] = fmt.Sprintf("127.0.0.1:%d", localPort)
-	destImageName = strings.Join(parts, "/")
-	destRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", destImageName))
-	if err != nil {
-		return fmt.Errorf("failed to parse destRef: %w", err)
-	}
-
-	// copy the image
-	_, err = copy.Image(ctx, destRef, srcRef, &copy.Options{
+	return &copy.Options{
 		SourceCtx:      srcContext,
 		DestinationCtx: destContext,
 
@@ -267,13 +1018,67 @@ func (o *PushOptions) pushImage(ctx context.Context, srcRef types.ImageReference
 
 		RemoveSignatures: true,
 
-		ReportWriter: os.Stdout,
+		ReportWriter: reportWriter,
+	}
+}
+
+// pushRetryBackoff is the backoff between retries of failed pushes
+var pushRetryBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Cap: 30 * time.Second, Steps: math.MaxInt32}
+
+// withRetries calls push until it succeeds, returns an error that is not retriable or failed Retries times
+func (o *PushOptions) withRetries(ctx context.Context, image string, push func(ctx context.Context) error) error {
+	attempt := 0
+	var pushErr error
+	err := wait.ExponentialBackoffWithContext(ctx, pushRetryBackoff, func(ctx context.Context) (bool, error) {
+		pushErr = push(ctx)
+		if pushErr == nil {
+			return true, nil
+		} else if attempt >= o.Retries || !isRetriablePushError(pushErr) {
+			return false, pushErr
+		}
+
+		attempt++
+		o.Log.Warnf("Failed to push %s, retrying (%d/%d): %v", image, attempt, o.Retries, pushErr)
+		return false, nil
 	})
-	if err != nil {
-		return fmt.Errorf("failed to copy image: %w", err)
+	if err != nil && pushErr != nil && !errors.Is(err, pushErr) {
+		// the context was canceled while waiting for the next attempt
+		return fmt.Errorf("%w: %w", err, pushErr)
 	}
 
-	return nil
+	return err
+}
+
+// isRetriablePushError returns true if the push failed because of a network error or a transient
+// registry error, such as a 5xx status code or too many requests
+func isRetriablePushError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	} else if errors.Is(err, docker.ErrTooManyRequests) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	unexpectedStatusErr := docker.UnexpectedHTTPStatusError{}
+	if errors.As(err, &unexpectedStatusErr) {
+		return isRetriableStatusCode(unexpectedStatusErr.StatusCode)
+	}
+
+	transportErr := &transport.Error{}
+	if errors.As(err, &transportErr) {
+		return transportErr.Temporary()
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func isRetriableStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
 }
 
 func isRegistryEnabled(ctx context.Context, restConfig *rest.Config) (bool, error) {
@@ -305,6 +1110,19 @@ func runCommand(ctx context.Context, args ...string) error {
 }
 
 func getConfig(ctx context.Context, flags *flags.GlobalFlags) (*rest.Config, error) {
+	restConfig, err := loadConfig(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureRegistryEnabled(ctx, restConfig); err != nil {
+		return nil, err
+	}
+
+	return restConfig, nil
+}
+
+func loadConfig(flags *flags.GlobalFlags) (*rest.Config, error) {
 	// first load the kube config
 	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
 		CurrentContext: flags.Context,
@@ -316,13 +1134,17 @@ func getConfig(ctx context.Context, flags *flags.GlobalFlags) (*rest.Config, err
 		return nil, fmt.Errorf("failed to get client config: %w", err)
 	}
 
-	// check if registry is enabled
+	return restConfig, nil
+}
+
+// ensureRegistryEnabled returns an error if the registry of the virtual cluster is not enabled
+func ensureRegistryEnabled(ctx context.Context, restConfig *rest.Config) error {
 	registryEnabled, err := isRegistryEnabled(ctx, restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if registry is enabled: %w", err)
+		return fmt.Errorf("failed to check if registry is enabled: %w", err)
 	} else if !registryEnabled {
-		return nil, fmt.Errorf("vCluster registry is not enabled or the target cluster is not a vCluster. Please make sure to enable the registry in the vCluster config and run `vcluster connect` to connect to the vCluster before pushing images")
+		return fmt.Errorf("vCluster registry is not enabled or the target cluster is not a vCluster. Please make sure to enable the registry in the vCluster config and run `vcluster connect` to connect to the vCluster before pushing images")
 	}
 
-	return restConfig, nil
+	return nil
 }
@@ -0,0 +1,835 @@
+package registry
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/loft-sh/image/copy"
+	"github.com/loft-sh/image/docker"
+	"github.com/loft-sh/log"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+)
+
+const helmChartConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
+
+// writeOCIArchive writes an oci-archive with a single manifest that uses the given config media type
+func writeOCIArchive(t *testing.T, path, configMediaType string) {
+	t.Helper()
+
+	blobs := map[digest.Digest][]byte{}
+	addBlob := func(mediaType string, content []byte) imgspecv1.Descriptor {
+		dgst := digest.FromBytes(content)
+		blobs[dgst] = content
+		return imgspecv1.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(content))}
+	}
+	mustMarshal := func(obj interface{}) []byte {
+		out, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return out
+	}
+
+	manifest := mustMarshal(&imgspecv1.Manifest{
+		Versioned: imgspecs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config:    addBlob(configMediaType, []byte(`{"name":"my-chart","version":"1.0.0"}`)),
+		Layers:    []imgspecv1.Descriptor{addBlob("application/vnd.cncf.helm.chart.content.v1.tar+gzip", []byte("chart"))},
+	})
+	files := map[string][]byte{
+		imgspecv1.ImageLayoutFile: mustMarshal(&imgspecv1.ImageLayout{Version: imgspecv1.ImageLayoutVersion}),
+		"index.json": mustMarshal(&imgspecv1.Index{
+			Versioned: imgspecs.Versioned{SchemaVersion: 2},
+			MediaType: imgspecv1.MediaTypeImageIndex,
+			Manifests: []imgspecv1.Descriptor{addBlob(imgspecv1.MediaTypeImageManifest, manifest)},
+		}),
+	}
+	for dgst, content := range blobs {
+		files["blobs/sha256/"+dgst.Encoded()] = content
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create archive: %v", err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close archive: %v", err)
+	}
+}
+
+func TestDetectArtifactType(t *testing.T) {
+	tempDir := t.TempDir()
+	helmChart := filepath.Join(tempDir, "charts_my-chart+1.0.0.tar")
+	writeOCIArchive(t, helmChart, helmChartConfigMediaType)
+	image := filepath.Join(tempDir, "library_nginx+latest.tar")
+	writeOCIArchive(t, image, imgspecv1.MediaTypeImageConfig)
+
+	artifactType, err := detectArtifactType(helmChart)
+	if err != nil {
+		t.Fatalf("detectArtifactType() error = %v", err)
+	} else if artifactType != helmChartConfigMediaType {
+		t.Fatalf("detectArtifactType() = %q, want %q", artifactType, helmChartConfigMediaType)
+	}
+
+	artifactType, err = detectArtifactType(image)
+	if err != nil {
+		t.Fatalf("detectArtifactType() error = %v", err)
+	} else if artifactType != "" {
+		t.Fatalf("expected no artifact type for a container image, got %q", artifactType)
+	}
+
+	// gzip compressed archives are read without extracting them
+	content, err := os.ReadFile(helmChart)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	compressed := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(compressed)
+	if _, err := gzipWriter.Write(content); err != nil {
+		t.Fatalf("write gzip: %v", err)
+	} else if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("close gzip writer: %v", err)
+	}
+	tgz := filepath.Join(tempDir, "charts_my-chart+1.0.0.tgz")
+	if err := os.WriteFile(tgz, compressed.Bytes(), 0644); err != nil {
+		t.Fatalf("write tgz: %v", err)
+	}
+	artifactType, err = detectArtifactType(tgz)
+	if err != nil {
+		t.Fatalf("detectArtifactType() error = %v", err)
+	} else if artifactType != helmChartConfigMediaType {
+		t.Fatalf("detectArtifactType() = %q, want %q", artifactType, helmChartConfigMediaType)
+	}
+}
+
+func TestCopyOptionsArtifact(t *testing.T) {
+	o := &PushOptions{Architecture: "arm64"}
+
+	options := o.copyOptions(false, true, io.Discard)
+	if !options.PreserveDigests || options.ImageListSelection != copy.CopyAllImages {
+		t.Fatalf("artifacts must be copied unmodified")
+	}
+	if options.SourceCtx.ArchitectureChoice != "" || options.SourceCtx.OSChoice != "" {
+		t.Fatalf("artifacts must not select a platform, got %s/%s", options.SourceCtx.OSChoice, options.SourceCtx.ArchitectureChoice)
+	}
+
+	options = o.copyOptions(false, false, io.Discard)
+	if options.PreserveDigests || options.ImageListSelection != copy.CopySystemImage || options.SourceCtx.ArchitectureChoice != "arm64" {
+		t.Fatalf("unexpected image copy options %+v", options)
+	}
+}
+
+// fakeRegistry is a minimal in memory registry that supports pushing, pulling and deleting manifests
+type fakeRegistry struct {
+	mutex     sync.Mutex
+	blobs     map[string][]byte
+	uploads   map[string][]byte
+	manifests map[string][]byte
+	deleted   []string
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		blobs:     map[string][]byte{},
+		uploads:   map[string][]byte{},
+		manifests: map[string][]byte{},
+	}
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if r.URL.Path == "/v2/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case strings.HasSuffix(path, "/tags/list"):
+		repository := strings.TrimSuffix(path, "/tags/list")
+		tags := []string{}
+		for reference := range f.manifests {
+			if tag, ok := strings.CutPrefix(reference, repository+":"); ok && !strings.HasPrefix(tag, "sha256:") {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		slices.Sort(tags)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": repository, "tags": tags})
+	case strings.Contains(path, "/blobs/uploads/"):
+		repository, uploadID, _ := strings.Cut(path, "/blobs/uploads/")
+		switch r.Method {
+		case http.MethodPost:
+			uploadID = strconv.Itoa(len(f.uploads))
+			f.uploads[uploadID] = nil
+			w.Header().Set("Location", "/v2/"+repository+"/blobs/uploads/"+uploadID)
+			w.Header().Set("Range", "0-0")
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPatch:
+			f.uploads[uploadID] = append(f.uploads[uploadID], body...)
+			w.Header().Set("Location", "/v2/"+repository+"/blobs/uploads/"+uploadID)
+			w.Header().Set("Range", fmt.Sprintf("0-%d", len(f.uploads[uploadID])-1))
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPut:
+			dgst := r.URL.Query().Get("digest")
+			f.blobs[repository+"@"+dgst] = append(f.uploads[uploadID], body...)
+			w.Header().Set("Docker-Content-Digest", dgst)
+			w.WriteHeader(http.StatusCreated)
+		}
+	case strings.Contains(path, "/blobs/"):
+		repository, dgst, _ := strings.Cut(path, "/blobs/")
+		blob, ok := f.blobs[repository+"@"+dgst]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+		w.Header().Set("Docker-Content-Digest", dgst)
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(blob)
+		}
+	case strings.Contains(path, "/manifests/"):
+		repository, reference, _ := strings.Cut(path, "/manifests/")
+		switch r.Method {
+		case http.MethodPut:
+			dgst := digest.FromBytes(body).String()
+			f.manifests[repository+"@"+dgst] = body
+			f.manifests[repository+":"+reference] = body
+			w.Header().Set("Docker-Content-Digest", dgst)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			if _, ok := f.manifests[repository+"@"+reference]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(f.manifests, repository+"@"+reference)
+			f.deleted = append(f.deleted, repository+"@"+reference)
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodGet, http.MethodHead:
+			separator := ":"
+			if strings.HasPrefix(reference, "sha256:") {
+				separator = "@"
+			}
+			manifest, ok := f.manifests[repository+separator+reference]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			mediaType := struct {
+				MediaType string `json:"mediaType"`
+			}{}
+			_ = json.Unmarshal(manifest, &mediaType)
+			w.Header().Set("Content-Type", mediaType.MediaType)
+			w.Header().Set("Content-Length", strconv.Itoa(len(manifest)))
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+			w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(manifest)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestPushTransactionalRollback(t *testing.T) {
+	registry := newFakeRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse server url: %v", err)
+	}
+	localPort, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("parse server port: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	first := filepath.Join(tempDir, "registry_first+1.0.0.tar")
+	writeOCIArchive(t, first, helmChartConfigMediaType)
+	second := filepath.Join(tempDir, "registry_second+1.0.0.tar")
+	writeOCIArchive(t, second, helmChartConfigMediaType)
+	third := filepath.Join(tempDir, "registry_third+1.0.0.tar")
+	writeOCIArchive(t, third, helmChartConfigMediaType)
+
+	// the image of the third archive already exists under another tag
+	o := &PushOptions{Archives: []string{filepath.Join(tempDir, "registry_third+0.9.0.tar")}, Log: log.Discard}
+	writeOCIArchive(t, o.Archives[0], helmChartConfigMediaType)
+	if err := o.pushImagesAndArchives(context.Background(), localPort); err != nil {
+		t.Fatalf("push existing image: %v", err)
+	}
+
+	// the last archive does not exist, so the push fails after the others were pushed
+	o = &PushOptions{
+		Transactional: true,
+		Archives:      []string{first, second, third, filepath.Join(tempDir, "registry_missing+1.0.0.tar")},
+		Log:           log.Discard,
+	}
+	err = o.pushImagesAndArchives(context.Background(), localPort)
+	if err == nil || !strings.Contains(err.Error(), "failed to stat archive") {
+		t.Fatalf("expected push to fail on the missing archive, got %v", err)
+	}
+
+	if len(registry.deleted) != 2 {
+		t.Fatalf("expected the first two pushed archives to be rolled back, got %v", registry.deleted)
+	}
+	if !strings.HasPrefix(registry.deleted[0], "second@sha256:") || !strings.HasPrefix(registry.deleted[1], "first@sha256:") {
+		t.Fatalf("expected pushes to be rolled back in reverse order, got %v", registry.deleted)
+	}
+	for reference := range registry.manifests {
+		if strings.Contains(reference, "@") && !strings.HasPrefix(reference, "third@") {
+			t.Fatalf("expected no new manifests to be left after the rollback, found %s", reference)
+		}
+	}
+	thirdDigest := digest.FromBytes(registry.manifests["third:0.9.0"]).String()
+	if _, ok := registry.manifests["third@"+thirdDigest]; !ok {
+		t.Fatalf("expected the image that existed before the push to be kept, got %v", registry.manifests)
+	}
+
+	// the rollback also runs if the push was cancelled
+	registry.deleted = nil
+	o = &PushOptions{
+		Log:        log.Discard,
+		pushed:     []string{fmt.Sprintf("127.0.0.1:%d/third@%s", localPort, thirdDigest)},
+		pushedTags: []string{fmt.Sprintf("127.0.0.1:%d/third:0.9.0", localPort), fmt.Sprintf("127.0.0.1:%d/third:1.0.0", localPort)},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := o.rollback(ctx); err != nil {
+		t.Fatalf("rollback with cancelled context: %v", err)
+	} else if len(registry.deleted) != 1 || registry.deleted[0] != "third@"+thirdDigest {
+		t.Fatalf("expected the pushed archive to be rolled back, got %v", registry.deleted)
+	}
+
+	// without --transactional the pushed archives are kept
+	registry.deleted = nil
+	o = &PushOptions{
+		Archives: []string{first, filepath.Join(tempDir, "registry_missing+1.0.0.tar")},
+		Log:      log.Discard,
+	}
+	if err := o.pushImagesAndArchives(context.Background(), localPort); err == nil {
+		t.Fatalf("expected push to fail on the missing archive")
+	} else if len(registry.deleted) != 0 {
+		t.Fatalf("expected no rollback without --transactional, got %v", registry.deleted)
+	}
+}
+
+func TestPushDockerDaemon(t *testing.T) {
+	registry := newFakeRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse server url: %v", err)
+	}
+	localPort, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("parse server port: %v", err)
+	}
+
+	// the fake daemon exports an empty image in the docker save format
+	exported := []string{}
+	daemonServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/_ping":
+			w.Header().Set("API-Version", "1.47")
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/images/get"):
+			image := r.URL.Query().Get("names")
+			exported = append(exported, image)
+			tag, err := name.NewTag(image)
+			if err != nil {
+				t.Errorf("parse tag %s: %v", image, err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if err := tarball.Write(tag, empty.Image, w); err != nil {
+				t.Errorf("write image: %v", err)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer daemonServer.Close()
+
+	daemon := newFakeDockerDaemon(t, daemonServer)
+	if err := daemon.ping(context.Background()); err != nil {
+		t.Fatalf("ping() error = %v", err)
+	}
+
+	o := &PushOptions{Transactional: true, Log: log.Discard}
+	err = o.pushDaemonImage(context.Background(), daemon, "nginx:1.27", localPort)
+	if err != nil {
+		t.Fatalf("pushDaemonImage() error = %v", err)
+	}
+
+	if len(exported) != 1 || exported[0] != "nginx:1.27" {
+		t.Fatalf("expected nginx:1.27 to be exported from the daemon, got %v", exported)
+	}
+	if _, ok := registry.manifests["library/nginx:1.27"]; !ok {
+		t.Fatalf("expected library/nginx:1.27 to be pushed, got %v", registry.manifests)
+	}
+	if len(o.pushed) != 1 || !strings.HasPrefix(o.pushed[0], serverURL.Host+"/library/nginx@sha256:") {
+		t.Fatalf("expected the pushed image to be remembered by digest, got %v", o.pushed)
+	}
+
+	err = o.pushDaemonImage(context.Background(), daemon, "nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000", localPort)
+	if err == nil || !strings.Contains(err.Error(), "by digest") {
+		t.Fatalf("expected pushing by digest to fail, got %v", err)
+	}
+
+	// pushing an image that already exists is not rolled back
+	o = &PushOptions{Transactional: true, Log: log.Discard}
+	if err := o.pushDaemonImage(context.Background(), daemon, "nginx:1.27", localPort); err != nil {
+		t.Fatalf("pushDaemonImage() error = %v", err)
+	} else if len(o.pushed) != 0 {
+		t.Fatalf("expected the existing image not to be remembered, got %v", o.pushed)
+	}
+	if err := o.pushDaemonImage(context.Background(), daemon, "nginx:1.28", localPort); err != nil {
+		t.Fatalf("pushDaemonImage() error = %v", err)
+	} else if len(o.pushed) != 0 {
+		t.Fatalf("expected the existing manifest pushed under a new tag not to be remembered, got %v", o.pushed)
+	}
+	if err := o.rollback(context.Background()); err != nil {
+		t.Fatalf("rollback() error = %v", err)
+	} else if len(registry.deleted) != 0 {
+		t.Fatalf("expected the existing image to be kept, got %v", registry.deleted)
+	}
+	if _, ok := registry.manifests["library/nginx:1.27"]; !ok {
+		t.Fatalf("expected library/nginx:1.27 to be kept, got %v", registry.manifests)
+	}
+}
+
+func TestExportImageRemovesPartialFile(t *testing.T) {
+	// the fake daemon announces a larger image than it sends and then drops the connection
+	daemonServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/images/get") {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Length", "1024")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack connection: %v", err)
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer daemonServer.Close()
+
+	dir := t.TempDir()
+	_, err := newFakeDockerDaemon(t, daemonServer).exportImage(context.Background(), "nginx:1.27", dir)
+	if err == nil {
+		t.Fatalf("expected exportImage() to fail")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the partial image to be removed, got %v", entries)
+	}
+}
+
+// newFakeDockerDaemon returns a docker daemon client that talks to server, ignoring the docker environment
+func newFakeDockerDaemon(t *testing.T, server *httptest.Server) *dockerDaemon {
+	for _, env := range []string{"DOCKER_HOST", "DOCKER_TLS_VERIFY", "DOCKER_CERT_PATH", "DOCKER_API_VERSION"} {
+		t.Setenv(env, "")
+	}
+
+	daemon, err := newDockerDaemon(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("newDockerDaemon() error = %v", err)
+	}
+
+	return daemon
+}
+
+func TestNewDockerDaemonSSH(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "ssh://user@remote")
+	if _, err := newDockerDaemon(); err == nil || !strings.Contains(err.Error(), "ssh docker hosts require the docker cli") {
+		t.Fatalf("expected ssh docker hosts to be rejected, got %v", err)
+	}
+}
+
+func TestDockerDaemonLoadImage(t *testing.T) {
+	loaded := 0
+	daemonServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/_ping":
+			w.Header().Set("API-Version", "1.47")
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/images/load"):
+			loaded++
+			w.Header().Set("Content-Type", "application/json")
+			if loaded == 1 {
+				_, _ = w.Write([]byte(`{"stream":"Loaded image: nginx:1.27\n"}`))
+			} else {
+				_, _ = w.Write([]byte(`{"errorDetail":{"message":"invalid tar"},"error":"invalid tar"}`))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer daemonServer.Close()
+
+	daemon := newFakeDockerDaemon(t, daemonServer)
+	out := &bytes.Buffer{}
+	if err := daemon.loadImage(context.Background(), strings.NewReader("image"), out); err != nil {
+		t.Fatalf("loadImage() error = %v", err)
+	} else if out.String() != "Loaded image: nginx:1.27\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	if err := daemon.loadImage(context.Background(), strings.NewReader("image"), out); err == nil || !strings.Contains(err.Error(), "invalid tar") {
+		t.Fatalf("expected the daemon error to be returned, got %v", err)
+	}
+}
+
+func TestDestinationReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   *PushOptions
+		imageName string
+		expected  string
+		err       string
+	}{
+		{
+			name:      "registry only",
+			options:   &PushOptions{},
+			imageName: "docker.io/library/nginx:1.25",
+			expected:  "127.0.0.1:5000/library/nginx:1.25",
+		},
+		{
+			name:      "tag",
+			options:   &PushOptions{Tag: "internal/nginx:stable"},
+			imageName: "docker.io/library/nginx:1.25",
+			expected:  "127.0.0.1:5000/internal/nginx:stable",
+		},
+		{
+			name:      "tag keeps original tag",
+			options:   &PushOptions{Tag: "internal/nginx"},
+			imageName: "docker.io/library/nginx:1.25",
+			expected:  "127.0.0.1:5000/internal/nginx:1.25",
+		},
+		{
+			name:      "tag keeps original digest",
+			options:   &PushOptions{Tag: "internal/nginx"},
+			imageName: "docker.io/library/nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			expected:  "127.0.0.1:5000/internal/nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			name:      "repository prefix",
+			options:   &PushOptions{RepositoryPrefix: "/internal/"},
+			imageName: "docker.io/library/nginx:1.25",
+			expected:  "127.0.0.1:5000/internal/library/nginx:1.25",
+		},
+		{
+			name:      "invalid rewrite",
+			options:   &PushOptions{Tag: "Internal/NGINX"},
+			imageName: "docker.io/library/nginx:1.25",
+			err:       "invalid reference 127.0.0.1:5000/Internal/NGINX:1.25",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			destImageName, err := test.options.destinationReference(test.imageName, 5000)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("destinationReference() error = %v", err)
+			}
+
+			if destImageName != test.expected {
+				t.Fatalf("destinationReference() = %s, want %s", destImageName, test.expected)
+			}
+		})
+	}
+}
+
+func TestPushArchivesParallel(t *testing.T) {
+	registry := newFakeRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse server url: %v", err)
+	}
+	localPort, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("parse server port: %v", err)
+	}
+
+	archiveDir := t.TempDir()
+	for i := range 5 {
+		writeOCIArchive(t, filepath.Join(archiveDir, fmt.Sprintf("registry_chart-%d+1.0.0.tar", i)), helmChartConfigMediaType)
+	}
+
+	o := &PushOptions{Archives: []string{archiveDir}, Parallel: 3, Transactional: true, Log: log.Discard}
+	if err := o.pushImagesAndArchives(context.Background(), localPort); err != nil {
+		t.Fatalf("pushImagesAndArchives() error = %v", err)
+	}
+	for i := range 5 {
+		if _, ok := registry.manifests[fmt.Sprintf("chart-%d:1.0.0", i)]; !ok {
+			t.Fatalf("expected chart-%d to be pushed, got %v", i, registry.manifests)
+		}
+	}
+	if len(o.pushed) != 5 {
+		t.Fatalf("expected all 5 pushed images to be remembered, got %v", o.pushed)
+	}
+
+	// a broken archive fails the push and its error is returned
+	if err := os.WriteFile(filepath.Join(archiveDir, "registry_broken+1.0.0.tar"), []byte("not a tar"), 0644); err != nil {
+		t.Fatalf("write broken archive: %v", err)
+	}
+	o = &PushOptions{Archives: []string{archiveDir}, Parallel: 3, Log: log.Discard}
+	err = o.pushImagesAndArchives(context.Background(), localPort)
+	if err == nil || !strings.Contains(err.Error(), "registry_broken+1.0.0.tar") {
+		t.Fatalf("expected the push of the broken archive to fail, got %v", err)
+	}
+}
+
+func TestIsRetriablePushError(t *testing.T) {
+	tests := map[string]struct {
+		err       error
+		retriable bool
+	}{
+		"service unavailable": {err: fmt.Errorf("writing blob: %w", docker.UnexpectedHTTPStatusError{StatusCode: http.StatusServiceUnavailable}), retriable: true},
+		"too many requests":   {err: docker.ErrTooManyRequests, retriable: true},
+		"connection reset":    {err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, retriable: true},
+		"unexpected eof":      {err: fmt.Errorf("reading blob: %w", io.ErrUnexpectedEOF), retriable: true},
+		"transport 502":       {err: &transport.Error{StatusCode: http.StatusBadGateway}, retriable: true},
+		"unauthorized":        {err: docker.ErrUnauthorizedForCredentials{Err: errors.New("denied")}},
+		"not found":           {err: docker.UnexpectedHTTPStatusError{StatusCode: http.StatusNotFound}},
+		"transport 401":       {err: &transport.Error{StatusCode: http.StatusUnauthorized}},
+		"canceled":            {err: context.Canceled},
+	}
+
+	for name, test := range tests {
+		if retriable := isRetriablePushError(test.err); retriable != test.retriable {
+			t.Fatalf("%s: isRetriablePushError(%v) = %v, want %v", name, test.err, retriable, test.retriable)
+		}
+	}
+}
+
+func TestWithRetries(t *testing.T) {
+	defer func(backoff wait.Backoff) { pushRetryBackoff = backoff }(pushRetryBackoff)
+	pushRetryBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: math.MaxInt32}
+
+	o := &PushOptions{Retries: 2, Log: log.Discard}
+	attempts := 0
+	err := o.withRetries(context.Background(), "nginx", func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return docker.UnexpectedHTTPStatusError{StatusCode: http.StatusBadGateway}
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected push to succeed on the third attempt, got %d attempts and %v", attempts, err)
+	}
+
+	attempts = 0
+	err = o.withRetries(context.Background(), "nginx", func(context.Context) error {
+		attempts++
+		return docker.UnexpectedHTTPStatusError{StatusCode: http.StatusBadGateway}
+	})
+	if err == nil || attempts != 3 {
+		t.Fatalf("expected push to fail after 3 attempts, got %d attempts and %v", attempts, err)
+	}
+
+	attempts = 0
+	unauthorized := docker.ErrUnauthorizedForCredentials{Err: errors.New("denied")}
+	err = o.withRetries(context.Background(), "nginx", func(context.Context) error {
+		attempts++
+		return unauthorized
+	})
+	if !errors.Is(err, unauthorized) || attempts != 1 {
+		t.Fatalf("expected unauthorized push to fail immediately, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestPushArchiveFormats(t *testing.T) {
+	registry := newFakeRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse server url: %v", err)
+	}
+	localPort, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("parse server port: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	archive := filepath.Join(tempDir, "archive.tar")
+	writeOCIArchive(t, archive, helmChartConfigMediaType)
+	content, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+
+	// gzip the archive as .tgz
+	archiveDir := filepath.Join(tempDir, "archives")
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		t.Fatalf("create archive dir: %v", err)
+	}
+	tgz, err := os.Create(filepath.Join(archiveDir, "registry_tgz+1.0.0.tgz"))
+	if err != nil {
+		t.Fatalf("create tgz: %v", err)
+	}
+	gzipWriter := gzip.NewWriter(tgz)
+	if _, err := gzipWriter.Write(content); err != nil {
+		t.Fatalf("write tgz: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("close gzip writer: %v", err)
+	}
+	tgz.Close()
+
+	// extract the archive as OCI layout directory
+	layoutDir := filepath.Join(archiveDir, "registry_layout+1.0.0")
+	tarReader := tar.NewReader(bytes.NewReader(content))
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("read archive: %v", err)
+		}
+
+		if err := os.MkdirAll(filepath.Join(layoutDir, filepath.Dir(header.Name)), 0755); err != nil {
+			t.Fatalf("create dir: %v", err)
+		}
+		file, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatalf("read file: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(layoutDir, header.Name), file, 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+
+	// unsupported files in the directory are skipped
+	if err := os.WriteFile(filepath.Join(archiveDir, "README.md"), []byte("readme"), 0644); err != nil {
+		t.Fatalf("write readme: %v", err)
+	}
+
+	o := &PushOptions{Archives: []string{archiveDir}, Log: log.Discard}
+	if err := o.pushImagesAndArchives(context.Background(), localPort); err != nil {
+		t.Fatalf("pushImagesAndArchives() error = %v", err)
+	}
+	for _, reference := range []string{"tgz:1.0.0", "layout:1.0.0"} {
+		if _, ok := registry.manifests[reference]; !ok {
+			t.Fatalf("expected %s to be pushed, got %v", reference, registry.manifests)
+		}
+	}
+
+	// unsupported files are rejected if passed directly
+	o = &PushOptions{Archives: []string{filepath.Join(archiveDir, "README.md")}, Log: log.Discard}
+	err = o.pushImagesAndArchives(context.Background(), localPort)
+	if err == nil || !strings.Contains(err.Error(), supportedArchiveFormats) {
+		t.Fatalf("expected unsupported archive error listing the supported formats, got %v", err)
+	}
+}
+
+func TestPushImages(t *testing.T) {
+	registry := newFakeRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	archive := filepath.Join(t.TempDir(), "registry_my-chart+1.0.0.tar")
+	writeOCIArchive(t, archive, helmChartConfigMediaType)
+
+	progressMutex := sync.Mutex{}
+	progress := map[string][]string{}
+	err := PushImages(context.Background(), &rest.Config{Host: server.URL}, nil, PushImagesOptions{
+		Archives: []string{archive},
+		Progress: func(image, line string) {
+			progressMutex.Lock()
+			defer progressMutex.Unlock()
+			progress[image] = append(progress[image], line)
+		},
+	})
+	if err != nil {
+		t.Fatalf("PushImages() error = %v", err)
+	}
+
+	if _, ok := registry.manifests["my-chart:1.0.0"]; !ok {
+		t.Fatalf("expected my-chart:1.0.0 to be pushed, got %v", registry.manifests)
+	}
+	if len(progress[archive]) == 0 || !strings.HasPrefix(progress[archive][len(progress[archive])-1], "Writing manifest") {
+		t.Fatalf("expected the copy progress to be passed to the callback, got %v", progress)
+	}
+
+	err = PushImages(context.Background(), &rest.Config{Host: server.URL}, nil, PushImagesOptions{})
+	if err == nil || !strings.Contains(err.Error(), "either an image or an archive is required") {
+		t.Fatalf("expected PushImages without images to fail, got %v", err)
+	}
+}
@@ -18,6 +18,7 @@ func NewRegistryCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 
 	registryCmd.AddCommand(NewPushCmd(globalFlags))
 	registryCmd.AddCommand(NewPullCmd(globalFlags))
+	registryCmd.AddCommand(NewListCmd(globalFlags))
 	registryCmd.AddCommand(NewProxyCmd(globalFlags))
 	return registryCmd
 }
@@ -974,6 +974,10 @@ func ValidateNamespaceSyncChanges(oldCfg, newCfg *Config) error {
 		return fmt.Errorf("sync.toHost.namespaces.mappingsOnly is not allowed to be changed")
 	}
 
+	if oldNamespaceConf.HostNamePrefix != newNamespaceConf.HostNamePrefix {
+		return fmt.Errorf("sync.toHost.namespaces.hostNamePrefix is not allowed to be changed")
+	}
+
 	if !reflect.DeepEqual(oldNamespaceConf.Mappings.ByName, newNamespaceConf.Mappings.ByName) {
 		return fmt.Errorf("sync.toHost.namespaces.mappings.byName is not allowed to be changed")
 	}
@@ -1380,6 +1384,11 @@ type SyncToHostNamespaces struct {
 	// MappingsOnly defines if creation of namespaces not matched by mappings should be allowed.
 	MappingsOnly bool `json:"mappingsOnly,omitempty"`
 
+	// HostNamePrefix is prepended to every host namespace and host namespace pattern in mappings.byName, e.g. with
+	// the prefix team-a- the mapping foo: bar syncs the virtual namespace foo to the host namespace team-a-bar.
+	// Host namespaces without the prefix are never matched by the mappings.
+	HostNamePrefix string `json:"hostNamePrefix,omitempty"`
+
 	// ExtraLabels are additional labels to add to the namespace in the host cluster.
 	ExtraLabels map[string]string `json:"extraLabels,omitempty"`
 }
@@ -1495,6 +1504,10 @@ type SyncPods struct {
 	// a certain image that is used within the virtual cluster to be another image on the host cluster
 	TranslateImage map[string]string `json:"translateImage,omitempty"`
 
+	// TranslateTokenAudiences maps an audience of a projected service account token to another audience that should be used
+	// instead. This can be used if the virtual cluster and host cluster api servers expect different token audiences.
+	TranslateTokenAudiences map[string]string `json:"translateTokenAudiences,omitempty"`
+
 	// EnforceTolerations will add the specified tolerations to all pods synced by the virtual cluster.
 	EnforceTolerations []string `json:"enforceTolerations,omitempty"`
 
@@ -1502,6 +1515,14 @@ type SyncPods struct {
 	// pod annotation.
 	UseSecretsForSATokens bool `json:"useSecretsForSATokens,omitempty"`
 
+	// InjectVirtualMetadataEnv replaces environment variables that reference the metadata.name, metadata.namespace or metadata.uid
+	// of the pod via fieldRef with the literal virtual values, instead of reading them from annotations of the host pod.
+	InjectVirtualMetadataEnv bool `json:"injectVirtualMetadataEnv,omitempty"`
+
+	// ClearVirtualNodeNames clears the nodeName of synced pods that are pinned to a node that doesn't exist in the host cluster,
+	// so the host scheduler decides where the pod is placed.
+	ClearVirtualNodeNames bool `json:"clearVirtualNodeNames,omitempty"`
+
 	// RuntimeClassName is the runtime class to set for synced pods.
 	RuntimeClassName string `json:"runtimeClassName,omitempty"`
 
@@ -3195,6 +3216,30 @@ type ExperimentalSyncSettings struct {
 
 	// VirtualMetricsBindAddress is the bind address for the virtual manager
 	VirtualMetricsBindAddress string `json:"virtualMetricsBindAddress,omitempty"`
+
+	// UseAnnotationsForTopology stores the vCluster marker of synced host objects in an annotation instead of a label and
+	// omits the namespace label. This is useful for host clusters that restrict the labels objects can have. Namespaced
+	// host objects still get a single hashed scope label, so selectors only match objects of the same virtual namespace.
+	// Admission webhooks can't be synced in this mode.
+	UseAnnotationsForTopology bool `json:"useAnnotationsForTopology,omitempty"`
+
+	// PreserveHostAnnotations are annotation keys on synced host objects that are owned by the host cluster, e.g. annotations
+	// injected by a service mesh. vCluster keeps them on updates and never syncs them from or to the virtual object.
+	PreserveHostAnnotations []string `json:"preserveHostAnnotations,omitempty"`
+
+	// SyncLabels is an allowlist of label keys that are synced from virtual objects to their host objects. If set, all other
+	// labels stay on the virtual object only. The labels vCluster uses to track host objects are always set.
+	SyncLabels []string `json:"syncLabels,omitempty"`
+
+	// SaltHostNames mixes a random salt into the hashes of synced host object names, so that hashed host names cannot be
+	// guessed from the virtual names alone. The salt is generated once and stored in the secret vc-hash-salt-NAME
+	// in the vCluster namespace. Changing this on an existing vCluster changes the names of already synced host objects.
+	SaltHostNames bool `json:"saltHostNames,omitempty"`
+
+	// TranslateCompatibilityMode pins the version of the host name translation algorithm, so that an upgraded vCluster keeps
+	// the host names of already synced objects until they were migrated. Version 1 does not mix the salt of saltHostNames
+	// into host names. Defaults to the latest version.
+	TranslateCompatibilityMode int `json:"translateCompatibilityMode,omitempty"`
 }
 
 func (e ExperimentalSyncSettings) JSONSchemaExtend(base *jsonschema.Schema) {
@@ -0,0 +1,129 @@
+package certs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/kubernetes/cmd/kubeadm/app/util/pkiutil"
+)
+
+// CommonNames are the identities the control plane components authenticate with. Start from DefaultCommonNames
+// and override the identities that differ, e.g. to match the users of an external RBAC or auth setup.
+type CommonNames struct {
+	// ControllerManagerUser is the common name of the client certificate in controller-manager.conf
+	ControllerManagerUser string
+
+	// SchedulerUser is the common name of the client certificate in scheduler.conf
+	SchedulerUser string
+
+	// FrontProxyClient is the common name of front-proxy-client.crt
+	FrontProxyClient string
+}
+
+// DefaultCommonNames returns the identities kubeadm generates the certificates with
+func DefaultCommonNames() CommonNames {
+	return CommonNames{
+		ControllerManagerUser: ControllerManagerUser,
+		SchedulerUser:         SchedulerUser,
+		FrontProxyClient:      FrontProxyClientCertCommonName,
+	}
+}
+
+// Validate returns an error if a common name is empty
+func (c CommonNames) Validate() error {
+	if strings.TrimSpace(c.ControllerManagerUser) == "" {
+		return fmt.Errorf("controller manager user common name must not be empty")
+	}
+	if strings.TrimSpace(c.SchedulerUser) == "" {
+		return fmt.Errorf("scheduler user common name must not be empty")
+	}
+	if strings.TrimSpace(c.FrontProxyClient) == "" {
+		return fmt.Errorf("front proxy client common name must not be empty")
+	}
+
+	return nil
+}
+
+// ApplyCommonNames regenerates the front-proxy-client certificate and the client certificates of controller-manager.conf
+// and scheduler.conf in certDir if their common names differ from the given ones. The certificates are signed by
+// their existing CAs. Files that don't exist are skipped.
+func ApplyCommonNames(certDir string, commonNames CommonNames) error {
+	if err := commonNames.Validate(); err != nil {
+		return err
+	}
+
+	frontProxyClient := strings.TrimSuffix(FrontProxyClientCertName, ".crt")
+	if _, err := os.Stat(filepath.Join(certDir, FrontProxyClientCertName)); err == nil {
+		oldCert, err := pkiutil.TryLoadCertFromDisk(certDir, frontProxyClient)
+		if err != nil {
+			return fmt.Errorf("load certificate %s: %w", frontProxyClient, err)
+		}
+
+		if oldCert.Subject.CommonName != commonNames.FrontProxyClient {
+			caCert, caKey, err := pkiutil.TryLoadCertAndKeyFromDisk(certDir, strings.TrimSuffix(FrontProxyCACertName, ".crt"))
+			if err != nil {
+				return fmt.Errorf("load ca %s: %w", FrontProxyCACertName, err)
+			}
+
+			certConfig := renewedCertConfig(oldCert, caCert)
+			certConfig.CommonName = commonNames.FrontProxyClient
+			newCert, newKey, err := pkiutil.NewCertAndKey(caCert, caKey, certConfig)
+			if err != nil {
+				return fmt.Errorf("create certificate %s: %w", frontProxyClient, err)
+			}
+			err = pkiutil.WriteCertAndKey(certDir, frontProxyClient, newCert, newKey)
+			if err != nil {
+				return fmt.Errorf("write certificate %s: %w", frontProxyClient, err)
+			}
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for kubeConfigName, commonName := range map[string]string{
+		ControllerManagerKubeConfigFileName: commonNames.ControllerManagerUser,
+		SchedulerKubeConfigFileName:         commonNames.SchedulerUser,
+	} {
+		kubeConfigPath := filepath.Join(certDir, kubeConfigName)
+		if _, err := os.Stat(kubeConfigPath); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+		if err != nil {
+			return fmt.Errorf("load %s: %w", kubeConfigName, err)
+		}
+		upToDate := true
+		for userName, authInfo := range kubeConfig.AuthInfos {
+			if len(authInfo.ClientCertificateData) == 0 {
+				continue
+			}
+
+			clientCerts, err := certhelper.ParseCertsPEM(authInfo.ClientCertificateData)
+			if err != nil {
+				return fmt.Errorf("parse client certificate of user %s in %s: %w", userName, kubeConfigName, err)
+			}
+			upToDate = upToDate && clientCerts[0].Subject.CommonName == commonName
+		}
+		if upToDate {
+			continue
+		}
+
+		caCert, caKey, err := pkiutil.TryLoadCertAndKeyFromDisk(certDir, strings.TrimSuffix(CACertName, ".crt"))
+		if err != nil {
+			return fmt.Errorf("load ca %s: %w", CACertName, err)
+		}
+		err = rotateKubeConfigClientCerts(kubeConfigPath, &caKeyPair{cert: caCert, key: caKey}, commonName)
+		if err != nil {
+			return fmt.Errorf("rotate %s: %w", kubeConfigName, err)
+		}
+	}
+
+	return nil
+}
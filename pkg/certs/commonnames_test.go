@@ -0,0 +1,133 @@
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestApplyCommonNames(t *testing.T) {
+	certDir := t.TempDir()
+	kubeadmConfig, err := GenerateInitKubeadmConfig("10.96.0.0/12", certDir, testingutil.NewFakeConfig())
+	assert.NilError(t, err)
+	assert.NilError(t, generateCertificates(certDir, kubeadmConfig, nil))
+
+	readCert := func(pemBytes []byte) *x509.Certificate {
+		certs, err := certhelper.ParseCertsPEM(pemBytes)
+		assert.NilError(t, err)
+		return certs[0]
+	}
+	kubeConfigCommonName := func(kubeConfigName string) string {
+		kubeConfig, err := clientcmd.LoadFromFile(filepath.Join(certDir, kubeConfigName))
+		assert.NilError(t, err)
+		assert.Equal(t, len(kubeConfig.AuthInfos), 1)
+		for _, authInfo := range kubeConfig.AuthInfos {
+			clientCert := readCert(authInfo.ClientCertificateData)
+
+			roots := x509.NewCertPool()
+			caPEM, err := os.ReadFile(filepath.Join(certDir, CACertName))
+			assert.NilError(t, err)
+			roots.AddCert(readCert(caPEM))
+			_, err = clientCert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+			assert.NilError(t, err)
+			return clientCert.Subject.CommonName
+		}
+		return ""
+	}
+	frontProxyCommonName := func() string {
+		pemBytes, err := os.ReadFile(filepath.Join(certDir, FrontProxyClientCertName))
+		assert.NilError(t, err)
+		return readCert(pemBytes).Subject.CommonName
+	}
+
+	// the defaults match what kubeadm generates, so nothing is changed
+	oldControllerManager, err := os.ReadFile(filepath.Join(certDir, ControllerManagerKubeConfigFileName))
+	assert.NilError(t, err)
+	assert.NilError(t, ApplyCommonNames(certDir, DefaultCommonNames()))
+	newControllerManager, err := os.ReadFile(filepath.Join(certDir, ControllerManagerKubeConfigFileName))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, newControllerManager, oldControllerManager)
+	assert.Equal(t, kubeConfigCommonName(ControllerManagerKubeConfigFileName), ControllerManagerUser)
+	assert.Equal(t, kubeConfigCommonName(SchedulerKubeConfigFileName), SchedulerUser)
+	assert.Equal(t, frontProxyCommonName(), FrontProxyClientCertCommonName)
+
+	commonNames := DefaultCommonNames()
+	commonNames.ControllerManagerUser = "custom:controller-manager"
+	commonNames.SchedulerUser = "custom:scheduler"
+	commonNames.FrontProxyClient = "custom-front-proxy"
+	assert.NilError(t, ApplyCommonNames(certDir, commonNames))
+	assert.Equal(t, kubeConfigCommonName(ControllerManagerKubeConfigFileName), "custom:controller-manager")
+	assert.Equal(t, kubeConfigCommonName(SchedulerKubeConfigFileName), "custom:scheduler")
+	assert.Equal(t, frontProxyCommonName(), "custom-front-proxy")
+	assert.NilError(t, Validate(certDir))
+
+	commonNames.SchedulerUser = " "
+	assert.ErrorContains(t, ApplyCommonNames(certDir, commonNames), "scheduler user common name must not be empty")
+}
+
+func TestGenerateAllCertsCommonNames(t *testing.T) {
+	certDir := t.TempDir()
+	commonNames := DefaultCommonNames()
+	commonNames.FrontProxyClient = "custom-front-proxy"
+	err := GenerateAllCerts(certDir, CertConfig{
+		ServiceCIDR: "10.96.0.0/12",
+		Options:     testingutil.NewFakeConfig(),
+		CommonNames: &commonNames,
+	}, nil)
+	assert.NilError(t, err)
+
+	pemBytes, err := os.ReadFile(filepath.Join(certDir, FrontProxyClientCertName))
+	assert.NilError(t, err)
+	certs, err := certhelper.ParseCertsPEM(pemBytes)
+	assert.NilError(t, err)
+	assert.Equal(t, certs[0].Subject.CommonName, "custom-front-proxy")
+
+	err = GenerateAllCerts(t.TempDir(), CertConfig{
+		ServiceCIDR: "10.96.0.0/12",
+		Options:     testingutil.NewFakeConfig(),
+		CommonNames: &CommonNames{},
+	}, nil)
+	assert.ErrorContains(t, err, "common name must not be empty")
+}
+
+func TestGenerateWithCertConfigCommonNames(t *testing.T) {
+	certDir := t.TempDir()
+	commonNames := DefaultCommonNames()
+	commonNames.ControllerManagerUser = "custom:controller-manager"
+	commonNames.SchedulerUser = "custom:scheduler"
+	commonNames.FrontProxyClient = "custom-front-proxy"
+	certConfig := newEnsureCertsConfig()
+	certConfig.CommonNames = &commonNames
+	assert.NilError(t, GenerateWithCertConfig(context.Background(), certDir, certConfig))
+
+	readCert := func(pemBytes []byte) *x509.Certificate {
+		certs, err := certhelper.ParseCertsPEM(pemBytes)
+		assert.NilError(t, err)
+		return certs[0]
+	}
+	for kubeConfigName, commonName := range map[string]string{
+		ControllerManagerKubeConfigFileName: "custom:controller-manager",
+		SchedulerKubeConfigFileName:         "custom:scheduler",
+	} {
+		kubeConfig, err := clientcmd.LoadFromFile(filepath.Join(certDir, kubeConfigName))
+		assert.NilError(t, err)
+		for _, authInfo := range kubeConfig.AuthInfos {
+			assert.Equal(t, readCert(authInfo.ClientCertificateData).Subject.CommonName, commonName, kubeConfigName)
+		}
+	}
+	pemBytes, err := os.ReadFile(filepath.Join(certDir, FrontProxyClientCertName))
+	assert.NilError(t, err)
+	assert.Equal(t, readCert(pemBytes).Subject.CommonName, "custom-front-proxy")
+	assert.NilError(t, Validate(certDir))
+
+	certConfig = newEnsureCertsConfig()
+	certConfig.CommonNames = &CommonNames{}
+	assert.ErrorContains(t, GenerateWithCertConfig(context.Background(), t.TempDir(), certConfig), "common name must not be empty")
+}
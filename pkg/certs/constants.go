@@ -135,6 +135,8 @@ const (
 	// SystemPrivilegedGroup defines the well-known group for the apiservers. This group is also superuser by default
 	// (i.e. bound to the cluster-admin ClusterRole)
 	SystemPrivilegedGroup = "system:masters"
+	// AdminKubeConfigCommonName defines the user of break-glass admin kubeconfigs created by GenerateAdminKubeConfig
+	AdminKubeConfigCommonName = "vcluster:break-glass-admin"
 
 	// DefaultAPIServerBindAddress is the default bind address for the API Server
 	DefaultAPIServerBindAddress = "0.0.0.0"
@@ -45,17 +45,26 @@ const (
 )
 
 func Generate(ctx context.Context, serviceCIDR, certificatesDir string, options *config.VirtualClusterConfig) error {
-	currentNamespace := options.HostNamespace
-	currentNamespaceClient := options.HostClient
+	return GenerateWithCertConfig(ctx, certificatesDir, CertConfig{ServiceCIDR: serviceCIDR, Options: options})
+}
+
+// GenerateWithCertConfig is like Generate, but generates missing certificates with the given cert config
+func GenerateWithCertConfig(ctx context.Context, certificatesDir string, certConfig CertConfig) error {
+	if certConfig.CommonNames != nil {
+		if err := certConfig.CommonNames.Validate(); err != nil {
+			return err
+		}
+	}
 
 	// create kubeadm config
-	kubeadmConfig, err := GenerateInitKubeadmConfig(serviceCIDR, certificatesDir, options)
+	kubeadmConfig, err := GenerateInitKubeadmConfigWithCertConfig(certificatesDir, certConfig)
 	if err != nil {
 		return fmt.Errorf("create kubeadm config: %w", err)
 	}
 
 	// generate certificates
-	err = EnsureCerts(ctx, currentNamespace, currentNamespaceClient, certificatesDir, options, kubeadmConfig)
+	options := certConfig.Options
+	err = ensureCerts(ctx, options.HostNamespace, options.HostClient, certificatesDir, options, kubeadmConfig, certConfig.CommonNames)
 	if err != nil {
 		return fmt.Errorf("ensure certs: %w", err)
 	}
@@ -71,6 +80,30 @@ func GenerateInitKubeadmConfig(serviceCIDR, certificatesDir string, options *con
 	return kubeadm.InitKubeadmConfig(options, "", "127.0.0.1:6443", serviceCIDR, certificatesDir, extraSans)
 }
 
+// GenerateInitKubeadmConfigWithCertConfig is like GenerateInitKubeadmConfig, but also adds the APIServerSANs of
+// the cert config to the apiserver certificate and generates all keys with the KeyType of the cert config
+func GenerateInitKubeadmConfigWithCertConfig(certificatesDir string, certConfig CertConfig) (*kubeadmapi.InitConfiguration, error) {
+	if certConfig.Options == nil {
+		return nil, fmt.Errorf("virtual cluster config is required")
+	}
+	if err := certConfig.APIServerSANs.Validate(); err != nil {
+		return nil, err
+	}
+	encryptionAlgorithm, err := certConfig.KeyType.EncryptionAlgorithm()
+	if err != nil {
+		return nil, err
+	}
+
+	kubeadmConfig, err := GenerateInitKubeadmConfig(certConfig.ServiceCIDR, certificatesDir, certConfig.Options)
+	if err != nil {
+		return nil, err
+	}
+	kubeadmConfig.ClusterConfiguration.EncryptionAlgorithm = encryptionAlgorithm
+	kubeadmConfig.APIServer.CertSANs = append(kubeadmConfig.APIServer.CertSANs, certConfig.APIServerSANs.certSANs()...)
+
+	return kubeadmConfig, nil
+}
+
 func EnsureCerts(
 	ctx context.Context,
 	currentNamespace string,
@@ -78,6 +111,20 @@ func EnsureCerts(
 	certificateDir string,
 	options *config.VirtualClusterConfig,
 	kubeadmConfig *kubeadmapi.InitConfiguration,
+) error {
+	return ensureCerts(ctx, currentNamespace, currentNamespaceClient, certificateDir, options, kubeadmConfig, nil)
+}
+
+// ensureCerts is like EnsureCerts, but generates the kube configs and the front proxy client certificate with the
+// given common names, nil keeps the defaults
+func ensureCerts(
+	ctx context.Context,
+	currentNamespace string,
+	currentNamespaceClient kubernetes.Interface,
+	certificateDir string,
+	options *config.VirtualClusterConfig,
+	kubeadmConfig *kubeadmapi.InitConfiguration,
+	commonNames *CommonNames,
 ) error {
 	// when we run in standalone mode, we don't have a currentNamespaceClient
 	if currentNamespaceClient == nil {
@@ -89,7 +136,7 @@ func EnsureCerts(
 		_, err := os.Stat(filepath.Join(certificateDir, CAKeyName))
 		if errors.Is(err, fs.ErrNotExist) {
 			// try to generate the certificates
-			err = generateCertificates(certificateDir, kubeadmConfig)
+			err = generateCertificates(certificateDir, kubeadmConfig, commonNames)
 			if err != nil {
 				return err
 			}
@@ -106,7 +153,7 @@ func EnsureCerts(
 				return fmt.Errorf("remove expiring leaf certs: %w", err)
 			}
 
-			if err := generateCertificates(certificateDir, kubeadmConfig); err != nil {
+			if err := generateCertificates(certificateDir, kubeadmConfig, commonNames); err != nil {
 				return fmt.Errorf("regenerate certs: %w", err)
 			}
 		}
@@ -139,7 +186,7 @@ func EnsureCerts(
 		}
 
 		// Regenerate missing certs
-		if err := generateCertificates(certificateDir, kubeadmConfig); err != nil {
+		if err := generateCertificates(certificateDir, kubeadmConfig, commonNames); err != nil {
 			return fmt.Errorf("regenerate certs: %w", err)
 		}
 
@@ -151,7 +198,7 @@ func EnsureCerts(
 		return nil
 	}
 
-	err = generateCertificates(certificateDir, kubeadmConfig)
+	err = generateCertificates(certificateDir, kubeadmConfig, commonNames)
 	if err != nil {
 		return err
 	}
@@ -318,6 +365,7 @@ func CertSecretName(vClusterName string) string {
 func generateCertificates(
 	certificateDir string,
 	kubeadmConfig *kubeadmapi.InitConfiguration,
+	commonNames *CommonNames,
 ) error {
 	// only create the files if the files are not there yet
 	err := certs.CreatePKIAssets(kubeadmConfig)
@@ -343,6 +391,14 @@ func generateCertificates(
 		return fmt.Errorf("rename kube config: %w", err)
 	}
 
+	// apply the common names after the kube configs were created, as kubeadm always uses the defaults
+	if commonNames != nil {
+		err = ApplyCommonNames(certificateDir, *commonNames)
+		if err != nil {
+			return fmt.Errorf("apply common names: %w", err)
+		}
+	}
+
 	err = splitCACert(certificateDir)
 	if err != nil {
 		return fmt.Errorf("split ca cert: %w", err)
@@ -465,6 +521,9 @@ type KubeConfigOptions struct {
 	Organizations []string
 	APIServer     string
 	ClientName    string
+
+	// Validity is how long the client certificate is valid, defaults to 10 years
+	Validity time.Duration
 }
 
 // CreateKubeConfig creates a kubeconfig object and writes it to disk
@@ -501,6 +560,9 @@ func BuildKubeConfig(spec *KubeConfigOptions) (*clientcmdapi.Config, error) {
 
 	// we need to set the not after to the start time + 10 years
 	notAfter := kubeadmutil.StartTimeUTC().Add(time.Hour * 24 * 365 * 10)
+	if spec.Validity > 0 {
+		notAfter = time.Now().UTC().Add(spec.Validity)
+	}
 
 	// otherwise, create a client cert
 	clientCertConfig := pkiutil.CertConfig{
@@ -510,7 +572,7 @@ func BuildKubeConfig(spec *KubeConfigOptions) (*clientcmdapi.Config, error) {
 			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 		},
 		NotAfter:            notAfter,
-		EncryptionAlgorithm: kubeadmapi.EncryptionAlgorithmRSA2048,
+		EncryptionAlgorithm: encryptionAlgorithmForKey(caKey.Public()),
 	}
 
 	clientCert, clientKey, err := pkiutil.NewCertAndKey(caCert[0], caKey, &clientCertConfig)
@@ -533,6 +595,23 @@ func BuildKubeConfig(spec *KubeConfigOptions) (*clientcmdapi.Config, error) {
 	), nil
 }
 
+// GenerateAdminKubeConfig creates a kubeconfig for break-glass admin access to the given server. The client
+// certificate is signed by the CA in certDir, is in the SystemPrivilegedGroup and is only valid for the given duration.
+func GenerateAdminKubeConfig(certDir, server string, validity time.Duration) (*clientcmdapi.Config, error) {
+	if validity <= 0 {
+		return nil, fmt.Errorf("validity must be greater than 0")
+	}
+
+	return BuildKubeConfig(&KubeConfigOptions{
+		CACert:        filepath.Join(certDir, CACertName),
+		CAKey:         filepath.Join(certDir, CAKeyName),
+		Organizations: []string{SystemPrivilegedGroup},
+		APIServer:     server,
+		ClientName:    AdminKubeConfigCommonName,
+		Validity:      validity,
+	})
+}
+
 func GetEtcdExtraSANs(options *config.VirtualClusterConfig) []string {
 	clusterDomain := options.Networking.Advanced.ClusterDomain
 	currentNamespace := options.HostNamespace
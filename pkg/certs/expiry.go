@@ -0,0 +1,50 @@
+package certs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+)
+
+// CertExpiries returns the expiry of every known certificate in certsDir, keyed by its file name
+// relative to certsDir (e.g. apiserver.crt or etcd/server.crt). If a file contains a chain, the
+// earliest expiry of the chain is returned. Certificates that don't exist are omitted.
+func CertExpiries(certsDir string) (map[string]time.Time, error) {
+	expiries := map[string]time.Time{}
+	for certFile := range certMap {
+		if !strings.HasSuffix(certFile, ".crt") {
+			continue
+		}
+
+		certPath := filepath.Join(certsDir, certFile)
+		pemBytes, err := os.ReadFile(certPath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("read certificate %s: %w", certPath, err)
+		}
+
+		certs, err := certhelper.ParseCertsPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate %s: %w", certPath, err)
+		}
+
+		notAfter := certs[0].NotAfter
+		for _, cert := range certs[1:] {
+			if cert.NotAfter.Before(notAfter) {
+				notAfter = cert.NotAfter
+			}
+		}
+		expiries[certFile] = notAfter
+	}
+
+	return expiries, nil
+}
@@ -0,0 +1,37 @@
+package certs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestCertExpiries(t *testing.T) {
+	certificateDir := t.TempDir()
+	apiServerExpiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	etcdExpiry := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	assert.NilError(t, os.WriteFile(filepath.Join(certificateDir, APIServerCertName), newSelfSignedCertPEM(t, APIServerCertCommonName, apiServerExpiry), 0644))
+	assert.NilError(t, os.MkdirAll(filepath.Join(certificateDir, "etcd"), 0755))
+	assert.NilError(t, os.WriteFile(filepath.Join(certificateDir, EtcdServerCertName), newSelfSignedCertPEM(t, "etcd", etcdExpiry), 0644))
+
+	// chains report the earliest expiry
+	chain := append(newSelfSignedCertPEM(t, "front-proxy-client", etcdExpiry), newSelfSignedCertPEM(t, "front-proxy-ca", apiServerExpiry)...)
+	assert.NilError(t, os.WriteFile(filepath.Join(certificateDir, FrontProxyClientCertName), chain, 0644))
+
+	// keys and missing certificates are omitted
+	assert.NilError(t, os.WriteFile(filepath.Join(certificateDir, APIServerKeyName), []byte("not a certificate"), 0600))
+	expiries, err := CertExpiries(certificateDir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(expiries), 3)
+	assert.Assert(t, expiries[APIServerCertName].Equal(apiServerExpiry))
+	assert.Assert(t, expiries[EtcdServerCertName].Equal(etcdExpiry))
+	assert.Assert(t, expiries[FrontProxyClientCertName].Equal(apiServerExpiry))
+
+	// invalid certificates are reported
+	assert.NilError(t, os.WriteFile(filepath.Join(certificateDir, CACertName), []byte("not a certificate"), 0644))
+	_, err = CertExpiries(certificateDir)
+	assert.ErrorContains(t, err, "parse certificate")
+}
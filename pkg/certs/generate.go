@@ -0,0 +1,189 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kubeadmapi "k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm"
+	"k8s.io/kubernetes/cmd/kubeadm/app/phases/certs"
+)
+
+// ServiceAccountStep is the progress step reported for the service account key pair
+const ServiceAccountStep = "sa"
+
+// CertConfig holds the inputs required to generate the vCluster PKI
+type CertConfig struct {
+	// ServiceCIDR is the service cidr of the virtual cluster, used for the apiserver SANs
+	ServiceCIDR string
+
+	// Options is the virtual cluster config used to build the kubeadm config
+	Options *config.VirtualClusterConfig
+
+	// APIServerSANs are added to the SANs of the apiserver certificate
+	APIServerSANs APIServerSANs
+
+	// CommonNames overrides the identities of the generated certificates, defaults to DefaultCommonNames
+	CommonNames *CommonNames
+
+	// KeyType is the type of the keys of the CAs, the certificates and the service account signing key,
+	// defaults to KeyTypeRSA
+	KeyType KeyType
+}
+
+// KeyType is the type of the generated private keys
+type KeyType string
+
+const (
+	// KeyTypeRSA generates RSA 2048 keys
+	KeyTypeRSA KeyType = "RSA"
+	// KeyTypeECDSA generates ECDSA P-256 keys
+	KeyTypeECDSA KeyType = "ECDSA"
+)
+
+// EncryptionAlgorithm returns the kubeadm encryption algorithm of the key type
+func (k KeyType) EncryptionAlgorithm() (kubeadmapi.EncryptionAlgorithmType, error) {
+	switch k {
+	case "", KeyTypeRSA:
+		return kubeadmapi.EncryptionAlgorithmRSA2048, nil
+	case KeyTypeECDSA:
+		return kubeadmapi.EncryptionAlgorithmECDSAP256, nil
+	default:
+		return "", fmt.Errorf("unsupported key type %q, must be %q or %q", k, KeyTypeRSA, KeyTypeECDSA)
+	}
+}
+
+// encryptionAlgorithmForKey returns the kubeadm encryption algorithm of the given public key, so certificates
+// signed by a CA or renewed from an existing certificate keep the key type
+func encryptionAlgorithmForKey(publicKey crypto.PublicKey) kubeadmapi.EncryptionAlgorithmType {
+	switch publicKey := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if publicKey.Curve == elliptic.P384() {
+			return kubeadmapi.EncryptionAlgorithmECDSAP384
+		}
+		return kubeadmapi.EncryptionAlgorithmECDSAP256
+	case *rsa.PublicKey:
+		switch publicKey.N.BitLen() {
+		case 3072:
+			return kubeadmapi.EncryptionAlgorithmRSA3072
+		case 4096:
+			return kubeadmapi.EncryptionAlgorithmRSA4096
+		}
+	}
+
+	return kubeadmapi.EncryptionAlgorithmRSA2048
+}
+
+// APIServerSANs are additional subject alternative names of the apiserver certificate, e.g. the
+// hostname of an external load balancer or a custom domain the vCluster is exposed under
+type APIServerSANs struct {
+	// DNSNames may contain wildcards such as *.example.com
+	DNSNames []string
+
+	IPs []net.IP
+}
+
+// Validate returns an error if a dns name or ip is invalid
+func (s APIServerSANs) Validate() error {
+	for _, dnsName := range s.DNSNames {
+		var errs []string
+		if strings.HasPrefix(dnsName, "*.") {
+			errs = validation.IsWildcardDNS1123Subdomain(dnsName)
+		} else {
+			errs = validation.IsDNS1123Subdomain(dnsName)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid apiserver SAN %q: %s", dnsName, strings.Join(errs, ", "))
+		}
+	}
+	for _, ip := range s.IPs {
+		if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+			return fmt.Errorf("invalid apiserver SAN ip %v", []byte(ip))
+		}
+	}
+
+	return nil
+}
+
+// certSANs returns the SANs in the format of the kubeadm config
+func (s APIServerSANs) certSANs() []string {
+	certSANs := make([]string, 0, len(s.DNSNames)+len(s.IPs))
+	certSANs = append(certSANs, s.DNSNames...)
+	for _, ip := range s.IPs {
+		certSANs = append(certSANs, ip.String())
+	}
+
+	return certSANs
+}
+
+type caKeyPair struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// GenerateAllCerts generates the full set of certificates (CA, apiserver, apiserver-kubelet-client,
+// front-proxy, etcd and the service account key pair) into certDir. Progress is called once
+// before each certificate is generated with the kubeadm name of the certificate, or
+// ServiceAccountStep for the service account key pair. A nil progress is a no-op.
+func GenerateAllCerts(certDir string, certConfig CertConfig, progress func(step string)) error {
+	if certConfig.CommonNames != nil {
+		if err := certConfig.CommonNames.Validate(); err != nil {
+			return err
+		}
+	}
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	kubeadmConfig, err := GenerateInitKubeadmConfigWithCertConfig(certDir, certConfig)
+	if err != nil {
+		return fmt.Errorf("create kubeadm config: %w", err)
+	}
+
+	// generate in list order so that every CA exists before the certificates it signs
+	cas := map[string]*caKeyPair{}
+	for _, cert := range certs.GetDefaultCertList() {
+		progress(cert.Name)
+		if cert.CAName == "" {
+			caCert, caKey, err := cert.CreateAsCA(kubeadmConfig)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", cert.Name, err)
+			}
+
+			cas[cert.Name] = &caKeyPair{cert: caCert, key: caKey}
+			continue
+		}
+
+		ca, ok := cas[cert.CAName]
+		if !ok {
+			return fmt.Errorf("certificate %s references unknown ca %s", cert.Name, cert.CAName)
+		}
+		err = cert.CreateFromCA(kubeadmConfig, ca.cert, ca.key)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", cert.Name, err)
+		}
+	}
+
+	// service accounts are not x509 certs, so handled separately
+	progress(ServiceAccountStep)
+	err = certs.CreateServiceAccountKeyAndPublicKeyFiles(certDir, kubeadmConfig.ClusterConfiguration.EncryptionAlgorithmType())
+	if err != nil {
+		return fmt.Errorf("create service account key pair: %w", err)
+	}
+
+	if certConfig.CommonNames != nil {
+		err = ApplyCommonNames(certDir, *certConfig.CommonNames)
+		if err != nil {
+			return fmt.Errorf("apply common names: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/kubernetes/cmd/kubeadm/app/phases/certs"
+)
+
+func TestGenerateAllCerts(t *testing.T) {
+	certDir := t.TempDir()
+
+	steps := map[string]int{}
+	err := GenerateAllCerts(certDir, CertConfig{
+		ServiceCIDR: "10.96.0.0/12",
+		Options:     testingutil.NewFakeConfig(),
+	}, func(step string) {
+		steps[step]++
+	})
+	assert.NilError(t, err)
+
+	certList := certs.GetDefaultCertList()
+	assert.Equal(t, len(steps), len(certList)+1)
+	for _, cert := range certList {
+		assert.Equal(t, steps[cert.Name], 1, cert.Name)
+	}
+	assert.Equal(t, steps[ServiceAccountStep], 1)
+
+	for _, file := range []string{
+		CACertName, CAKeyName,
+		APIServerCertName, APIServerKeyName,
+		APIServerKubeletClientCertName, APIServerKubeletClientKeyName,
+		FrontProxyCACertName, FrontProxyCAKeyName,
+		FrontProxyClientCertName, FrontProxyClientKeyName,
+		EtcdCACertName, EtcdCAKeyName,
+		EtcdServerCertName, EtcdServerKeyName,
+		EtcdPeerCertName, EtcdPeerKeyName,
+		EtcdHealthcheckClientCertName, EtcdHealthcheckClientKeyName,
+		APIServerEtcdClientCertName, APIServerEtcdClientKeyName,
+		ServiceAccountPrivateKeyName, ServiceAccountPublicKeyName,
+	} {
+		_, err := os.Stat(filepath.Join(certDir, file))
+		assert.NilError(t, err, file)
+	}
+}
+
+func TestGenerateAllCertsNilProgress(t *testing.T) {
+	err := GenerateAllCerts(t.TempDir(), CertConfig{
+		ServiceCIDR: "10.96.0.0/12",
+		Options:     testingutil.NewFakeConfig(),
+	}, nil)
+	assert.NilError(t, err)
+}
+
+func TestGenerateAdminKubeConfig(t *testing.T) {
+	certDir := t.TempDir()
+	err := GenerateAllCerts(certDir, CertConfig{
+		ServiceCIDR: "10.96.0.0/12",
+		Options:     testingutil.NewFakeConfig(),
+	}, nil)
+	assert.NilError(t, err)
+
+	kubeConfig, err := GenerateAdminKubeConfig(certDir, "https://127.0.0.1:6443", time.Hour)
+	assert.NilError(t, err)
+	assert.Equal(t, kubeConfig.Clusters["default"].Server, "https://127.0.0.1:6443")
+
+	caPEM, err := os.ReadFile(filepath.Join(certDir, CACertName))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, kubeConfig.Clusters["default"].CertificateAuthorityData, caPEM)
+
+	clientCerts, err := certhelper.ParseCertsPEM(kubeConfig.AuthInfos[AdminKubeConfigCommonName].ClientCertificateData)
+	assert.NilError(t, err)
+	clientCert := clientCerts[0]
+	assert.Equal(t, clientCert.Subject.CommonName, AdminKubeConfigCommonName)
+	assert.DeepEqual(t, clientCert.Subject.Organization, []string{SystemPrivilegedGroup})
+	assert.Assert(t, clientCert.NotAfter.Before(time.Now().Add(time.Hour+time.Minute)))
+
+	caCerts, err := certhelper.ParseCertsPEM(caPEM)
+	assert.NilError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCerts[0])
+	_, err = clientCert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	assert.NilError(t, err)
+
+	_, err = GenerateAdminKubeConfig(certDir, "https://127.0.0.1:6443", 0)
+	assert.ErrorContains(t, err, "validity must be greater than 0")
+}
+
+func TestGenerateAllCertsAPIServerSANs(t *testing.T) {
+	certDir := t.TempDir()
+	err := GenerateAllCerts(certDir, CertConfig{
+		ServiceCIDR: "10.96.0.0/12",
+		Options:     testingutil.NewFakeConfig(),
+		APIServerSANs: APIServerSANs{
+			DNSNames: []string{"vcluster.example.com", "*.vcluster.example.com"},
+			IPs:      []net.IP{net.ParseIP("203.0.113.10"), net.ParseIP("2001:db8::10")},
+		},
+	}, nil)
+	assert.NilError(t, err)
+
+	dnsNames, ips, err := ListAPIServerCertSANs(certDir)
+	assert.NilError(t, err)
+	assert.Assert(t, slices.Contains(dnsNames, "vcluster.example.com"))
+	assert.Assert(t, slices.Contains(dnsNames, "*.vcluster.example.com"))
+	for _, expected := range []string{"203.0.113.10", "2001:db8::10"} {
+		assert.Assert(t, slices.ContainsFunc(ips, func(ip net.IP) bool { return ip.Equal(net.ParseIP(expected)) }), expected)
+	}
+
+	// invalid SANs are rejected before anything is generated
+	certDir = t.TempDir()
+	err = GenerateAllCerts(certDir, CertConfig{
+		ServiceCIDR:   "10.96.0.0/12",
+		Options:       testingutil.NewFakeConfig(),
+		APIServerSANs: APIServerSANs{DNSNames: []string{"Invalid_Name"}},
+	}, nil)
+	assert.ErrorContains(t, err, `invalid apiserver SAN "Invalid_Name"`)
+	_, err = os.Stat(filepath.Join(certDir, CACertName))
+	assert.Assert(t, os.IsNotExist(err))
+
+	err = GenerateAllCerts(t.TempDir(), CertConfig{
+		ServiceCIDR:   "10.96.0.0/12",
+		Options:       testingutil.NewFakeConfig(),
+		APIServerSANs: APIServerSANs{IPs: []net.IP{net.ParseIP("not-an-ip")}},
+	}, nil)
+	assert.ErrorContains(t, err, "invalid apiserver SAN ip")
+}
+
+// newEnsureCertsConfig returns a cert config for EnsureCerts that stores the certificates in the certs secret of
+// a fake host cluster
+func newEnsureCertsConfig() CertConfig {
+	options := testingutil.NewFakeConfig()
+	options.Experimental.SyncSettings.SetOwner = false
+	return CertConfig{ServiceCIDR: "10.96.0.0/12", Options: options}
+}
+
+func TestGenerateWithCertConfigAPIServerSANs(t *testing.T) {
+	certDir := t.TempDir()
+	certConfig := newEnsureCertsConfig()
+	certConfig.APIServerSANs = APIServerSANs{
+		DNSNames: []string{"vcluster.example.com"},
+		IPs:      []net.IP{net.ParseIP("203.0.113.10")},
+	}
+	assert.NilError(t, GenerateWithCertConfig(context.Background(), certDir, certConfig))
+
+	dnsNames, ips, err := ListAPIServerCertSANs(certDir)
+	assert.NilError(t, err)
+	assert.Assert(t, slices.Contains(dnsNames, "vcluster.example.com"))
+	assert.Assert(t, slices.ContainsFunc(ips, func(ip net.IP) bool { return ip.Equal(net.ParseIP("203.0.113.10")) }))
+
+	certConfig = newEnsureCertsConfig()
+	certConfig.APIServerSANs = APIServerSANs{DNSNames: []string{"Invalid_Name"}}
+	err = GenerateWithCertConfig(context.Background(), t.TempDir(), certConfig)
+	assert.ErrorContains(t, err, `invalid apiserver SAN "Invalid_Name"`)
+}
+
+func TestGenerateAllCertsKeyType(t *testing.T) {
+	certDir := t.TempDir()
+	err := GenerateAllCerts(certDir, CertConfig{
+		ServiceCIDR: "10.96.0.0/12",
+		Options:     testingutil.NewFakeConfig(),
+		KeyType:     KeyTypeECDSA,
+	}, nil)
+	assert.NilError(t, err)
+
+	for _, file := range []string{
+		CAKeyName, APIServerKeyName, APIServerKubeletClientKeyName,
+		FrontProxyCAKeyName, FrontProxyClientKeyName,
+		EtcdCAKeyName, EtcdServerKeyName, EtcdPeerKeyName, EtcdHealthcheckClientKeyName, APIServerEtcdClientKeyName,
+		ServiceAccountPrivateKeyName,
+	} {
+		key, err := keyutil.PrivateKeyFromFile(filepath.Join(certDir, file))
+		assert.NilError(t, err, file)
+		_, ok := key.(*ecdsa.PrivateKey)
+		assert.Assert(t, ok, file)
+	}
+	for _, file := range []string{
+		CACertName, APIServerCertName, APIServerKubeletClientCertName,
+		FrontProxyCACertName, FrontProxyClientCertName,
+		EtcdCACertName, EtcdServerCertName, EtcdPeerCertName, EtcdHealthcheckClientCertName, APIServerEtcdClientCertName,
+	} {
+		pemBytes, err := os.ReadFile(filepath.Join(certDir, file))
+		assert.NilError(t, err, file)
+		certs, err := certhelper.ParseCertsPEM(pemBytes)
+		assert.NilError(t, err, file)
+		_, ok := certs[0].PublicKey.(*ecdsa.PublicKey)
+		assert.Assert(t, ok, file)
+	}
+	publicKeys, err := keyutil.PublicKeysFromFile(filepath.Join(certDir, ServiceAccountPublicKeyName))
+	assert.NilError(t, err)
+	_, ok := publicKeys[0].(*ecdsa.PublicKey)
+	assert.Assert(t, ok)
+
+	// kubeconfigs use the key type of the ca
+	kubeConfig, err := GenerateAdminKubeConfig(certDir, "https://127.0.0.1:6443", time.Hour)
+	assert.NilError(t, err)
+	clientKey, err := keyutil.ParsePrivateKeyPEM(kubeConfig.AuthInfos[AdminKubeConfigCommonName].ClientKeyData)
+	assert.NilError(t, err)
+	_, ok = clientKey.(*ecdsa.PrivateKey)
+	assert.Assert(t, ok)
+
+	// rsa is the default
+	certDir = t.TempDir()
+	assert.NilError(t, GenerateAllCerts(certDir, CertConfig{ServiceCIDR: "10.96.0.0/12", Options: testingutil.NewFakeConfig()}, nil))
+	key, err := keyutil.PrivateKeyFromFile(filepath.Join(certDir, CAKeyName))
+	assert.NilError(t, err)
+	_, ok = key.(*rsa.PrivateKey)
+	assert.Assert(t, ok)
+
+	err = GenerateAllCerts(t.TempDir(), CertConfig{ServiceCIDR: "10.96.0.0/12", Options: testingutil.NewFakeConfig(), KeyType: "DSA"}, nil)
+	assert.ErrorContains(t, err, `unsupported key type "DSA"`)
+}
+
+func TestGenerateWithCertConfigKeyType(t *testing.T) {
+	certDir := t.TempDir()
+	certConfig := newEnsureCertsConfig()
+	certConfig.KeyType = KeyTypeECDSA
+	assert.NilError(t, GenerateWithCertConfig(context.Background(), certDir, certConfig))
+
+	for _, kubeConfigName := range []string{AdminKubeConfigFileName, ControllerManagerKubeConfigFileName, SchedulerKubeConfigFileName} {
+		kubeConfig, err := clientcmd.LoadFromFile(filepath.Join(certDir, kubeConfigName))
+		assert.NilError(t, err, kubeConfigName)
+		assert.Equal(t, len(kubeConfig.AuthInfos), 1, kubeConfigName)
+		for _, authInfo := range kubeConfig.AuthInfos {
+			clientKey, err := keyutil.ParsePrivateKeyPEM(authInfo.ClientKeyData)
+			assert.NilError(t, err, kubeConfigName)
+			_, ok := clientKey.(*ecdsa.PrivateKey)
+			assert.Assert(t, ok, kubeConfigName)
+		}
+	}
+	for _, file := range []string{CAKeyName, APIServerKeyName, ServiceAccountPrivateKeyName} {
+		key, err := keyutil.PrivateKeyFromFile(filepath.Join(certDir, file))
+		assert.NilError(t, err, file)
+		_, ok := key.(*ecdsa.PrivateKey)
+		assert.Assert(t, ok, file)
+	}
+
+	certConfig = newEnsureCertsConfig()
+	certConfig.KeyType = "DSA"
+	assert.ErrorContains(t, GenerateWithCertConfig(context.Background(), t.TempDir(), certConfig), `unsupported key type "DSA"`)
+}
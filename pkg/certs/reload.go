@@ -0,0 +1,109 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+)
+
+// CertReloader serves a certificate and key pair from disk and reloads it whenever the files
+// change, e.g. after the leaf certificates were rotated, so that running servers pick up the
+// new certificate without a restart.
+type CertReloader struct {
+	certPath string
+	keyPath  string
+
+	cert atomic.Pointer[tls.Certificate]
+}
+
+// NewCertReloader loads the certificate and key pair at the given paths.
+func NewCertReloader(certPath, keyPath string) (*CertReloader, error) {
+	reloader := &CertReloader{
+		certPath: filepath.Clean(certPath),
+		keyPath:  filepath.Clean(keyPath),
+	}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// NewAPIServerCertReloader loads the apiserver certificate and key pair in certificateDir.
+func NewAPIServerCertReloader(certificateDir string) (*CertReloader, error) {
+	return NewCertReloader(filepath.Join(certificateDir, APIServerCertName), filepath.Join(certificateDir, APIServerKeyName))
+}
+
+// Reload reads the certificate and key pair from disk and swaps the served certificate.
+// If the pair cannot be loaded, the previous certificate is kept.
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return fmt.Errorf("load certificate %s: %w", r.certPath, err)
+	}
+
+	r.cert.Store(&cert)
+	return nil
+}
+
+// GetCertificate returns the current certificate and can be used as tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// TLSConfig returns a tls config that always serves the current certificate.
+func (r *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// Start watches the certificate and key files and reloads them on change until the context is done.
+// The directories are watched instead of the files themselves, as rotated files are usually replaced
+// rather than written in place.
+func (r *CertReloader) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, dir := range []string{filepath.Dir(r.certPath), filepath.Dir(r.keyPath)} {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("watch %s: %w", dir, err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Name != r.certPath && event.Name != r.keyPath {
+				continue
+			}
+
+			// the certificate and key are written one after the other, so a mismatch is expected
+			// here until both files were replaced
+			if err := r.Reload(); err != nil {
+				klog.V(1).Infof("Skipping certificate reload: %v", err)
+				continue
+			}
+			klog.Infof("Reloaded certificate %s", r.certPath)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			klog.Errorf("Error watching certificate %s: %v", r.certPath, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package certs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+	"gotest.tools/assert"
+)
+
+func writeTestKeyPair(t *testing.T, certificateDir string, serial int64) {
+	keyPEM, err := certhelper.MakeEllipticPrivateKeyPEM()
+	assert.NilError(t, err)
+	key, err := certhelper.ParsePrivateKeyPEM(keyPEM)
+	assert.NilError(t, err)
+	signer := key.(crypto.Signer)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: APIServerCertCommonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, signer.Public(), signer)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(derBytes)
+	assert.NilError(t, err)
+
+	// write to temporary files first and rename them, the same way rotated certificates are replaced
+	for name, data := range map[string][]byte{APIServerCertName: certhelper.EncodeCertPEM(cert), APIServerKeyName: keyPEM} {
+		tmpPath := filepath.Join(certificateDir, name+".tmp")
+		assert.NilError(t, os.WriteFile(tmpPath, data, 0600))
+		assert.NilError(t, os.Rename(tmpPath, filepath.Join(certificateDir, name)))
+	}
+}
+
+func servedSerial(t *testing.T, reloader *CertReloader) int64 {
+	cert, err := reloader.TLSConfig().GetCertificate(nil)
+	assert.NilError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NilError(t, err)
+	return leaf.SerialNumber.Int64()
+}
+
+func TestCertReloader(t *testing.T) {
+	certificateDir := t.TempDir()
+	writeTestKeyPair(t, certificateDir, 1)
+
+	reloader, err := NewAPIServerCertReloader(certificateDir)
+	assert.NilError(t, err)
+	assert.Equal(t, servedSerial(t, reloader), int64(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- reloader.Start(ctx)
+	}()
+
+	// wait until the watcher is started by rewriting the certificate until it is picked up
+	deadline := time.Now().Add(10 * time.Second)
+	for servedSerial(t, reloader) != 2 {
+		assert.Assert(t, time.Now().Before(deadline), "rotated certificate was not reloaded")
+		writeTestKeyPair(t, certificateDir, 2)
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// invalid files keep the previous certificate
+	assert.NilError(t, os.WriteFile(filepath.Join(certificateDir, APIServerCertName), []byte("invalid"), 0600))
+	assert.Assert(t, reloader.Reload() != nil)
+	assert.Equal(t, servedSerial(t, reloader), int64(2))
+
+	cancel()
+	assert.NilError(t, <-done)
+}
@@ -2,6 +2,7 @@ package certs
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -14,9 +15,14 @@ import (
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/config"
 	setupconfig "github.com/loft-sh/vcluster/pkg/setup/config"
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
 	"github.com/loft-sh/vcluster/pkg/util/servicecidr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/kubernetes/cmd/kubeadm/app/util/pkiutil"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -90,7 +96,7 @@ func Rotate(ctx context.Context,
 		return fmt.Errorf("removing files from PKI directory: %w", err)
 	}
 
-	if err := generateCertificates(pkiPath, kubeadmConfig); err != nil {
+	if err := generateCertificates(pkiPath, kubeadmConfig, nil); err != nil {
 		return fmt.Errorf("creating pki assets: %w", err)
 	}
 
@@ -262,3 +268,135 @@ func SyncSecret(ctx context.Context, secretNamespace, secretName, pkiPath string
 
 	return nil
 }
+
+// leafCertCAs maps the base names of the leaf certificates to the base names of the CAs that sign them
+var leafCertCAs = map[string]string{
+	strings.TrimSuffix(APIServerCertName, ".crt"):              strings.TrimSuffix(CACertName, ".crt"),
+	strings.TrimSuffix(APIServerKubeletClientCertName, ".crt"): strings.TrimSuffix(CACertName, ".crt"),
+	strings.TrimSuffix(FrontProxyClientCertName, ".crt"):       strings.TrimSuffix(FrontProxyCACertName, ".crt"),
+	strings.TrimSuffix(EtcdServerCertName, ".crt"):             strings.TrimSuffix(EtcdCACertName, ".crt"),
+	strings.TrimSuffix(EtcdPeerCertName, ".crt"):               strings.TrimSuffix(EtcdCACertName, ".crt"),
+	strings.TrimSuffix(EtcdHealthcheckClientCertName, ".crt"):  strings.TrimSuffix(EtcdCACertName, ".crt"),
+	strings.TrimSuffix(APIServerEtcdClientCertName, ".crt"):    strings.TrimSuffix(EtcdCACertName, ".crt"),
+}
+
+// RotateLeafCerts regenerates all leaf certificates in certsDir with new keys and signs them with the existing CAs,
+// which are left untouched, so kubeconfigs that trust the CA stay valid. The subject, SANs and usages of each leaf
+// certificate are kept. The client certificates of the admin, controller-manager and scheduler kubeconfigs are
+// regenerated as well. Leaf certificates and kubeconfigs that don't exist are skipped.
+func RotateLeafCerts(certsDir string) error {
+	cas := map[string]*caKeyPair{}
+	loadCA := func(name string) (*caKeyPair, error) {
+		if ca, ok := cas[name]; ok {
+			return ca, nil
+		}
+
+		caCert, caKey, err := pkiutil.TryLoadCertAndKeyFromDisk(certsDir, name)
+		if err != nil {
+			return nil, fmt.Errorf("load ca %s: %w", name, err)
+		}
+
+		cas[name] = &caKeyPair{cert: caCert, key: caKey}
+		return cas[name], nil
+	}
+
+	for name, caName := range leafCertCAs {
+		if _, err := os.Stat(filepath.Join(certsDir, name+".crt")); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		oldCert, err := pkiutil.TryLoadCertFromDisk(certsDir, name)
+		if err != nil {
+			return fmt.Errorf("load certificate %s: %w", name, err)
+		}
+		ca, err := loadCA(caName)
+		if err != nil {
+			return err
+		}
+
+		newCert, newKey, err := pkiutil.NewCertAndKey(ca.cert, ca.key, renewedCertConfig(oldCert, ca.cert))
+		if err != nil {
+			return fmt.Errorf("create certificate %s: %w", name, err)
+		}
+		err = pkiutil.WriteCertAndKey(certsDir, name, newCert, newKey)
+		if err != nil {
+			return fmt.Errorf("write certificate %s: %w", name, err)
+		}
+	}
+
+	for _, kubeConfigName := range []string{AdminKubeConfigFileName, ControllerManagerKubeConfigFileName, SchedulerKubeConfigFileName} {
+		kubeConfigPath := filepath.Join(certsDir, kubeConfigName)
+		if _, err := os.Stat(kubeConfigPath); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		ca, err := loadCA(strings.TrimSuffix(CACertName, ".crt"))
+		if err != nil {
+			return err
+		}
+		err = rotateKubeConfigClientCerts(kubeConfigPath, ca, "")
+		if err != nil {
+			return fmt.Errorf("rotate %s: %w", kubeConfigName, err)
+		}
+	}
+
+	return nil
+}
+
+// rotateKubeConfigClientCerts regenerates the client certificates of all users in the given kubeconfig. If commonName
+// is not empty, it replaces the common name of the client certificates.
+func rotateKubeConfigClientCerts(kubeConfigPath string, ca *caKeyPair, commonName string) error {
+	kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	for userName, authInfo := range kubeConfig.AuthInfos {
+		if len(authInfo.ClientCertificateData) == 0 {
+			continue
+		}
+
+		oldCerts, err := certhelper.ParseCertsPEM(authInfo.ClientCertificateData)
+		if err != nil {
+			return fmt.Errorf("parse client certificate of user %s: %w", userName, err)
+		}
+
+		certConfig := renewedCertConfig(oldCerts[0], ca.cert)
+		if commonName != "" {
+			certConfig.CommonName = commonName
+		}
+		newCert, newKey, err := pkiutil.NewCertAndKey(ca.cert, ca.key, certConfig)
+		if err != nil {
+			return fmt.Errorf("create client certificate of user %s: %w", userName, err)
+		}
+		encodedKey, err := keyutil.MarshalPrivateKeyToPEM(newKey)
+		if err != nil {
+			return fmt.Errorf("marshal client key of user %s: %w", userName, err)
+		}
+
+		authInfo.ClientCertificateData = pkiutil.EncodeCertPEM(newCert)
+		authInfo.ClientKeyData = encodedKey
+	}
+
+	return clientcmd.WriteToFile(*kubeConfig, kubeConfigPath)
+}
+
+// renewedCertConfig returns the config for a new certificate with the subject, SANs, usages and key type of
+// the given certificate. The new certificate is valid for CertificateValidity, but not longer than its CA.
+func renewedCertConfig(cert *x509.Certificate, caCert *x509.Certificate) *pkiutil.CertConfig {
+	notAfter := time.Now().UTC().Add(CertificateValidity)
+	if caCert.NotAfter.Before(notAfter) {
+		notAfter = caCert.NotAfter
+	}
+
+	return &pkiutil.CertConfig{
+		Config: certutil.Config{
+			CommonName:   cert.Subject.CommonName,
+			Organization: cert.Subject.Organization,
+			AltNames:     certutil.AltNames{DNSNames: cert.DNSNames, IPs: cert.IPAddresses},
+			Usages:       cert.ExtKeyUsage,
+		},
+		NotAfter:            notAfter,
+		EncryptionAlgorithm: encryptionAlgorithmForKey(cert.PublicKey),
+	}
+}
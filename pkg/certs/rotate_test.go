@@ -0,0 +1,92 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestRotateLeafCerts(t *testing.T) {
+	certDir := t.TempDir()
+	kubeadmConfig, err := GenerateInitKubeadmConfig("10.96.0.0/12", certDir, testingutil.NewFakeConfig())
+	assert.NilError(t, err)
+	assert.NilError(t, generateCertificates(certDir, kubeadmConfig, nil))
+
+	readCert := func(name string) *x509.Certificate {
+		pemBytes, err := os.ReadFile(filepath.Join(certDir, name))
+		assert.NilError(t, err, name)
+		certs, err := certhelper.ParseCertsPEM(pemBytes)
+		assert.NilError(t, err, name)
+		return certs[0]
+	}
+	readFile := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join(certDir, name))
+		assert.NilError(t, err, name)
+		return data
+	}
+	verify := func(cert *x509.Certificate, caName string) error {
+		roots := x509.NewCertPool()
+		roots.AddCert(readCert(caName))
+		_, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+		return err
+	}
+
+	caFiles := []string{CACertName, CAKeyName, FrontProxyCACertName, FrontProxyCAKeyName, EtcdCACertName, EtcdCAKeyName, ServiceAccountPrivateKeyName}
+	oldCAs := map[string][]byte{}
+	for _, name := range caFiles {
+		oldCAs[name] = readFile(name)
+	}
+	oldLeafs := map[string]*x509.Certificate{}
+	oldKeys := map[string][]byte{}
+	for name := range leafCertCAs {
+		oldLeafs[name] = readCert(name + ".crt")
+		oldKeys[name] = readFile(name + ".key")
+	}
+	oldKubeConfig, err := clientcmd.LoadFromFile(filepath.Join(certDir, AdminKubeConfigFileName))
+	assert.NilError(t, err)
+
+	assert.NilError(t, RotateLeafCerts(certDir))
+
+	// the cas are untouched
+	for _, name := range caFiles {
+		assert.Assert(t, bytes.Equal(readFile(name), oldCAs[name]), name)
+	}
+
+	// the leaf certificates are new, keep their SANs and chain to the old cas
+	for name, caName := range leafCertCAs {
+		newCert := readCert(name + ".crt")
+		assert.Assert(t, newCert.SerialNumber.Cmp(oldLeafs[name].SerialNumber) != 0, name)
+		assert.Assert(t, !bytes.Equal(readFile(name+".key"), oldKeys[name]), name)
+		assert.Equal(t, newCert.Subject.CommonName, oldLeafs[name].Subject.CommonName)
+		assert.DeepEqual(t, newCert.DNSNames, oldLeafs[name].DNSNames)
+		assert.DeepEqual(t, newCert.ExtKeyUsage, oldLeafs[name].ExtKeyUsage)
+		assert.NilError(t, verify(newCert, caName+".crt"), name)
+	}
+
+	// the kubeconfig client certificates are new and chain to the old ca
+	for _, kubeConfigName := range []string{AdminKubeConfigFileName, ControllerManagerKubeConfigFileName, SchedulerKubeConfigFileName} {
+		kubeConfig, err := clientcmd.LoadFromFile(filepath.Join(certDir, kubeConfigName))
+		assert.NilError(t, err, kubeConfigName)
+		for _, authInfo := range kubeConfig.AuthInfos {
+			clientCerts, err := certhelper.ParseCertsPEM(authInfo.ClientCertificateData)
+			assert.NilError(t, err, kubeConfigName)
+			assert.NilError(t, verify(clientCerts[0], CACertName), kubeConfigName)
+		}
+	}
+	newKubeConfig, err := clientcmd.LoadFromFile(filepath.Join(certDir, AdminKubeConfigFileName))
+	assert.NilError(t, err)
+	for userName, authInfo := range newKubeConfig.AuthInfos {
+		assert.Assert(t, !bytes.Equal(authInfo.ClientCertificateData, oldKubeConfig.AuthInfos[userName].ClientCertificateData), userName)
+	}
+
+	// missing cas are reported
+	assert.NilError(t, os.Remove(filepath.Join(certDir, FrontProxyCAKeyName)))
+	assert.ErrorContains(t, RotateLeafCerts(certDir), "load ca front-proxy-ca")
+}
@@ -0,0 +1,32 @@
+package certs
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+)
+
+// ListCertSANs parses the certificate at certPath and returns the DNS names and
+// IP addresses it was issued for. If the file contains a chain, the SANs of the
+// first (leaf) certificate are returned.
+func ListCertSANs(certPath string) ([]string, []net.IP, error) {
+	pemBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read certificate %s: %w", certPath, err)
+	}
+
+	certs, err := certhelper.ParseCertsPEM(pemBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse certificate %s: %w", certPath, err)
+	}
+
+	return certs[0].DNSNames, certs[0].IPAddresses, nil
+}
+
+// ListAPIServerCertSANs returns the SANs of the apiserver certificate in certificateDir.
+func ListAPIServerCertSANs(certificateDir string) ([]string, []net.IP, error) {
+	return ListCertSANs(filepath.Join(certificateDir, APIServerCertName))
+}
@@ -0,0 +1,55 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+	"gotest.tools/assert"
+)
+
+func TestListCertSANs(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: APIServerCertCommonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"kubernetes.default", "my-vcluster.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("10.96.0.1"), net.ParseIP("127.0.0.1")},
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(derBytes)
+	assert.NilError(t, err)
+
+	certificateDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(certificateDir, APIServerCertName), certhelper.EncodeCertPEM(cert), 0644))
+
+	dnsNames, ips, err := ListAPIServerCertSANs(certificateDir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, dnsNames, tmpl.DNSNames)
+	assert.Equal(t, len(ips), 2)
+	assert.Assert(t, ips[0].Equal(tmpl.IPAddresses[0]))
+	assert.Assert(t, ips[1].Equal(tmpl.IPAddresses[1]))
+
+	// missing and invalid files are reported
+	_, _, err = ListCertSANs(filepath.Join(certificateDir, "missing.crt"))
+	assert.ErrorContains(t, err, "read certificate")
+
+	invalidPath := filepath.Join(certificateDir, "invalid.crt")
+	assert.NilError(t, os.WriteFile(invalidPath, []byte("not a certificate"), 0644))
+	_, _, err = ListCertSANs(invalidPath)
+	assert.ErrorContains(t, err, "parse certificate")
+}
@@ -0,0 +1,101 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/certhelper"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/keyutil"
+)
+
+// Validate checks that all certificates and keys exist in certsDir, can be parsed, match each other and that the
+// leaf certificates are signed by their CAs. All problems are returned as an aggregate error.
+func Validate(certsDir string) error {
+	errs := []error{}
+	invalid := map[string]bool{}
+	addError := func(name string, err error) {
+		invalid[name] = true
+		errs = append(errs, err)
+	}
+
+	files := []string{}
+	for name := range certMap {
+		if strings.HasSuffix(name, ".crt") || strings.HasSuffix(name, ".key") || strings.HasSuffix(name, ".pub") {
+			files = append(files, name)
+		}
+	}
+	slices.Sort(files)
+
+	// check every file on its own
+	certs := map[string]*x509.Certificate{}
+	for _, name := range files {
+		path := filepath.Join(certsDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			addError(name, fmt.Errorf("read %s: %w", name, err))
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(name, ".crt"):
+			parsed, err := certhelper.ParseCertsPEM(data)
+			if err != nil {
+				addError(name, fmt.Errorf("parse certificate %s: %w", name, err))
+				continue
+			}
+
+			certs[name] = parsed[0]
+		case strings.HasSuffix(name, ".key"):
+			_, err = keyutil.ParsePrivateKeyPEM(data)
+			if err != nil {
+				addError(name, fmt.Errorf("parse key %s: %w", name, err))
+			}
+		default:
+			_, err = keyutil.ParsePublicKeysPEM(data)
+			if err != nil {
+				addError(name, fmt.Errorf("parse public key %s: %w", name, err))
+			}
+		}
+	}
+
+	// check that the keys belong to the certificates
+	for _, name := range files {
+		keyName := strings.TrimSuffix(name, ".crt") + ".key"
+		if !strings.HasSuffix(name, ".crt") || invalid[name] || invalid[keyName] || !slices.Contains(files, keyName) {
+			continue
+		}
+
+		_, err := tls.LoadX509KeyPair(filepath.Join(certsDir, name), filepath.Join(certsDir, keyName))
+		if err != nil {
+			addError(name, fmt.Errorf("key %s does not match certificate %s: %w", keyName, name, err))
+		}
+	}
+
+	// check that the leaf certificates are signed by their cas
+	leafNames := make([]string, 0, len(leafCertCAs))
+	for name := range leafCertCAs {
+		leafNames = append(leafNames, name)
+	}
+	slices.Sort(leafNames)
+	for _, name := range leafNames {
+		leafCert, caCert := certs[name+".crt"], certs[leafCertCAs[name]+".crt"]
+		if leafCert == nil || caCert == nil {
+			continue
+		}
+
+		roots := x509.NewCertPool()
+		roots.AddCert(caCert)
+		_, err := leafCert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+		if err != nil {
+			addError(name+".crt", fmt.Errorf("verify certificate %s.crt against %s.crt: %w", name, leafCertCAs[name], err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
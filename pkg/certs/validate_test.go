@@ -0,0 +1,43 @@
+package certs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func TestValidate(t *testing.T) {
+	certDir := t.TempDir()
+	kubeadmConfig, err := GenerateInitKubeadmConfig("10.96.0.0/12", certDir, testingutil.NewFakeConfig())
+	assert.NilError(t, err)
+	assert.NilError(t, generateCertificates(certDir, kubeadmConfig, nil))
+	assert.NilError(t, Validate(certDir))
+
+	copyFile := func(from, to string) {
+		data, err := os.ReadFile(filepath.Join(certDir, from))
+		assert.NilError(t, err)
+		assert.NilError(t, os.WriteFile(filepath.Join(certDir, to), data, 0600))
+	}
+
+	// a missing key, a leaf certificate signed by the wrong ca, a key that doesn't belong to its certificate
+	// and an invalid public key are all reported
+	assert.NilError(t, os.Remove(filepath.Join(certDir, EtcdPeerKeyName)))
+	copyFile(APIServerKubeletClientCertName, FrontProxyClientCertName)
+	copyFile(APIServerKubeletClientKeyName, FrontProxyClientKeyName)
+	copyFile(APIServerEtcdClientKeyName, EtcdServerKeyName)
+	assert.NilError(t, os.WriteFile(filepath.Join(certDir, ServiceAccountPublicKeyName), []byte("invalid"), 0600))
+
+	err = Validate(certDir)
+	assert.ErrorContains(t, err, "read "+EtcdPeerKeyName)
+	assert.ErrorContains(t, err, "verify certificate "+FrontProxyClientCertName+" against "+FrontProxyCACertName)
+	assert.ErrorContains(t, err, "key "+EtcdServerKeyName+" does not match certificate "+EtcdServerCertName)
+	assert.ErrorContains(t, err, "parse public key "+ServiceAccountPublicKeyName)
+
+	aggregate, ok := err.(utilerrors.Aggregate)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(aggregate.Errors()), 4)
+}
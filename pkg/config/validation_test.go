@@ -1724,6 +1724,34 @@ func TestValidateToHostNamespaceSyncMappings(t *testing.T) {
 			},
 			checkErr: noErrExpected,
 		},
+		{
+			name: "Invalid: Host name prefix maps to the control plane namespace",
+			vclusterConfig: &VirtualClusterConfig{
+				Name: "test-vc",
+				Config: config.Config{
+					Sync: config.Sync{ToHost: config.SyncToHost{Namespaces: config.SyncToHostNamespaces{
+						Enabled:        true,
+						HostNamePrefix: "vcluster-",
+						Mappings:       config.FromHostMappings{ByName: map[string]string{"vns": "control-plane"}},
+					}}},
+				},
+			},
+			checkErr: expectErr("config.sync.toHost.namespaces.mappings.byName: host namespace mapping 'vcluster-control-plane' conflicts with control plane namespace 'vcluster-control-plane'"),
+		},
+		{
+			name: "Invalid: Host name prefix with wildcard",
+			vclusterConfig: &VirtualClusterConfig{
+				Name: "test-vc",
+				Config: config.Config{
+					Sync: config.Sync{ToHost: config.SyncToHost{Namespaces: config.SyncToHostNamespaces{
+						Enabled:        true,
+						HostNamePrefix: "team-*",
+						Mappings:       config.FromHostMappings{ByName: map[string]string{"vns": "hns"}},
+					}}},
+				},
+			},
+			checkErr: expectErr("config.sync.toHost.namespaces.hostNamePrefix: 'team-*' cannot contain a wildcard '*'"),
+		},
 	}
 
 	for _, tc := range testCases {
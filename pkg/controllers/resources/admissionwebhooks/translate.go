@@ -0,0 +1,78 @@
+package admissionwebhooks
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TranslateWebhookRulesToHost applies the given api group rewrite to the api groups of the webhook rules, so that
+// rules of a webhook configuration keep matching custom resources whose group was rewritten during import.
+// The wildcard group is kept as it is and the given rules are not modified.
+func TranslateWebhookRulesToHost(rules []admissionregistrationv1.RuleWithOperations, groupRewrite func(group string) string) []admissionregistrationv1.RuleWithOperations {
+	if rules == nil {
+		return nil
+	}
+
+	hostRules := make([]admissionregistrationv1.RuleWithOperations, 0, len(rules))
+	for _, rule := range rules {
+		hostRule := *rule.DeepCopy()
+		for i, group := range hostRule.APIGroups {
+			if group == "*" {
+				continue
+			}
+
+			hostRule.APIGroups[i] = groupRewrite(group)
+		}
+
+		hostRules = append(hostRules, hostRule)
+	}
+
+	return hostRules
+}
+
+// TranslateMutatingWebhookToHost translates the webhooks of a virtual MutatingWebhookConfiguration for the host. The
+// service of the client config is rewritten to the synced host service, where vNamespace is used if the service has
+// no namespace. The label keys of the namespace and object selectors are translated like the labels of synced
+// objects and the object selector additionally only matches objects of this vCluster. The api groups of the rules
+// are rewritten via groupRewrite, which is the api group rewrite of custom resources that were renamed during import.
+// A nil groupRewrite keeps all groups as they are. The reinvocation policy and all other fields are kept as they are. Webhooks can't be
+// translated if translate.UseAnnotationsForTopology is set, as their object selector would match objects of all
+// vClusters on the host.
+func TranslateMutatingWebhookToHost(ctx *synccontext.SyncContext, vNamespace string, mwc *admissionregistrationv1.MutatingWebhookConfiguration, groupRewrite func(group string) string) error {
+	if translate.UseAnnotationsForTopology && len(mwc.Webhooks) > 0 {
+		return fmt.Errorf("cannot sync mutating webhook configuration %s: the object selector can't be limited to this vCluster if annotations are used for the topology", mwc.Name)
+	}
+
+	for i := range mwc.Webhooks {
+		webhook := &mwc.Webhooks[i]
+		if service := webhook.ClientConfig.Service; service != nil && service.Name != "" {
+			if service.Namespace == "" {
+				service.Namespace = vNamespace
+			}
+
+			pName := translate.Default.HostName(ctx, service.Name, service.Namespace)
+			service.Name = pName.Name
+			service.Namespace = pName.Namespace
+		}
+
+		webhook.NamespaceSelector = translate.HostLabelSelectorNamespace(webhook.NamespaceSelector)
+		webhook.ObjectSelector = translateObjectSelector(webhook.ObjectSelector)
+		if groupRewrite != nil {
+			webhook.Rules = TranslateWebhookRulesToHost(webhook.Rules, groupRewrite)
+		}
+	}
+
+	return nil
+}
+
+func translateObjectSelector(selector *metav1.LabelSelector) *metav1.LabelSelector {
+	return translate.MergeLabelSelectors(translate.HostLabelSelector(selector), &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			translate.MarkerLabel: translate.VClusterName,
+		},
+	})
+}
@@ -0,0 +1,118 @@
+package admissionwebhooks
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestTranslateWebhookRulesToHost(t *testing.T) {
+	groupRewrite := func(group string) string {
+		if group == "example.com" {
+			return "example.com.my-vcluster"
+		}
+
+		return group
+	}
+
+	rules := []admissionregistrationv1.RuleWithOperations{
+		{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{"example.com", "apps"},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"widgets", "deployments"},
+			},
+		},
+		{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.OperationAll},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{"*"},
+				APIVersions: []string{"*"},
+				Resources:   []string{"*"},
+			},
+		},
+	}
+
+	hostRules := TranslateWebhookRulesToHost(rules, groupRewrite)
+	assert.DeepEqual(t, hostRules[0].APIGroups, []string{"example.com.my-vcluster", "apps"})
+	assert.DeepEqual(t, hostRules[0].Resources, []string{"widgets", "deployments"})
+	assert.DeepEqual(t, hostRules[1].APIGroups, []string{"*"})
+
+	// the virtual rules are not modified
+	assert.DeepEqual(t, rules[0].APIGroups, []string{"example.com", "apps"})
+	assert.Assert(t, TranslateWebhookRulesToHost(nil, groupRewrite) == nil)
+}
+
+func TestTranslateMutatingWebhookToHost(t *testing.T) {
+	groupRewrite := func(group string) string {
+		if group == "example.com" {
+			return "example.com.my-vcluster"
+		}
+
+		return group
+	}
+
+	mwc := &admissionregistrationv1.MutatingWebhookConfiguration{
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{
+				Name: "widgets.example.com",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Name:      "widget-webhook",
+						Namespace: "widgets",
+						Path:      ptr.To("/mutate"),
+					},
+					CABundle: []byte("ca-bundle"),
+				},
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"example.com"},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"widgets"},
+					},
+				}},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "a"},
+				},
+				ObjectSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"widget"}},
+					},
+				},
+				ReinvocationPolicy: ptr.To(admissionregistrationv1.IfNeededReinvocationPolicy),
+			},
+		},
+	}
+
+	// the object selector can't be limited to this vCluster without the marker label
+	translate.UseAnnotationsForTopology = true
+	err := TranslateMutatingWebhookToHost(nil, "widgets", mwc.DeepCopy(), groupRewrite)
+	translate.UseAnnotationsForTopology = false
+	assert.ErrorContains(t, err, "the object selector can't be limited to this vCluster")
+
+	assert.NilError(t, TranslateMutatingWebhookToHost(nil, "widgets", mwc, groupRewrite))
+	webhook := mwc.Webhooks[0]
+	pName := translate.Default.HostName(nil, "widget-webhook", "widgets")
+	assert.DeepEqual(t, webhook.ClientConfig.Service, &admissionregistrationv1.ServiceReference{
+		Name:      pName.Name,
+		Namespace: pName.Namespace,
+		Path:      ptr.To("/mutate"),
+	})
+	assert.DeepEqual(t, webhook.NamespaceSelector, &metav1.LabelSelector{
+		MatchLabels: map[string]string{translate.HostLabelNamespace("team"): "a"},
+	})
+	assert.DeepEqual(t, webhook.ObjectSelector, &metav1.LabelSelector{
+		MatchLabels: map[string]string{translate.MarkerLabel: translate.VClusterName},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: translate.HostLabel("app"), Operator: metav1.LabelSelectorOpIn, Values: []string{"widget"}},
+		},
+	})
+	assert.DeepEqual(t, webhook.Rules[0].APIGroups, []string{"example.com.my-vcluster"})
+	assert.Equal(t, *webhook.ReinvocationPolicy, admissionregistrationv1.IfNeededReinvocationPolicy)
+}
@@ -0,0 +1,20 @@
+package apiservices
+
+import (
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+)
+
+// TranslateAPIServiceToHost rewrites the service a virtual APIService is backed by to the synced host service,
+// so that the host apiserver can reach the aggregated api server. The caBundle and all other fields are kept,
+// local APIServices without a service are not changed.
+func TranslateAPIServiceToHost(ctx *synccontext.SyncContext, as *apiregistrationv1.APIService) {
+	if as.Spec.Service == nil || as.Spec.Service.Name == "" {
+		return
+	}
+
+	pName := translate.Default.HostName(ctx, as.Spec.Service.Name, as.Spec.Service.Namespace)
+	as.Spec.Service.Name = pName.Name
+	as.Spec.Service.Namespace = pName.Namespace
+}
@@ -0,0 +1,42 @@
+package apiservices
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestTranslateAPIServiceToHost(t *testing.T) {
+	as := &apiregistrationv1.APIService{
+		Spec: apiregistrationv1.APIServiceSpec{
+			Group:   "metrics.example.com",
+			Version: "v1beta1",
+			Service: &apiregistrationv1.ServiceReference{
+				Name:      "metrics-server",
+				Namespace: "monitoring",
+				Port:      ptr.To[int32](443),
+			},
+			CABundle:             []byte("ca-bundle"),
+			GroupPriorityMinimum: 100,
+			VersionPriority:      10,
+		},
+	}
+
+	TranslateAPIServiceToHost(nil, as)
+	pName := translate.Default.HostName(nil, "metrics-server", "monitoring")
+	assert.DeepEqual(t, as.Spec.Service, &apiregistrationv1.ServiceReference{
+		Name:      pName.Name,
+		Namespace: pName.Namespace,
+		Port:      ptr.To[int32](443),
+	})
+	assert.DeepEqual(t, as.Spec.CABundle, []byte("ca-bundle"))
+	assert.Equal(t, as.Spec.Group, "metrics.example.com")
+
+	// local api services are not backed by a service
+	local := &apiregistrationv1.APIService{Spec: apiregistrationv1.APIServiceSpec{Group: "apps", Version: "v1"}}
+	TranslateAPIServiceToHost(nil, local)
+	assert.Assert(t, local.Spec.Service == nil)
+}
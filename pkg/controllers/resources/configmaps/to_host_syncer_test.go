@@ -31,12 +31,13 @@ func TestSync(t *testing.T) {
 			Name:      translate.Default.HostName(nil, baseConfigMap.Name, baseConfigMap.Namespace).Name,
 			Namespace: "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          baseConfigMap.Name,
-				translate.NamespaceAnnotation:     baseConfigMap.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("ConfigMap").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, baseConfigMap.Name, baseConfigMap.Namespace).Name,
+				translate.NameAnnotation:             baseConfigMap.Name,
+				translate.NamespaceAnnotation:        baseConfigMap.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("ConfigMap").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, baseConfigMap.Name, baseConfigMap.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.NamespaceLabel: baseConfigMap.Namespace,
@@ -32,12 +32,13 @@ func TestSyncHostStorageClass(t *testing.T) {
 		Name:      "test-csistoragecapacity-x-test",
 		Namespace: "kube-system",
 		Annotations: map[string]string{
-			translate.NameAnnotation:          "test-csistoragecapacity",
-			translate.NamespaceAnnotation:     "test",
-			translate.UIDAnnotation:           "",
-			translate.KindAnnotation:          storagev1.SchemeGroupVersion.WithKind("CSIStorageCapacity").String(),
-			translate.HostNameAnnotation:      "test-csistoragecapacity-x-test",
-			translate.HostNamespaceAnnotation: "kube-system",
+			translate.NameAnnotation:             "test-csistoragecapacity",
+			translate.NamespaceAnnotation:        "test",
+			translate.UIDAnnotation:              "",
+			translate.KindAnnotation:             storagev1.SchemeGroupVersion.WithKind("CSIStorageCapacity").String(),
+			translate.TranslateVersionAnnotation: "1",
+			translate.HostNameAnnotation:         "test-csistoragecapacity-x-test",
+			translate.HostNamespaceAnnotation:    "kube-system",
 		},
 		Labels: map[string]string{
 			"vcluster.loft.sh/namespace": "test",
@@ -162,12 +163,13 @@ func TestSyncStorageClass(t *testing.T) {
 		Name:      "test-csistoragecapacity-x-test",
 		Namespace: "kube-system",
 		Annotations: map[string]string{
-			translate.NameAnnotation:          "test-csistoragecapacity",
-			translate.NamespaceAnnotation:     "test",
-			translate.UIDAnnotation:           "",
-			translate.KindAnnotation:          storagev1.SchemeGroupVersion.WithKind("CSIStorageCapacity").String(),
-			translate.HostNameAnnotation:      "test-csistoragecapacity-x-test",
-			translate.HostNamespaceAnnotation: "kube-system",
+			translate.NameAnnotation:             "test-csistoragecapacity",
+			translate.NamespaceAnnotation:        "test",
+			translate.UIDAnnotation:              "",
+			translate.KindAnnotation:             storagev1.SchemeGroupVersion.WithKind("CSIStorageCapacity").String(),
+			translate.TranslateVersionAnnotation: "1",
+			translate.HostNameAnnotation:         "test-csistoragecapacity-x-test",
+			translate.HostNamespaceAnnotation:    "kube-system",
 		},
 		Labels: map[string]string{
 			"vcluster.loft.sh/namespace": "test",
@@ -59,12 +59,13 @@ func TestExistingEndpoints(t *testing.T) {
 			Name:      translate.Default.HostName(nil, vEndpoints.Name, vEndpoints.Namespace).Name,
 			Namespace: "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          vEndpoints.Name,
-				translate.NamespaceAnnotation:     vEndpoints.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Service").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, vEndpoints.Name, vEndpoints.Namespace).Name,
+				translate.NameAnnotation:             vEndpoints.Name,
+				translate.NamespaceAnnotation:        vEndpoints.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Service").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, vEndpoints.Name, vEndpoints.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.NamespaceLabel: vEndpoints.Namespace,
@@ -76,12 +77,13 @@ func TestExistingEndpoints(t *testing.T) {
 			Name:      translate.Default.HostName(nil, vEndpoints.Name, vEndpoints.Namespace).Name,
 			Namespace: "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          vEndpoints.Name,
-				translate.NamespaceAnnotation:     vEndpoints.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Endpoints").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, vEndpoints.Name, vEndpoints.Namespace).Name,
+				translate.NameAnnotation:             vEndpoints.Name,
+				translate.NamespaceAnnotation:        vEndpoints.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Endpoints").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, vEndpoints.Name, vEndpoints.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.NamespaceLabel: vEndpoints.Namespace,
@@ -159,12 +161,13 @@ func TestSync(t *testing.T) {
 			Name:            translate.Default.HostName(nil, baseEndpoints.Name, baseEndpoints.Namespace).Name,
 			Namespace:       "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          baseEndpoints.Name,
-				translate.NamespaceAnnotation:     baseEndpoints.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Endpoints").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, baseEndpoints.Name, baseEndpoints.Namespace).Name,
+				translate.NameAnnotation:             baseEndpoints.Name,
+				translate.NamespaceAnnotation:        baseEndpoints.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Endpoints").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, baseEndpoints.Name, baseEndpoints.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.NamespaceLabel: baseEndpoints.Namespace,
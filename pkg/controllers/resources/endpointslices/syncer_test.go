@@ -1,12 +1,15 @@
 package endpointslices
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
 	"github.com/loft-sh/vcluster/pkg/syncer"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -59,12 +62,13 @@ func TestExistingEndpointSlices(t *testing.T) {
 			Name:      translate.Default.HostName(nil, vService.Name, vEndpointSlice.Namespace).Name,
 			Namespace: "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          vService.Name,
-				translate.NamespaceAnnotation:     vEndpointSlice.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Service").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, vService.Name, vEndpointSlice.Namespace).Name,
+				translate.NameAnnotation:             vService.Name,
+				translate.NamespaceAnnotation:        vEndpointSlice.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Service").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, vService.Name, vEndpointSlice.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.NamespaceLabel: vEndpointSlice.Namespace,
@@ -76,12 +80,13 @@ func TestExistingEndpointSlices(t *testing.T) {
 			Name:      translate.Default.HostName(nil, vEndpointSlice.Name, vEndpointSlice.Namespace).Name,
 			Namespace: "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          vEndpointSlice.Name,
-				translate.NamespaceAnnotation:     vEndpointSlice.Namespace,
-				translate.KindAnnotation:          discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.UIDAnnotation:           "",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, vEndpointSlice.Name, vEndpointSlice.Namespace).Name,
+				translate.NameAnnotation:             vEndpointSlice.Name,
+				translate.NamespaceAnnotation:        vEndpointSlice.Namespace,
+				translate.KindAnnotation:             discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.UIDAnnotation:              "",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, vEndpointSlice.Name, vEndpointSlice.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.K8sServiceNameLabel: translate.Default.HostName(nil, vEndpointSlice.Name, vEndpointSlice.Namespace).Name,
@@ -158,12 +163,13 @@ func TestSync(t *testing.T) {
 			Name:            translate.Default.HostName(nil, baseEndpointSlice.Name, baseEndpointSlice.Namespace).Name,
 			Namespace:       "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          baseEndpointSlice.Name,
-				translate.NamespaceAnnotation:     baseEndpointSlice.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, baseEndpointSlice.Name, baseEndpointSlice.Namespace).Name,
+				translate.NameAnnotation:             baseEndpointSlice.Name,
+				translate.NamespaceAnnotation:        baseEndpointSlice.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, baseEndpointSlice.Name, baseEndpointSlice.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.NamespaceLabel:      baseEndpointSlice.Namespace,
@@ -234,3 +240,49 @@ func TestSync(t *testing.T) {
 		},
 	})
 }
+
+func TestTranslateEndpointSliceToHost(t *testing.T) {
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	syncCtx := syncertesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient).ToSyncContext("endpointslices")
+
+	eps := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-eps",
+			Namespace: "test",
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses: []string{"10.0.0.1"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod-a", Namespace: "test"},
+			},
+			{
+				Addresses: []string{"10.0.0.2"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod-b", Namespace: "other"},
+			},
+			{
+				Addresses: []string{"10.0.0.3"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod-c"},
+			},
+			{
+				Addresses: []string{"10.0.0.4"},
+				TargetRef: &corev1.ObjectReference{Kind: "Node", Name: "node-a"},
+			},
+			{
+				Addresses: []string{"10.0.0.5"},
+			},
+		},
+	}
+
+	TranslateEndpointSliceToHost(syncCtx, eps.Namespace, eps)
+
+	assert.DeepEqual(t, eps.Endpoints[0].TargetRef, &corev1.ObjectReference{Kind: "Pod", Name: translate.Default.HostName(nil, "pod-a", "test").Name, Namespace: testingutil.DefaultTestTargetNamespace})
+	assert.DeepEqual(t, eps.Endpoints[1].TargetRef, &corev1.ObjectReference{Kind: "Pod", Name: translate.Default.HostName(nil, "pod-b", "other").Name, Namespace: testingutil.DefaultTestTargetNamespace})
+	assert.DeepEqual(t, eps.Endpoints[2].TargetRef, &corev1.ObjectReference{Kind: "Pod", Name: translate.Default.HostName(nil, "pod-c", "test").Name, Namespace: testingutil.DefaultTestTargetNamespace})
+	assert.DeepEqual(t, eps.Endpoints[3].TargetRef, &corev1.ObjectReference{Kind: "Node", Name: "node-a"})
+	assert.Assert(t, eps.Endpoints[4].TargetRef == nil)
+	for i, ep := range eps.Endpoints {
+		assert.DeepEqual(t, ep.Addresses, []string{fmt.Sprintf("10.0.0.%d", i+1)})
+	}
+}
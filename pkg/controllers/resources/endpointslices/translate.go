@@ -10,36 +10,47 @@ import (
 )
 
 func (s *endpointSliceSyncer) translate(ctx *synccontext.SyncContext, vObj client.Object) *discoveryv1.EndpointSlice {
-	endpointSlice := translate.HostMetadata(vObj.(*discoveryv1.EndpointSlice),
+	endpointSlice := translate.HostMetadataWithContext(ctx, vObj.(*discoveryv1.EndpointSlice),
 		s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj),
 		s.excludedAnnotations...)
 
 	virtualSvcName := endpointSlice.GetLabels()[translate.K8sServiceNameLabel]
-	vcName := endpointSlice.GetLabels()[translate.MarkerLabel]
-	namespace := endpointSlice.GetLabels()[translate.NamespaceLabel]
-	hostSvcName := translateSvcName(virtualSvcName, namespace, vcName)
+	hostSvcName := translateSvcName(virtualSvcName, vObj.GetNamespace(), translate.VClusterName)
 
 	// in case of selector-less service, we need to add "kubernetes.io/service-name" label manually
 	endpointSlice.Labels[translate.K8sServiceNameLabel] = hostSvcName
-	s.translateSpec(ctx, endpointSlice)
+	s.translateSpec(ctx, vObj.GetNamespace(), endpointSlice)
 	return endpointSlice
 }
 
-func (s *endpointSliceSyncer) translateSpec(ctx *synccontext.SyncContext, endpointSlice *discoveryv1.EndpointSlice) {
-	// translate the endpoints
-	for i, ep := range endpointSlice.Endpoints {
-		if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
-			nameAndNamespace := mappings.VirtualToHost(ctx, ep.TargetRef.Name, ep.TargetRef.Namespace, mappings.Pods())
-			endpointSlice.Endpoints[i].TargetRef.Name = nameAndNamespace.Name
-			endpointSlice.Endpoints[i].TargetRef.Namespace = nameAndNamespace.Namespace
+func (s *endpointSliceSyncer) translateSpec(ctx *synccontext.SyncContext, vNamespace string, endpointSlice *discoveryv1.EndpointSlice) {
+	TranslateEndpointSliceToHost(ctx, vNamespace, endpointSlice)
+}
+
+// TranslateEndpointSliceToHost rewrites the pod target references of the given endpoint slice to
+// their host name and namespace. Target references without a namespace are resolved against vNamespace.
+// Addresses are left untouched, as they already point to the pod ips.
+func TranslateEndpointSliceToHost(ctx *synccontext.SyncContext, vNamespace string, eps *discoveryv1.EndpointSlice) {
+	for i, ep := range eps.Endpoints {
+		if ep.TargetRef == nil || ep.TargetRef.Kind != "Pod" {
+			continue
 		}
+
+		targetNamespace := ep.TargetRef.Namespace
+		if targetNamespace == "" {
+			targetNamespace = vNamespace
+		}
+
+		nameAndNamespace := mappings.VirtualToHost(ctx, ep.TargetRef.Name, targetNamespace, mappings.Pods())
+		eps.Endpoints[i].TargetRef.Name = nameAndNamespace.Name
+		eps.Endpoints[i].TargetRef.Namespace = nameAndNamespace.Namespace
 	}
 }
 
 func (s *endpointSliceSyncer) translateUpdate(ctx *synccontext.SyncContext, pObj, vObj *discoveryv1.EndpointSlice) error {
 	// check endpointSlice.Endpoints
 	translated := vObj.DeepCopy()
-	s.translateSpec(ctx, translated)
+	s.translateSpec(ctx, vObj.GetNamespace(), translated)
 	pObj.Endpoints = translated.Endpoints
 	return nil
 }
@@ -0,0 +1,24 @@
+package horizontalpodautoscalers
+
+import (
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// TranslateSpecToHost rewrites the object names referenced by a virtual horizontal pod autoscaler spec, so that the
+// host horizontal pod autoscaler controller scales and reads metrics from the synced host objects. The apiVersion and
+// kind of the references are kept as they are.
+func TranslateSpecToHost(ctx *synccontext.SyncContext, vNamespace string, spec *autoscalingv2.HorizontalPodAutoscalerSpec) {
+	if spec.ScaleTargetRef.Name != "" {
+		spec.ScaleTargetRef.Name = translate.Default.HostName(ctx, spec.ScaleTargetRef.Name, vNamespace).Name
+	}
+
+	for i := range spec.Metrics {
+		if spec.Metrics[i].Object == nil || spec.Metrics[i].Object.DescribedObject.Name == "" {
+			continue
+		}
+
+		spec.Metrics[i].Object.DescribedObject.Name = translate.Default.HostName(ctx, spec.Metrics[i].Object.DescribedObject.Name, vNamespace).Name
+	}
+}
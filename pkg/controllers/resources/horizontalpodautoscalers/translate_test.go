@@ -0,0 +1,57 @@
+package horizontalpodautoscalers
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestTranslateSpecToHost(t *testing.T) {
+	spec := &autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "my-deployment",
+		},
+		Metrics: []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.ObjectMetricSourceType,
+				Object: &autoscalingv2.ObjectMetricSource{
+					DescribedObject: autoscalingv2.CrossVersionObjectReference{
+						APIVersion: "networking.k8s.io/v1",
+						Kind:       "Ingress",
+						Name:       "my-ingress",
+					},
+					Metric: autoscalingv2.MetricIdentifier{Name: "requests-per-second"},
+					Target: autoscalingv2.MetricTarget{
+						Type:  autoscalingv2.ValueMetricType,
+						Value: resource.NewQuantity(10, resource.DecimalSI),
+					},
+				},
+			},
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: "cpu",
+				},
+			},
+		},
+	}
+
+	TranslateSpecToHost(nil, "test", spec)
+	assert.DeepEqual(t, spec.ScaleTargetRef, autoscalingv2.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       translate.Default.HostName(nil, "my-deployment", "test").Name,
+	})
+	assert.DeepEqual(t, spec.Metrics[0].Object.DescribedObject, autoscalingv2.CrossVersionObjectReference{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Name:       translate.Default.HostName(nil, "my-ingress", "test").Name,
+	})
+	assert.Equal(t, spec.Metrics[0].Object.Metric.Name, "requests-per-second")
+	assert.Assert(t, spec.Metrics[1].Object == nil)
+}
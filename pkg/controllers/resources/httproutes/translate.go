@@ -0,0 +1,101 @@
+package httproutes
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// GatewayGroup is the api group of the gateway api
+const GatewayGroup = "gateway.networking.k8s.io"
+
+// TranslateHTTPRouteToHost rewrites the service backend references and gateway parent references of a virtual
+// HTTPRoute or GRPCRoute to the synced host objects. As the gateway api types are not part of this module, the route
+// is handled as unstructured object, but its kind has to be registered in scheme.Scheme. Service backend references
+// are translated with the services mapper. References to other kinds are kept as they are.
+func TranslateHTTPRouteToHost(ctx *synccontext.SyncContext, vNamespace string, route *unstructured.Unstructured) error {
+	gvk := route.GroupVersionKind()
+	if gvk.Group != GatewayGroup || (gvk.Kind != "HTTPRoute" && gvk.Kind != "GRPCRoute") {
+		return fmt.Errorf("unsupported route kind %s", gvk.String())
+	} else if !scheme.Scheme.Recognizes(gvk) {
+		return fmt.Errorf("gateway api kind %s is not registered in the scheme", gvk.String())
+	}
+
+	parentRefs, _, err := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if err != nil {
+		return fmt.Errorf("parse parentRefs: %w", err)
+	}
+	if len(parentRefs) > 0 {
+		parentRefs = translateRefs(vNamespace, parentRefs, GatewayGroup, "Gateway", func(name, namespace string) types.NamespacedName {
+			return translate.Default.HostName(ctx, name, namespace)
+		})
+		if err := unstructured.SetNestedSlice(route.Object, parentRefs, "spec", "parentRefs"); err != nil {
+			return fmt.Errorf("set parentRefs: %w", err)
+		}
+	}
+
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if err != nil {
+		return fmt.Errorf("parse rules: %w", err)
+	}
+	for i, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		backendRefs, _, err := unstructured.NestedSlice(ruleMap, "backendRefs")
+		if err != nil {
+			return fmt.Errorf("parse backendRefs of rule %d: %w", i, err)
+		} else if len(backendRefs) == 0 {
+			continue
+		}
+
+		ruleMap["backendRefs"] = translateRefs(vNamespace, backendRefs, "", "Service", func(name, namespace string) types.NamespacedName {
+			return mappings.VirtualToHost(ctx, name, namespace, mappings.Services())
+		})
+	}
+	if len(rules) > 0 {
+		if err := unstructured.SetNestedSlice(route.Object, rules, "spec", "rules"); err != nil {
+			return fmt.Errorf("set rules: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// translateRefs rewrites the name and namespace of all references of the given group and kind, which are
+// the defaults if the reference doesn't specify a group or kind, with hostName.
+func translateRefs(vNamespace string, refs []interface{}, group, kind string, hostName func(name, namespace string) types.NamespacedName) []interface{} {
+	for _, ref := range refs {
+		refMap, ok := ref.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		refGroup, hasGroup, _ := unstructured.NestedString(refMap, "group")
+		refKind, hasKind, _ := unstructured.NestedString(refMap, "kind")
+		name, _, _ := unstructured.NestedString(refMap, "name")
+		if (hasGroup && refGroup != group) || (hasKind && refKind != kind) || name == "" {
+			continue
+		}
+
+		namespace, hasNamespace, _ := unstructured.NestedString(refMap, "namespace")
+		if !hasNamespace || namespace == "" {
+			namespace = vNamespace
+		}
+
+		pName := hostName(name, namespace)
+		refMap["name"] = pName.Name
+		if hasNamespace && namespace != "" {
+			refMap["namespace"] = pName.Namespace
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,87 @@
+package httproutes
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTranslateHTTPRouteToHost(t *testing.T) {
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	syncCtx := syncertesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient).ToSyncContext("httproutes")
+	serviceName := func(name, namespace string) string {
+		return mappings.VirtualToHostName(syncCtx, name, namespace, mappings.Services())
+	}
+
+	// routes are only translated if the gateway api kind is registered in the scheme
+	grpcRoute := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "gateway.networking.k8s.io/v1", "kind": "GRPCRoute"}}
+	assert.ErrorContains(t, TranslateHTTPRouteToHost(syncCtx, "test", grpcRoute), "gateway api kind gateway.networking.k8s.io/v1, Kind=GRPCRoute is not registered in the scheme")
+	scheme.Scheme.AddKnownTypeWithName(schema.GroupVersionKind{Group: GatewayGroup, Version: "v1", Kind: "HTTPRoute"}, &unstructured.Unstructured{})
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]interface{}{"name": "my-route", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{"name": "my-gateway"},
+			},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "web", "port": int64(80), "weight": int64(90)},
+						map[string]interface{}{"name": "web-canary", "namespace": "canary", "port": int64(80), "weight": int64(10)},
+						map[string]interface{}{"group": "example.com", "kind": "Bucket", "name": "assets"},
+					},
+				},
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{"kind": "Service", "name": "api", "port": int64(8080)},
+					},
+				},
+			},
+		},
+	}}
+
+	assert.NilError(t, TranslateHTTPRouteToHost(syncCtx, "test", route))
+
+	parentRefs, _, err := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parentRefs, []interface{}{
+		map[string]interface{}{"name": translate.Default.HostName(nil, "my-gateway", "test").Name},
+	})
+
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rules, []interface{}{
+		map[string]interface{}{
+			"backendRefs": []interface{}{
+				map[string]interface{}{"name": serviceName("web", "test"), "port": int64(80), "weight": int64(90)},
+				map[string]interface{}{
+					"name":      serviceName("web-canary", "canary"),
+					"namespace": mappings.VirtualToHost(syncCtx, "web-canary", "canary", mappings.Services()).Namespace,
+					"port":      int64(80),
+					"weight":    int64(10),
+				},
+				map[string]interface{}{"group": "example.com", "kind": "Bucket", "name": "assets"},
+			},
+		},
+		map[string]interface{}{
+			"backendRefs": []interface{}{
+				map[string]interface{}{"kind": "Service", "name": serviceName("api", "test"), "port": int64(8080)},
+			},
+		},
+	})
+
+	// other kinds are rejected
+	ingress := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "networking.k8s.io/v1", "kind": "Ingress"}}
+	assert.ErrorContains(t, TranslateHTTPRouteToHost(syncCtx, "test", ingress), "unsupported route kind")
+}
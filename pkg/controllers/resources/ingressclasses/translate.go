@@ -0,0 +1,30 @@
+package ingressclasses
+
+import (
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// TranslateIngressClassToHost rewrites the parameters reference of a virtual IngressClass to the synced host
+// parameters object, so that the host ingress controller can read it. Namespaced parameters are translated via
+// HostName, cluster scoped parameters via HostNameCluster. The controller is kept as it is.
+func TranslateIngressClassToHost(ctx *synccontext.SyncContext, ic *networkingv1.IngressClass) {
+	parameters := ic.Spec.Parameters
+	if parameters == nil || parameters.Name == "" {
+		return
+	}
+
+	if parameters.Scope != nil && *parameters.Scope == networkingv1.IngressClassParametersReferenceScopeNamespace {
+		if parameters.Namespace == nil || *parameters.Namespace == "" {
+			return
+		}
+
+		pName := translate.Default.HostName(ctx, parameters.Name, *parameters.Namespace)
+		parameters.Name = pName.Name
+		parameters.Namespace = &pName.Namespace
+		return
+	}
+
+	parameters.Name = translate.Default.HostNameCluster(parameters.Name)
+}
@@ -0,0 +1,46 @@
+package ingressclasses
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestTranslateIngressClassToHost(t *testing.T) {
+	ic := &networkingv1.IngressClass{
+		Spec: networkingv1.IngressClassSpec{
+			Controller: "example.com/ingress-controller",
+			Parameters: &networkingv1.IngressClassParametersReference{
+				APIGroup:  ptr.To("k8s.example.com"),
+				Kind:      "IngressParameters",
+				Name:      "external-lb",
+				Scope:     ptr.To(networkingv1.IngressClassParametersReferenceScopeNamespace),
+				Namespace: ptr.To("ingress"),
+			},
+		},
+	}
+
+	TranslateIngressClassToHost(nil, ic)
+	pName := translate.Default.HostName(nil, "external-lb", "ingress")
+	assert.DeepEqual(t, ic.Spec.Parameters, &networkingv1.IngressClassParametersReference{
+		APIGroup:  ptr.To("k8s.example.com"),
+		Kind:      "IngressParameters",
+		Name:      pName.Name,
+		Scope:     ptr.To(networkingv1.IngressClassParametersReferenceScopeNamespace),
+		Namespace: ptr.To(pName.Namespace),
+	})
+	assert.Equal(t, ic.Spec.Controller, "example.com/ingress-controller")
+
+	// cluster scoped parameters
+	clusterIC := &networkingv1.IngressClass{
+		Spec: networkingv1.IngressClassSpec{
+			Parameters: &networkingv1.IngressClassParametersReference{Kind: "IngressParameters", Name: "external-lb"},
+		},
+	}
+	TranslateIngressClassToHost(nil, clusterIC)
+	assert.Equal(t, clusterIC.Spec.Parameters.Name, translate.Default.HostNameCluster("external-lb"))
+	assert.Assert(t, clusterIC.Spec.Parameters.Namespace == nil)
+}
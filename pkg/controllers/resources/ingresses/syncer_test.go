@@ -108,12 +108,13 @@ func TestSync(t *testing.T) {
 		Name:      translate.Default.HostName(nil, "testingress", "test").Name,
 		Namespace: "test",
 		Annotations: map[string]string{
-			translate.NameAnnotation:          vObjectMeta.Name,
-			translate.NamespaceAnnotation:     vObjectMeta.Namespace,
-			translate.UIDAnnotation:           "",
-			translate.KindAnnotation:          networkingv1.SchemeGroupVersion.WithKind("Ingress").String(),
-			translate.HostNamespaceAnnotation: "test",
-			translate.HostNameAnnotation:      translate.Default.HostName(nil, "testingress", "test").Name,
+			translate.NameAnnotation:             vObjectMeta.Name,
+			translate.NamespaceAnnotation:        vObjectMeta.Namespace,
+			translate.UIDAnnotation:              "",
+			translate.KindAnnotation:             networkingv1.SchemeGroupVersion.WithKind("Ingress").String(),
+			translate.TranslateVersionAnnotation: "1",
+			translate.HostNamespaceAnnotation:    "test",
+			translate.HostNameAnnotation:         translate.Default.HostName(nil, "testingress", "test").Name,
 		},
 		Labels: map[string]string{
 			translate.MarkerLabel:    translate.VClusterName,
@@ -350,6 +351,7 @@ func TestSync(t *testing.T) {
 								"vcluster.loft.sh/object-namespace":           baseIngress.Namespace,
 								translate.UIDAnnotation:                       "",
 								translate.KindAnnotation:                      networkingv1.SchemeGroupVersion.WithKind("Ingress").String(),
+								translate.TranslateVersionAnnotation:          "1",
 								translate.HostNamespaceAnnotation:             createdIngress.Namespace,
 								translate.HostNameAnnotation:                  createdIngress.Name,
 							},
@@ -426,6 +428,7 @@ func TestSync(t *testing.T) {
 								"vcluster.loft.sh/object-namespace":                              baseIngress.Namespace,
 								translate.UIDAnnotation:                                          "",
 								translate.KindAnnotation:                                         networkingv1.SchemeGroupVersion.WithKind("Ingress").String(),
+								translate.TranslateVersionAnnotation:                             "1",
 								translate.HostNamespaceAnnotation:                                createdIngress.Namespace,
 								translate.HostNameAnnotation:                                     createdIngress.Name,
 								"alb.ingress.kubernetes.io/actions.testservice-x-test-x-suffix":  `{"forwardConfig":{"targetGroups":[{"serviceName":"nginx-service-x-test-x-suffix","servicePort":"80","weight":100}]}}`,
@@ -505,6 +508,7 @@ func TestSync(t *testing.T) {
 								"vcluster.loft.sh/object-namespace":           baseIngress.Namespace,
 								translate.UIDAnnotation:                       "",
 								translate.KindAnnotation:                      networkingv1.SchemeGroupVersion.WithKind("Ingress").String(),
+								translate.TranslateVersionAnnotation:          "1",
 								translate.HostNamespaceAnnotation:             createdIngress.Namespace,
 								translate.HostNameAnnotation:                  createdIngress.Name,
 							},
@@ -22,7 +22,7 @@ const (
 )
 
 func (s *ingressSyncer) translate(ctx *synccontext.SyncContext, vIngress *networkingv1.Ingress) (*networkingv1.Ingress, error) {
-	newIngress := translate.HostMetadata(vIngress, s.VirtualToHost(ctx, types.NamespacedName{Name: vIngress.Name, Namespace: vIngress.Namespace}, vIngress), s.excludedAnnotations...)
+	newIngress := translate.HostMetadataWithContext(ctx, vIngress, s.VirtualToHost(ctx, types.NamespacedName{Name: vIngress.Name, Namespace: vIngress.Namespace}, vIngress), s.excludedAnnotations...)
 	newIngress.Spec = *translateSpec(ctx, vIngress.Namespace, &vIngress.Spec)
 	newIngress.Annotations = updateAnnotations(ctx, newIngress.Annotations, vIngress.Namespace)
 	return newIngress, nil
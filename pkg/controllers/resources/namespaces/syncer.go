@@ -3,7 +3,6 @@ package namespaces
 import (
 	"fmt"
 
-	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/mappings"
 	"github.com/loft-sh/vcluster/pkg/patcher"
 	"github.com/loft-sh/vcluster/pkg/pro"
@@ -11,6 +10,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	namespaceutil "github.com/loft-sh/vcluster/pkg/util/namespaces"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,8 +37,9 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 	for k, v := range ctx.Config.Sync.ToHost.Namespaces.ExtraLabels {
 		namespaceLabels[k] = v
 	}
-	namespaceLabels[constants.VClusterNameAnnotation] = ctx.Config.Name
-	namespaceLabels[constants.VClusterNamespaceAnnotation] = ctx.CurrentNamespace
+	for k, v := range namespaceutil.OwnedNamespaceLabels(ctx.Config.Name, ctx.CurrentNamespace) {
+		namespaceLabels[k] = v
+	}
 
 	return &namespaceSyncer{
 		GenericTranslator:          translator.NewGenericTranslator(ctx, "namespace", &corev1.Namespace{}, mapper),
@@ -79,9 +80,18 @@ func (s *namespaceSyncer) SyncToHost(ctx *synccontext.SyncContext, event *syncco
 	}
 
 	newNamespace := s.translateToHost(ctx, event.Virtual)
+	err := translate.ValidateHostNamespace(ctx, event.Virtual.Name, newNamespace.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	err = translate.ValidateHostNamespaceOwner(ctx, event.Virtual.Name, newNamespace.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	ctx.Log.Infof("create physical namespace %s", newNamespace.Name)
 
-	err := pro.ApplyPatchesHostObject(ctx, nil, newNamespace, event.Virtual, ctx.Config.Sync.ToHost.Namespaces.Patches, false)
+	err = pro.ApplyPatchesHostObject(ctx, nil, newNamespace, event.Virtual, ctx.Config.Sync.ToHost.Namespaces.Patches, false)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
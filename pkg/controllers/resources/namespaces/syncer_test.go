@@ -0,0 +1,113 @@
+package namespaces
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/pro"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// byNameMapper maps virtual namespaces to host namespaces by the configured sync.toHost.namespaces.mappings
+type byNameMapper struct {
+	mappings map[string]string
+}
+
+func (m *byNameMapper) Migrate(_ *synccontext.RegisterContext, _ synccontext.Mapper) error {
+	return nil
+}
+
+func (m *byNameMapper) GroupVersionKind() schema.GroupVersionKind {
+	return corev1.SchemeGroupVersion.WithKind("Namespace")
+}
+
+func (m *byNameMapper) VirtualToHost(_ *synccontext.SyncContext, req types.NamespacedName, _ client.Object) types.NamespacedName {
+	return types.NamespacedName{Name: m.mappings[req.Name]}
+}
+
+func (m *byNameMapper) HostToVirtual(_ *synccontext.SyncContext, req types.NamespacedName, _ client.Object) types.NamespacedName {
+	for vNamespace, pNamespace := range m.mappings {
+		if pNamespace == req.Name {
+			return types.NamespacedName{Name: vNamespace}
+		}
+	}
+
+	return types.NamespacedName{}
+}
+
+func (m *byNameMapper) IsManaged(_ *synccontext.SyncContext, pObj client.Object) (bool, error) {
+	return m.HostToVirtual(nil, types.NamespacedName{Name: pObj.GetName()}, pObj).Name != "", nil
+}
+
+func TestSyncToHostMappedNamespace(t *testing.T) {
+	defer func(getNamespaceMapper func(*synccontext.RegisterContext, synccontext.Mapper) (synccontext.Mapper, error)) {
+		pro.GetNamespaceMapper = getNamespaceMapper
+	}(pro.GetNamespaceMapper)
+	pro.GetNamespaceMapper = func(ctx *synccontext.RegisterContext, _ synccontext.Mapper) (synccontext.Mapper, error) {
+		return &byNameMapper{mappings: ctx.Config.Sync.ToHost.Namespaces.Mappings.ByName}, nil
+	}
+
+	pTeamA := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "host-team-a",
+			Annotations: map[string]string{
+				translate.NameAnnotation: "team-a",
+			},
+		},
+	}
+	vTeamB := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-b",
+		},
+	}
+	vNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "control-plane",
+		},
+	}
+
+	syncertesting.RunTestsWithContext(t, func(vConfig *config.VirtualClusterConfig, pClient *testingutil.FakeIndexClient, vClient *testingutil.FakeIndexClient) *synccontext.RegisterContext {
+		vConfig.Sync.ToHost.Namespaces.Enabled = true
+		vConfig.Sync.ToHost.Namespaces.Mappings.ByName = map[string]string{
+			"control-plane": testingutil.DefaultTestCurrentNamespace,
+			"team-b":        "host-team-a",
+		}
+		return syncertesting.NewFakeRegisterContext(vConfig, pClient, vClient)
+	}, []*syncertesting.SyncTest{
+		{
+			Name:                "Mapping to the vCluster namespace is rejected",
+			InitialVirtualState: []runtime.Object{vNamespace.DeepCopy()},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Namespace"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := syncertesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*namespaceSyncer).SyncToHost(syncCtx, synccontext.NewSyncToHostEvent(vNamespace.DeepCopy()))
+				assert.ErrorContains(t, err, "virtual namespace control-plane is mapped to host namespace vcluster, which is the namespace vCluster is running in")
+			},
+		},
+		{
+			Name:                 "Mapping to a host namespace of another virtual namespace is rejected",
+			InitialVirtualState:  []runtime.Object{vTeamB.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pTeamA.DeepCopy()},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Namespace"): {pTeamA.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := syncertesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*namespaceSyncer).SyncToHost(syncCtx, synccontext.NewSyncToHostEvent(vTeamB.DeepCopy()))
+				assert.ErrorContains(t, err, "virtual namespace team-b is mapped to host namespace host-team-a, which is already used by virtual namespace team-a")
+			},
+		},
+	})
+}
@@ -20,7 +20,7 @@ func (s *namespaceSyncer) applyNamespaceLabels(ns *corev1.Namespace) *corev1.Nam
 }
 
 func (s *namespaceSyncer) translateToHost(ctx *synccontext.SyncContext, vObj client.Object) *corev1.Namespace {
-	newNamespace := translate.HostMetadata(vObj.(*corev1.Namespace), s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName()}, vObj), s.excludedAnnotations...)
+	newNamespace := translate.HostMetadataWithContext(ctx, vObj.(*corev1.Namespace), s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName()}, vObj), s.excludedAnnotations...)
 	return s.applyNamespaceLabels(newNamespace)
 }
 
@@ -68,12 +68,13 @@ func TestSync(t *testing.T) {
 		Name:      translate.Default.HostName(nil, "testnetworkpolicy", "test").Name,
 		Namespace: "test",
 		Annotations: map[string]string{
-			translate.NameAnnotation:          vObjectMeta.Name,
-			translate.NamespaceAnnotation:     vObjectMeta.Namespace,
-			translate.UIDAnnotation:           "",
-			translate.KindAnnotation:          networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy").String(),
-			translate.HostNameAnnotation:      translate.Default.HostName(nil, "testnetworkpolicy", "test").Name,
-			translate.HostNamespaceAnnotation: "test",
+			translate.NameAnnotation:             vObjectMeta.Name,
+			translate.NamespaceAnnotation:        vObjectMeta.Namespace,
+			translate.UIDAnnotation:              "",
+			translate.KindAnnotation:             networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy").String(),
+			translate.TranslateVersionAnnotation: "1",
+			translate.HostNameAnnotation:         translate.Default.HostName(nil, "testnetworkpolicy", "test").Name,
+			translate.HostNamespaceAnnotation:    "test",
 		},
 		Labels: map[string]string{
 			translate.MarkerLabel:    translate.VClusterName,
@@ -206,12 +207,10 @@ func TestSync(t *testing.T) {
 						{
 							Key:      translate.HostLabel("pod-expr-key"),
 							Operator: metav1.LabelSelectorOpExists,
-							Values:   []string{"some-pod-key"},
 						},
 						{
 							Key:      translate.HostLabelNamespace("ns-expr-key"),
 							Operator: metav1.LabelSelectorOpDoesNotExist,
-							Values:   []string{"forbidden-ns-key"},
 						},
 					},
 				},
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
 	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
@@ -29,12 +30,13 @@ func TestSync(t *testing.T) {
 		Name:      translate.Default.HostName(nil, "testpvc", "testns").Name,
 		Namespace: "test",
 		Annotations: map[string]string{
-			translate.NameAnnotation:          vObjectMeta.Name,
-			translate.NamespaceAnnotation:     vObjectMeta.Namespace,
-			translate.UIDAnnotation:           "",
-			translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim").String(),
-			translate.HostNamespaceAnnotation: "test",
-			translate.HostNameAnnotation:      translate.Default.HostName(nil, "testpvc", "testns").Name,
+			translate.NameAnnotation:             vObjectMeta.Name,
+			translate.NamespaceAnnotation:        vObjectMeta.Namespace,
+			translate.UIDAnnotation:              "",
+			translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim").String(),
+			translate.TranslateVersionAnnotation: "1",
+			translate.HostNamespaceAnnotation:    "test",
+			translate.HostNameAnnotation:         translate.Default.HostName(nil, "testpvc", "testns").Name,
 		},
 		Labels: map[string]string{
 			translate.MarkerLabel:    translate.VClusterName,
@@ -79,13 +81,14 @@ func TestSync(t *testing.T) {
 			Name:      pObjectMeta.Name,
 			Namespace: pObjectMeta.Namespace,
 			Annotations: map[string]string{
-				translate.NameAnnotation:          vObjectMeta.Name,
-				translate.NamespaceAnnotation:     vObjectMeta.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim").String(),
-				translate.HostNamespaceAnnotation: pObjectMeta.Namespace,
-				translate.HostNameAnnotation:      pObjectMeta.Name,
-				"otherAnnotationKey":              "update this",
+				translate.NameAnnotation:             vObjectMeta.Name,
+				translate.NamespaceAnnotation:        vObjectMeta.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    pObjectMeta.Namespace,
+				translate.HostNameAnnotation:         pObjectMeta.Name,
+				"otherAnnotationKey":                 "update this",
 			},
 			Labels: pObjectMeta.Labels,
 		},
@@ -98,15 +101,16 @@ func TestSync(t *testing.T) {
 			Name:      pObjectMeta.Name,
 			Namespace: pObjectMeta.Namespace,
 			Annotations: map[string]string{
-				translate.NameAnnotation:          vObjectMeta.Name,
-				translate.NamespaceAnnotation:     vObjectMeta.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim").String(),
-				translate.HostNameAnnotation:      pObjectMeta.Name,
-				translate.HostNamespaceAnnotation: pObjectMeta.Namespace,
-				bindCompletedAnnotation:           "testannotation",
-				boundByControllerAnnotation:       "testannotation2",
-				storageProvisionerAnnotation:      "testannotation3",
+				translate.NameAnnotation:             vObjectMeta.Name,
+				translate.NamespaceAnnotation:        vObjectMeta.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNameAnnotation:         pObjectMeta.Name,
+				translate.HostNamespaceAnnotation:    pObjectMeta.Namespace,
+				bindCompletedAnnotation:              "testannotation",
+				boundByControllerAnnotation:          "testannotation2",
+				storageProvisionerAnnotation:         "testannotation3",
 			},
 			Labels: pObjectMeta.Labels,
 		},
@@ -356,3 +360,28 @@ func TestSync(t *testing.T) {
 		},
 	})
 }
+
+func TestTranslateVolumeName(t *testing.T) {
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	registerCtx := syncertesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient)
+	registerCtx.Config.Sync.ToHost.PersistentVolumes.Enabled = true
+	syncer, err := New(registerCtx)
+	assert.NilError(t, err)
+	syncCtx := registerCtx.ToSyncContext("persistentvolumeclaims")
+
+	// a pvc bound to a specific persistent volume references the host persistent volume
+	pPVC, err := syncer.(*persistentVolumeClaimSyncer).translate(syncCtx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "static", Namespace: "test"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "my-volume"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, pPVC.Spec.VolumeName, translate.Default.HostNameCluster("my-volume"))
+
+	// dynamically provisioned pvcs keep an empty volume name
+	pPVC, err = syncer.(*persistentVolumeClaimSyncer).translate(syncCtx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynamic", Namespace: "test"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, pPVC.Spec.VolumeName, "")
+}
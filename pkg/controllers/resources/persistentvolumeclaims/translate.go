@@ -14,7 +14,7 @@ import (
 var deprecatedStorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
 
 func (s *persistentVolumeClaimSyncer) translate(ctx *synccontext.SyncContext, vPvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
-	pPVC := translate.HostMetadata(vPvc, s.VirtualToHost(ctx, types.NamespacedName{Name: vPvc.GetName(), Namespace: vPvc.GetNamespace()}, vPvc), s.excludedAnnotations...)
+	pPVC := translate.HostMetadataWithContext(ctx, vPvc, s.VirtualToHost(ctx, types.NamespacedName{Name: vPvc.GetName(), Namespace: vPvc.GetNamespace()}, vPvc), s.excludedAnnotations...)
 	s.translateSelector(ctx, pPVC)
 
 	if vPvc.Annotations[constants.SkipTranslationAnnotation] != "true" {
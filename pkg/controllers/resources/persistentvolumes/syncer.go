@@ -267,7 +267,7 @@ func (s *persistentVolumeSyncer) SyncToVirtual(ctx *synccontext.SyncContext, eve
 		return patcher.DeleteHostObject(ctx, event.Host, event.VirtualOld, "it is not needed anymore")
 	} else if sync {
 		// create the persistent volume
-		vObj := s.translateBackwards(event.Host, vPvc)
+		vObj := s.translateBackwards(ctx, event.Host, vPvc)
 		err := pro.ApplyPatchesVirtualObject(ctx, nil, vObj, event.Host, ctx.Config.Sync.ToHost.PersistentVolumes.Patches, false)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -55,6 +55,7 @@ func TestSync(t *testing.T) {
 			constants.HostClusterPersistentVolumeAnnotation: "testpv",
 			translate.HostNameAnnotation:                    "testpv",
 			translate.KindAnnotation:                        "/v1, Kind=PersistentVolume",
+			translate.TranslateVersionAnnotation:            "1",
 			translate.NameAnnotation:                        "testpv",
 			translate.UIDAnnotation:                         "",
 		},
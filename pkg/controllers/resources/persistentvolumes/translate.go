@@ -12,7 +12,7 @@ import (
 
 func (s *persistentVolumeSyncer) translate(ctx *synccontext.SyncContext, vPv *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
 	// translate the persistent volume
-	pPV := translate.HostMetadata(vPv, s.VirtualToHost(ctx, types.NamespacedName{Name: vPv.GetName()}, vPv), s.excludedAnnotations...)
+	pPV := translate.HostMetadataWithContext(ctx, vPv, s.VirtualToHost(ctx, types.NamespacedName{Name: vPv.GetName()}, vPv), s.excludedAnnotations...)
 	pPV.Spec.ClaimRef = nil
 
 	// TODO: translate the storage secrets
@@ -22,9 +22,9 @@ func (s *persistentVolumeSyncer) translate(ctx *synccontext.SyncContext, vPv *co
 	return pPV, nil
 }
 
-func (s *persistentVolumeSyncer) translateBackwards(pPv *corev1.PersistentVolume, vPvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolume {
+func (s *persistentVolumeSyncer) translateBackwards(ctx *synccontext.SyncContext, pPv *corev1.PersistentVolume, vPvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolume {
 	// build virtual persistent volume
-	vObj := translate.CopyObjectWithName(pPv, types.NamespacedName{Name: pPv.Name}, false, s.excludedAnnotations...)
+	vObj := translate.CopyObjectWithContext(ctx, pPv, types.NamespacedName{Name: pPv.Name}, false, s.excludedAnnotations...)
 	if vPvc != nil {
 		if vObj.Spec.ClaimRef == nil {
 			vObj.Spec.ClaimRef = &corev1.ObjectReference{}
@@ -104,6 +104,12 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 		return nil, fmt.Errorf("failed to parse host cluster version : %w", err)
 	}
 
+	// clear node names of nodes that only exist in the virtual cluster
+	var nodeNameMapper translatepods.NodeNameMapper
+	if ctx.Config.Sync.ToHost.Pods.ClearVirtualNodeNames {
+		nodeNameMapper = translatepods.ClearVirtualNodeNames
+	}
+
 	return &podSyncer{
 		GenericTranslator: genericTranslator,
 		Importer:          pro.NewImporter(podsMapper),
@@ -117,6 +123,7 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 		physicalClusterConfig: ctx.HostManager.GetConfig(),
 		podTranslator:         podTranslator,
 		nodeSelector:          nodeSelector,
+		nodeNameMapper:        nodeNameMapper,
 
 		hostClusterVersion: hostClusterVersion,
 
@@ -138,6 +145,9 @@ type podSyncer struct {
 	physicalClusterConfig *rest.Config
 	nodeSelector          *metav1.LabelSelector
 
+	// nodeNameMapper translates the node name of pods that are pinned to a node, if nil the node name is kept
+	nodeNameMapper translatepods.NodeNameMapper
+
 	hostClusterVersion *utilversion.Version
 
 	podSecurityStandard string
@@ -258,6 +268,12 @@ func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, event *synccontext.
 		}
 	}
 
+	// translate the node name of pods that are pinned to a node
+	err = translatepods.TranslateNodeName(ctx, pPod, s.nodeNameMapper)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	err = pro.ApplyPatchesHostObject(ctx, nil, pPod, event.Virtual, ctx.Config.Sync.ToHost.Pods.Patches, false)
 	if err != nil {
 		return ctrl.Result{}, err
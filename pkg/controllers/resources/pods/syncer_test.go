@@ -71,6 +71,7 @@ func TestSyncTable(t *testing.T) {
 			translate.UIDAnnotation:                   "",
 			translate.NamespaceAnnotation:             vObjectMeta.Namespace,
 			translate.KindAnnotation:                  corev1.SchemeGroupVersion.WithKind("Pod").String(),
+			translate.TranslateVersionAnnotation:      "1",
 			translate.HostNamespaceAnnotation:         "test",
 			translate.HostNameAnnotation:              translate.Default.HostName(nil, "testpod", "testns").Name,
 			podtranslate.ServiceAccountNameAnnotation: "",
@@ -89,6 +90,9 @@ func TestSyncTable(t *testing.T) {
 		expectPhysicalPod        bool
 		expectVirtualPod         bool
 		withVirtualNode          bool
+		withHostNode             bool
+		clearVirtualNodeNames    bool
+		expectNodeNameCleared    bool
 		virtualPodWithoutNode    bool
 		initialVContainers       []corev1.Container
 		expectedVContainers      []corev1.Container
@@ -173,6 +177,31 @@ func TestSyncTable(t *testing.T) {
 			virtualPodsLabels:        map[string]string{"test.sh/abcd": "yes"},
 			expectPhysicalPodsLabels: map[string]string{"test.sh/abcd": "yes"},
 		},
+		{
+			name:                  "SyncToHost clears virtual node name",
+			expectPhysicalPod:     true,
+			syncToHost:            true,
+			expectVirtualPod:      true,
+			withVirtualNode:       true,
+			clearVirtualNodeNames: true,
+			expectNodeNameCleared: true,
+		},
+		{
+			name:                  "SyncToHost keeps host node name",
+			expectPhysicalPod:     true,
+			syncToHost:            true,
+			expectVirtualPod:      true,
+			withVirtualNode:       true,
+			withHostNode:          true,
+			clearVirtualNodeNames: true,
+		},
+		{
+			name:              "SyncToHost keeps virtual node name by default",
+			expectPhysicalPod: true,
+			syncToHost:        true,
+			expectVirtualPod:  true,
+			withVirtualNode:   true,
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.name, func(t *testing.T) {
@@ -214,7 +243,7 @@ func TestSyncTable(t *testing.T) {
 				},
 			}
 			pPodFinal := &corev1.Pod{
-				ObjectMeta: pObjectMeta,
+				ObjectMeta: *pObjectMeta.DeepCopy(),
 				Spec: corev1.PodSpec{
 					AutomountServiceAccountToken: ptr.To(false),
 					EnableServiceLinks:           ptr.To(false),
@@ -238,6 +267,9 @@ func TestSyncTable(t *testing.T) {
 				pPodFinal.Spec.NodeName = "test123"
 				vPodInitial.Spec.NodeName = "test123"
 				vPodFinal.Spec.NodeName = "test123"
+				if tC.expectNodeNameCleared {
+					pPodFinal.Spec.NodeName = ""
+				}
 			}
 
 			initialVirtualObjects := []runtime.Object{vPodInitial.DeepCopy(), vNamespace.DeepCopy()}
@@ -253,6 +285,9 @@ func TestSyncTable(t *testing.T) {
 			if !tC.syncToHost {
 				initialPhysicalObjects = append(initialPhysicalObjects, pPodInitial.DeepCopy())
 			}
+			if tC.withHostNode {
+				initialPhysicalObjects = append(initialPhysicalObjects, virtualNode.DeepCopy())
+			}
 			expectedPhysicalObjects := map[schema.GroupVersionKind][]runtime.Object{}
 			if tC.expectPhysicalPod {
 				expectedPhysicalObjects[corev1.SchemeGroupVersion.WithKind("Pod")] =
@@ -273,6 +308,7 @@ func TestSyncTable(t *testing.T) {
 
 			registerContext.Config.Networking.Advanced.ProxyKubelets.ByIP = false
 			registerContext.Config.Sync.FromHost.Nodes.Selector.Labels = tC.nodeSelectorOption
+			registerContext.Config.Sync.ToHost.Pods.ClearVirtualNodeNames = tC.clearVirtualNodeNames
 			if tC.securityStandard != "" {
 				registerContext.Config.Policies.PodSecurityStandard = tC.securityStandard
 			}
@@ -377,6 +413,7 @@ func TestSync(t *testing.T) {
 			translate.UIDAnnotation:                   "",
 			translate.NamespaceAnnotation:             vObjectMeta.Namespace,
 			translate.KindAnnotation:                  corev1.SchemeGroupVersion.WithKind("Pod").String(),
+			translate.TranslateVersionAnnotation:      "1",
 			translate.HostNameAnnotation:              translate.Default.HostName(nil, "testpod", "testns").Name,
 			translate.HostNamespaceAnnotation:         "test",
 			podtranslate.ServiceAccountNameAnnotation: "",
@@ -481,6 +518,7 @@ func TestSync(t *testing.T) {
 				translate.NamespaceAnnotation:             vHostPathPod.Namespace,
 				translate.UIDAnnotation:                   "",
 				translate.KindAnnotation:                  corev1.SchemeGroupVersion.WithKind("Pod").String(),
+				translate.TranslateVersionAnnotation:      "1",
 				translate.HostNamespaceAnnotation:         "test",
 				translate.HostNameAnnotation:              translate.Default.HostName(nil, vHostPathPod.Name, testingutil.DefaultTestCurrentNamespace).Name,
 				podtranslate.ServiceAccountNameAnnotation: "",
@@ -0,0 +1,45 @@
+package translate
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// NodeNameMapper maps the virtual node name a pod is pinned to to a host node name. Returning an empty
+// host node name clears the nodeName, so the host scheduler decides where the pod is placed.
+type NodeNameMapper func(ctx *synccontext.SyncContext, vNodeName string) (string, error)
+
+// ClearVirtualNodeNames is a NodeNameMapper that keeps node names of host nodes and clears node names
+// of nodes that only exist in the virtual cluster.
+func ClearVirtualNodeNames(ctx *synccontext.SyncContext, vNodeName string) (string, error) {
+	err := ctx.HostClient.Get(ctx, types.NamespacedName{Name: vNodeName}, &corev1.Node{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("get host node %s: %w", vNodeName, err)
+	}
+
+	return vNodeName, nil
+}
+
+// TranslateNodeName rewrites the nodeName of the host pod with the given mapper. Pods without a
+// nodeName as well as a nil mapper leave the pod unchanged.
+func TranslateNodeName(ctx *synccontext.SyncContext, pPod *corev1.Pod, mapper NodeNameMapper) error {
+	if mapper == nil || pPod.Spec.NodeName == "" {
+		return nil
+	}
+
+	nodeName, err := mapper(ctx, pPod.Spec.NodeName)
+	if err != nil {
+		return fmt.Errorf("translate node name %s: %w", pPod.Spec.NodeName, err)
+	}
+
+	pPod.Spec.NodeName = nodeName
+	return nil
+}
@@ -0,0 +1,67 @@
+package translate
+
+import (
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TranslatePodSpecReferences rewrites the config map and secret names referenced in volumes, projected volumes,
+// envFrom and valueFrom of all containers of the given pod spec in place to their host names
+func TranslatePodSpecReferences(ctx *synccontext.SyncContext, spec *corev1.PodSpec, vNamespace string) {
+	if spec == nil {
+		return
+	}
+
+	for i := range spec.Volumes {
+		translateVolumeReferences(ctx, &spec.Volumes[i], vNamespace)
+	}
+	for i := range spec.InitContainers {
+		translateEnvReferences(ctx, spec.InitContainers[i].Env, spec.InitContainers[i].EnvFrom, vNamespace)
+	}
+	for i := range spec.Containers {
+		translateEnvReferences(ctx, spec.Containers[i].Env, spec.Containers[i].EnvFrom, vNamespace)
+	}
+	for i := range spec.EphemeralContainers {
+		translateEnvReferences(ctx, spec.EphemeralContainers[i].Env, spec.EphemeralContainers[i].EnvFrom, vNamespace)
+	}
+}
+
+func translateVolumeReferences(ctx *synccontext.SyncContext, volume *corev1.Volume, vNamespace string) {
+	if volume.ConfigMap != nil {
+		volume.ConfigMap.Name = mappings.VirtualToHostName(ctx, volume.ConfigMap.Name, vNamespace, mappings.ConfigMaps())
+	}
+	if volume.Secret != nil {
+		volume.Secret.SecretName = mappings.VirtualToHostName(ctx, volume.Secret.SecretName, vNamespace, mappings.Secrets())
+	}
+	if volume.Projected != nil {
+		// projected secrets and config maps have no namespace, they are always read from the namespace of the pod
+		for i := range volume.Projected.Sources {
+			if volume.Projected.Sources[i].Secret != nil {
+				volume.Projected.Sources[i].Secret.Name = mappings.VirtualToHostName(ctx, volume.Projected.Sources[i].Secret.Name, vNamespace, mappings.Secrets())
+			}
+			if volume.Projected.Sources[i].ConfigMap != nil {
+				volume.Projected.Sources[i].ConfigMap.Name = mappings.VirtualToHostName(ctx, volume.Projected.Sources[i].ConfigMap.Name, vNamespace, mappings.ConfigMaps())
+			}
+		}
+	}
+}
+
+func translateEnvReferences(ctx *synccontext.SyncContext, envVar []corev1.EnvVar, envFrom []corev1.EnvFromSource, vNamespace string) {
+	for i := range envVar {
+		if envVar[i].ValueFrom != nil && envVar[i].ValueFrom.ConfigMapKeyRef != nil && envVar[i].ValueFrom.ConfigMapKeyRef.Name != "" {
+			envVar[i].ValueFrom.ConfigMapKeyRef.Name = mappings.VirtualToHostName(ctx, envVar[i].ValueFrom.ConfigMapKeyRef.Name, vNamespace, mappings.ConfigMaps())
+		}
+		if envVar[i].ValueFrom != nil && envVar[i].ValueFrom.SecretKeyRef != nil && envVar[i].ValueFrom.SecretKeyRef.Name != "" {
+			envVar[i].ValueFrom.SecretKeyRef.Name = mappings.VirtualToHostName(ctx, envVar[i].ValueFrom.SecretKeyRef.Name, vNamespace, mappings.Secrets())
+		}
+	}
+	for i := range envFrom {
+		if envFrom[i].ConfigMapRef != nil && envFrom[i].ConfigMapRef.Name != "" {
+			envFrom[i].ConfigMapRef.Name = mappings.VirtualToHostName(ctx, envFrom[i].ConfigMapRef.Name, vNamespace, mappings.ConfigMaps())
+		}
+		if envFrom[i].SecretRef != nil && envFrom[i].SecretRef.Name != "" {
+			envFrom[i].SecretRef.Name = mappings.VirtualToHostName(ctx, envFrom[i].SecretRef.Name, vNamespace, mappings.Secrets())
+		}
+	}
+}
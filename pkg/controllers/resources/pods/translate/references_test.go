@@ -0,0 +1,63 @@
+package translate
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	generictesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTranslatePodSpecReferences(t *testing.T) {
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	registerCtx := generictesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient)
+	syncCtx := registerCtx.ToSyncContext("pods-syncer-translator-test")
+	configMapName := func(name string) string {
+		return mappings.VirtualToHostName(syncCtx, name, "test", mappings.ConfigMaps())
+	}
+	secretName := func(name string) string {
+		return mappings.VirtualToHostName(syncCtx, name, "test", mappings.Secrets())
+	}
+
+	spec := &corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}}}},
+			{Name: "secret", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "secret"}}},
+			{Name: "projected", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"}}},
+				{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "projected-secret"}}},
+				{DownwardAPI: &corev1.DownwardAPIProjection{}},
+			}}}},
+		},
+		InitContainers: []corev1.Container{{
+			Name:    "init",
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "init-secret"}}}},
+			Env: []corev1.EnvVar{
+				{Name: "A", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "init-cm"}, Key: "a"}}},
+			},
+		}},
+		Containers: []corev1.Container{{
+			Name:    "main",
+			EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "main-cm"}}}},
+			Env: []corev1.EnvVar{
+				{Name: "B", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "main-secret"}, Key: "b"}}},
+				{Name: "C", Value: "plain"},
+			},
+		}},
+	}
+
+	TranslatePodSpecReferences(syncCtx, spec, "test")
+	assert.Equal(t, spec.Volumes[0].ConfigMap.Name, configMapName("cm"))
+	assert.Equal(t, spec.Volumes[1].Secret.SecretName, secretName("secret"))
+	assert.Equal(t, spec.Volumes[2].Projected.Sources[0].ConfigMap.Name, configMapName("kube-root-ca.crt"))
+	assert.Equal(t, spec.Volumes[2].Projected.Sources[1].Secret.Name, secretName("projected-secret"))
+	assert.Equal(t, spec.InitContainers[0].EnvFrom[0].SecretRef.Name, secretName("init-secret"))
+	assert.Equal(t, spec.InitContainers[0].Env[0].ValueFrom.ConfigMapKeyRef.Name, configMapName("init-cm"))
+	assert.Equal(t, spec.Containers[0].EnvFrom[0].ConfigMapRef.Name, configMapName("main-cm"))
+	assert.Equal(t, spec.Containers[0].Env[0].ValueFrom.SecretKeyRef.Name, secretName("main-secret"))
+	assert.Equal(t, spec.Containers[0].Env[1].Value, "plain")
+}
@@ -59,6 +59,7 @@ type Translator interface {
 	Translate(ctx *synccontext.SyncContext, vPod *corev1.Pod, services []*corev1.Service, dnsIP string, kubeIP string) (*corev1.Pod, error)
 	Diff(ctx *synccontext.SyncContext, event *synccontext.SyncEvent[*corev1.Pod]) error
 	TranslateContainerEnv(ctx *synccontext.SyncContext, envVar []corev1.EnvVar, envFrom []corev1.EnvFromSource, vPod *corev1.Pod, serviceEnvMap map[string]string) ([]corev1.EnvVar, []corev1.EnvFromSource, error)
+	TranslateImage(image string) string
 }
 
 func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder events.EventRecorder) (Translator, error) {
@@ -116,6 +117,8 @@ func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder events.EventR
 		log:             loghelper.New("pods-syncer-translator"),
 
 		serviceAccountSecretsEnabled: ctx.Config.Sync.ToHost.Pods.UseSecretsForSATokens,
+		injectVirtualMetadataEnv:     ctx.Config.Sync.ToHost.Pods.InjectVirtualMetadataEnv,
+		translateTokenAudiences:      ctx.Config.Sync.ToHost.Pods.TranslateTokenAudiences,
 		clusterDomain:                ctx.Config.Networking.Advanced.ClusterDomain,
 		serviceAccount:               ctx.Config.ControlPlane.Advanced.WorkloadServiceAccount.Name,
 
@@ -156,6 +159,8 @@ type translator struct {
 
 	serviceAccountsEnabled         bool
 	serviceAccountSecretsEnabled   bool
+	injectVirtualMetadataEnv       bool
+	translateTokenAudiences        map[string]string
 	clusterDomain                  string
 	serviceAccount                 string
 	overrideHosts                  bool
@@ -188,7 +193,7 @@ func (t *translator) Translate(ctx *synccontext.SyncContext, vPod *corev1.Pod, s
 	}
 
 	// convert to core object
-	pPod := translate.HostMetadata(vPod, mappings.VirtualToHost(ctx, vPod.Name, vPod.Namespace, mappings.Pods()))
+	pPod := translate.HostMetadataWithContext(ctx, vPod, mappings.VirtualToHost(ctx, vPod.Name, vPod.Namespace, mappings.Pods()))
 
 	// override pod fields
 	pPod.Status = corev1.PodStatus{}
@@ -291,7 +296,12 @@ func (t *translator) Translate(ctx *synccontext.SyncContext, vPod *corev1.Pod, s
 		updatedLabels = map[string]string{}
 	}
 	for k, v := range vNamespace.GetLabels() {
-		updatedLabels[translate.HostLabelNamespace(k)] = v
+		pKey := translate.HostLabelNamespace(k)
+		if _, ok := updatedLabels[pKey]; ok {
+			t.log.Infof("Warning: namespace label %s of namespace %s collides with another label on host label %s and is merged", k, vNamespace.GetName(), pKey)
+		}
+
+		updatedLabels[pKey] = v
 	}
 	pPod.SetLabels(updatedLabels)
 
@@ -327,15 +337,17 @@ func (t *translator) Translate(ctx *synccontext.SyncContext, vPod *corev1.Pod, s
 		pPod.Spec.Hostname = strings.TrimSuffix(strings.Replace(pPod.Spec.Hostname, ".", "-", -1), "-")
 	}
 
-	// if spec.subdomain is set we have to translate the /etc/hosts
-	// because otherwise we could get a different hostname as if the pod
-	// would be deployed in a non virtual kubernetes cluster
+	// spec.subdomain names a headless service in the namespace of the pod, so we point it to the synced
+	// host service to get the dns record of the pod on the host. If spec.subdomain is set we also have to
+	// translate the /etc/hosts because otherwise we could get a different hostname as if the pod would be
+	// deployed in a non virtual kubernetes cluster
 	if pPod.Spec.Subdomain != "" {
+		pPod.Spec.Subdomain = mappings.VirtualToHostName(ctx, vPod.Spec.Subdomain, vPod.Namespace, mappings.Services())
 		if t.overrideHosts {
-			t.rewritePodHostnameFQDN(pPod, pPod.Spec.Hostname, pPod.Spec.Hostname, pPod.Spec.Hostname+"."+pPod.Spec.Subdomain+"."+vPod.Namespace+".svc."+t.clusterDomain)
+			// the kubelet writes the host fqdn and the hostname into the /etc/hosts
+			fromHost := pPod.Spec.Hostname + `\.` + pPod.Spec.Subdomain + `\.[^[:space:]]+\s+` + pPod.Spec.Hostname
+			t.rewritePodHostnameFQDN(pPod, fromHost, pPod.Spec.Hostname, pPod.Spec.Hostname+"."+vPod.Spec.Subdomain+"."+vPod.Namespace+".svc."+t.clusterDomain)
 		}
-
-		pPod.Spec.Subdomain = ""
 	}
 
 	// translate pod resources
@@ -458,12 +470,7 @@ func (t *translator) translateVolumes(ctx *synccontext.SyncContext, pPod *corev1
 	tokenSecrets := map[string]string{}
 
 	for i := range pPod.Spec.Volumes {
-		if pPod.Spec.Volumes[i].ConfigMap != nil {
-			pPod.Spec.Volumes[i].ConfigMap.Name = mappings.VirtualToHostName(ctx, pPod.Spec.Volumes[i].ConfigMap.Name, vPod.Namespace, mappings.ConfigMaps())
-		}
-		if pPod.Spec.Volumes[i].Secret != nil {
-			pPod.Spec.Volumes[i].Secret.SecretName = mappings.VirtualToHostName(ctx, pPod.Spec.Volumes[i].Secret.SecretName, vPod.Namespace, mappings.Secrets())
-		}
+		translateVolumeReferences(ctx, &pPod.Spec.Volumes[i], vPod.Namespace)
 		if pPod.Spec.Volumes[i].PersistentVolumeClaim != nil {
 			pPod.Spec.Volumes[i].PersistentVolumeClaim.ClaimName = mappings.VirtualToHostName(ctx, pPod.Spec.Volumes[i].PersistentVolumeClaim.ClaimName, vPod.Namespace, mappings.PersistentVolumeClaims())
 		}
@@ -546,12 +553,6 @@ func (t *translator) translateProjectedVolume(
 	tokenSecrets map[string]string,
 ) error {
 	for i := range projectedVolume.Sources {
-		if projectedVolume.Sources[i].Secret != nil {
-			projectedVolume.Sources[i].Secret.Name = mappings.VirtualToHostName(ctx, projectedVolume.Sources[i].Secret.Name, vPod.Namespace, mappings.Secrets())
-		}
-		if projectedVolume.Sources[i].ConfigMap != nil {
-			projectedVolume.Sources[i].ConfigMap.Name = mappings.VirtualToHostName(ctx, projectedVolume.Sources[i].ConfigMap.Name, vPod.Namespace, mappings.ConfigMaps())
-		}
 		if projectedVolume.Sources[i].DownwardAPI != nil {
 			for j := range projectedVolume.Sources[i].DownwardAPI.Items {
 				translateFieldRef(projectedVolume.Sources[i].DownwardAPI.Items[j].FieldRef)
@@ -571,11 +572,7 @@ func (t *translator) translateProjectedVolume(
 				return errors.Wrap(err, "create client")
 			}
 
-			audiences := []string{"https://kubernetes.default.svc." + t.clusterDomain, "https://kubernetes.default.svc", "https://kubernetes.default"}
-			if projectedVolume.Sources[i].ServiceAccountToken.Audience != "" {
-				audiences = []string{projectedVolume.Sources[i].ServiceAccountToken.Audience}
-			}
-
+			audiences := t.serviceAccountTokenAudiences(projectedVolume.Sources[i].ServiceAccountToken)
 			expirationSeconds := int64(10 * 365 * 24 * 60 * 60)
 			token, err := vClient.CoreV1().ServiceAccounts(vPod.Namespace).CreateToken(ctx, serviceAccountName, &authenticationv1.TokenRequest{
 				Spec: authenticationv1.TokenRequestSpec{
@@ -653,6 +650,23 @@ func (t *translator) translateProjectedVolume(
 	return nil
 }
 
+// serviceAccountTokenAudiences returns the audiences to request for the given projected token. Audiences
+// found in translateTokenAudiences are replaced, all others are kept as they are.
+func (t *translator) serviceAccountTokenAudiences(tokenProjection *corev1.ServiceAccountTokenProjection) []string {
+	audiences := []string{"https://kubernetes.default.svc." + t.clusterDomain, "https://kubernetes.default.svc", "https://kubernetes.default"}
+	if tokenProjection.Audience != "" {
+		audiences = []string{tokenProjection.Audience}
+	}
+
+	for i, audience := range audiences {
+		if translated, ok := t.translateTokenAudiences[audience]; ok {
+			audiences[i] = translated
+		}
+	}
+
+	return audiences
+}
+
 func translateFieldRef(fieldSelector *corev1.ObjectFieldSelector) {
 	if fieldSelector == nil {
 		return
@@ -679,27 +693,20 @@ func translateFieldRef(fieldSelector *corev1.ObjectFieldSelector) {
 	}
 }
 
+func (t *translator) TranslateImage(image string) string {
+	return t.imageTranslator.Translate(image)
+}
+
 func (t *translator) TranslateContainerEnv(ctx *synccontext.SyncContext, envVar []corev1.EnvVar, envFrom []corev1.EnvFromSource, vPod *corev1.Pod, serviceEnvMap map[string]string) ([]corev1.EnvVar, []corev1.EnvFromSource, error) {
 	envNameMap := make(map[string]struct{})
 	for j, env := range envVar {
-		translateDownwardAPI(&envVar[j])
-		if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name != "" {
-			envVar[j].ValueFrom.ConfigMapKeyRef.Name = mappings.VirtualToHostName(ctx, envVar[j].ValueFrom.ConfigMapKeyRef.Name, vPod.Namespace, mappings.ConfigMaps())
-		}
-		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name != "" {
-			envVar[j].ValueFrom.SecretKeyRef.Name = mappings.VirtualToHostName(ctx, envVar[j].ValueFrom.SecretKeyRef.Name, vPod.Namespace, mappings.Secrets())
+		if t.injectVirtualMetadataEnv {
+			injectVirtualMetadata(&envVar[j], vPod)
 		}
-
+		translateDownwardAPI(&envVar[j])
 		envNameMap[env.Name] = struct{}{}
 	}
-	for j, from := range envFrom {
-		if from.ConfigMapRef != nil && from.ConfigMapRef.Name != "" {
-			envFrom[j].ConfigMapRef.Name = mappings.VirtualToHostName(ctx, from.ConfigMapRef.Name, vPod.Namespace, mappings.ConfigMaps())
-		}
-		if from.SecretRef != nil && from.SecretRef.Name != "" {
-			envFrom[j].SecretRef.Name = mappings.VirtualToHostName(ctx, from.SecretRef.Name, vPod.Namespace, mappings.Secrets())
-		}
-	}
+	translateEnvReferences(ctx, envVar, envFrom, vPod.Namespace)
 
 	additionalEnvVars := []corev1.EnvVar{}
 	for k, v := range serviceEnvMap {
@@ -723,6 +730,27 @@ func (t *translator) TranslateContainerEnv(ctx *synccontext.SyncContext, envVar
 	return envVar, envFrom, nil
 }
 
+// injectVirtualMetadata replaces a fieldRef to the name, namespace or uid of the pod with the literal value of the
+// virtual pod, so the container sees the virtual identity without reading it from the host pod.
+func injectVirtualMetadata(env *corev1.EnvVar, vPod *corev1.Pod) {
+	if env.ValueFrom == nil || env.ValueFrom.FieldRef == nil {
+		return
+	}
+
+	switch env.ValueFrom.FieldRef.FieldPath {
+	case "metadata.name":
+		env.Value = vPod.Name
+	case "metadata.namespace":
+		env.Value = vPod.Namespace
+	case "metadata.uid":
+		env.Value = string(vPod.UID)
+	default:
+		return
+	}
+
+	env.ValueFrom = nil
+}
+
 func translateDownwardAPI(env *corev1.EnvVar) {
 	if env.ValueFrom == nil {
 		return
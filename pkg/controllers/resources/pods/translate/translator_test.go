@@ -1,6 +1,7 @@
 package translate
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -183,6 +184,73 @@ type translatePodAffinityTermTestCase struct {
 	expectedEvents []string
 }
 
+func TestPodAffinityTranslation(t *testing.T) {
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "affinity-pod",
+			Namespace: "test",
+		},
+	}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	namespaceSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+	hostSelector := func(extra *metav1.LabelSelector) *metav1.LabelSelector {
+		return translate.MergeLabelSelectors(&metav1.LabelSelector{MatchLabels: map[string]string{
+			translate.HostLabel("app"): "web",
+			translate.MarkerLabel:      translate.VClusterName,
+		}}, extra)
+	}
+
+	pPod := vPod.DeepCopy()
+	pPod.Spec.Affinity = &corev1.Affinity{
+		PodAffinity: &corev1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: selector, TopologyKey: "zone"},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: 10, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: selector, NamespaceSelector: namespaceSelector, TopologyKey: "zone"}},
+			},
+		},
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: selector, Namespaces: []string{"other"}, TopologyKey: "hostname"},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: 20, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: selector, TopologyKey: "hostname"}},
+			},
+		},
+	}
+
+	tr := &translator{
+		eventRecorder: events.NewFakeRecorder(10),
+		log:           loghelper.New("pods-syncer-translator-test"),
+	}
+	tr.translatePodAffinity(vPod, pPod)
+
+	ownNamespace := &metav1.LabelSelector{MatchLabels: map[string]string{translate.NamespaceLabel: vPod.Namespace}}
+	expected := &corev1.Affinity{
+		PodAffinity: &corev1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: hostSelector(ownNamespace), TopologyKey: "zone"},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: 10, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: hostSelector(translate.HostLabelSelectorNamespace(namespaceSelector)), TopologyKey: "zone"}},
+			},
+		},
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: appendNamespacesToMatchExpressions(hostSelector(nil), "other"), TopologyKey: "hostname"},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: 20, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: hostSelector(ownNamespace), TopologyKey: "hostname"}},
+			},
+		},
+	}
+	assert.DeepEqual(t, pPod.Spec.Affinity, expected)
+
+	// the virtual pod must not be touched
+	assert.Assert(t, vPod.Spec.Affinity == nil)
+}
+
 func TestVolumeTranslation(t *testing.T) {
 	virtualPath := fmt.Sprintf(VirtualPathTemplate, testingutil.DefaultTestCurrentNamespace, testingutil.DefaultTestVClusterName)
 	hostToContainer := corev1.MountPropagationHostToContainer
@@ -986,3 +1054,282 @@ func TestTranslateEnforcedTolerationDedup(t *testing.T) {
 	}
 	assert.Equal(t, count, 1, "enforced toleration must appear exactly once on the physical pod, got %d", count)
 }
+
+func TestTranslateEphemeralContainers(t *testing.T) {
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	imageTranslator, err := NewImageTranslator(map[string]string{"busybox": "registry.example.com/busybox"})
+	assert.NilError(t, err)
+
+	tr := &translator{
+		eventRecorder:   events.NewFakeRecorder(10),
+		log:             loghelper.New("translate-ephemeral-test"),
+		vClient:         vClient,
+		pClient:         pClient,
+		imageTranslator: imageTranslator,
+	}
+
+	registerCtx := generictesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient)
+	syncCtx := registerCtx.ToSyncContext("test")
+	assert.NilError(t, vClient.Create(syncCtx.Context, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "testns"},
+	}))
+
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "testpod", Namespace: "testns"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+			Volumes: []corev1.Volume{{
+				Name: "config",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "my-config"}},
+				},
+			}},
+			EphemeralContainers: []corev1.EphemeralContainer{{
+				EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+					Name:  "debugger",
+					Image: "busybox",
+					Env: []corev1.EnvVar{
+						{
+							Name: "LEVEL",
+							ValueFrom: &corev1.EnvVarSource{
+								ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-config"}, Key: "level"},
+							},
+						},
+						{
+							Name: "TOKEN",
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "token"},
+							},
+						},
+					},
+					EnvFrom: []corev1.EnvFromSource{{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "my-config"}},
+					}},
+					VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/config"}},
+				},
+				TargetContainerName: "app",
+			}},
+		},
+	}
+
+	pPod, err := tr.Translate(syncCtx, vPod, nil, "", "")
+	assert.NilError(t, err)
+
+	pConfigMapName := translate.Default.HostName(syncCtx, "my-config", "testns").Name
+	assert.Equal(t, pPod.Spec.Volumes[0].ConfigMap.Name, pConfigMapName)
+
+	assert.Equal(t, len(pPod.Spec.EphemeralContainers), 1)
+	pContainer := pPod.Spec.EphemeralContainers[0]
+	assert.Equal(t, pContainer.Image, "registry.example.com/busybox")
+	assert.Equal(t, pContainer.TargetContainerName, "app")
+	assert.DeepEqual(t, pContainer.VolumeMounts, []corev1.VolumeMount{{Name: "config", MountPath: "/config"}})
+	assert.Equal(t, pContainer.EnvFrom[0].ConfigMapRef.Name, pConfigMapName)
+
+	env := map[string]corev1.EnvVar{}
+	for _, envVar := range pContainer.Env {
+		env[envVar.Name] = envVar
+	}
+	assert.Equal(t, env["LEVEL"].ValueFrom.ConfigMapKeyRef.Name, pConfigMapName)
+	assert.Equal(t, env["TOKEN"].ValueFrom.SecretKeyRef.Name, translate.Default.HostName(syncCtx, "my-secret", "testns").Name)
+}
+
+func TestServiceAccountTokenAudiences(t *testing.T) {
+	tr := &translator{
+		clusterDomain: "cluster.local",
+		translateTokenAudiences: map[string]string{
+			"vault":                      "host-vault",
+			"https://kubernetes.default": "https://host.example.com",
+		},
+	}
+
+	// explicit audience is rewritten
+	assert.DeepEqual(t, tr.serviceAccountTokenAudiences(&corev1.ServiceAccountTokenProjection{
+		Audience: "vault",
+		Path:     "token",
+	}), []string{"host-vault"})
+
+	// unmatched audience is kept
+	assert.DeepEqual(t, tr.serviceAccountTokenAudiences(&corev1.ServiceAccountTokenProjection{
+		Audience: "other",
+		Path:     "token",
+	}), []string{"other"})
+
+	// default audiences are rewritten as well
+	assert.DeepEqual(t, tr.serviceAccountTokenAudiences(&corev1.ServiceAccountTokenProjection{
+		Path: "token",
+	}), []string{"https://kubernetes.default.svc.cluster.local", "https://kubernetes.default.svc", "https://host.example.com"})
+
+	// no translation configured
+	tr.translateTokenAudiences = nil
+	assert.DeepEqual(t, tr.serviceAccountTokenAudiences(&corev1.ServiceAccountTokenProjection{
+		Audience: "vault",
+		Path:     "token",
+	}), []string{"vault"})
+}
+
+func TestTranslateNodeName(t *testing.T) {
+	ctx := &synccontext.SyncContext{
+		Context: context.Background(),
+		HostClient: testingutil.NewFakeClient(scheme.Scheme, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "host-node"},
+		}),
+	}
+	newPod := func(nodeName string) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{NodeName: nodeName}}
+	}
+
+	// pods pinned to a virtual node are scheduled by the host scheduler
+	pPod := newPod("virtual-node")
+	assert.NilError(t, TranslateNodeName(ctx, pPod, ClearVirtualNodeNames))
+	assert.Equal(t, pPod.Spec.NodeName, "")
+
+	// pods pinned to a host node keep their node
+	pPod = newPod("host-node")
+	assert.NilError(t, TranslateNodeName(ctx, pPod, ClearVirtualNodeNames))
+	assert.Equal(t, pPod.Spec.NodeName, "host-node")
+
+	// virtual nodes can be remapped to host nodes
+	remap := func(_ *synccontext.SyncContext, vNodeName string) (string, error) {
+		return map[string]string{"virtual-node": "host-node"}[vNodeName], nil
+	}
+	pPod = newPod("virtual-node")
+	assert.NilError(t, TranslateNodeName(ctx, pPod, remap))
+	assert.Equal(t, pPod.Spec.NodeName, "host-node")
+
+	// without a mapper the node name is kept
+	pPod = newPod("virtual-node")
+	assert.NilError(t, TranslateNodeName(ctx, pPod, nil))
+	assert.Equal(t, pPod.Spec.NodeName, "virtual-node")
+
+	// mapper errors are returned
+	pPod = newPod("virtual-node")
+	err := TranslateNodeName(ctx, pPod, func(_ *synccontext.SyncContext, _ string) (string, error) {
+		return "", fmt.Errorf("no mapping")
+	})
+	assert.ErrorContains(t, err, "translate node name virtual-node: no mapping")
+}
+
+func TestInjectVirtualMetadataEnv(t *testing.T) {
+	vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "testpod", Namespace: "testns", UID: "1234"}}
+	newEnv := func() []corev1.EnvVar {
+		fieldRef := func(fieldPath string) *corev1.EnvVarSource {
+			return &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: fieldPath}}
+		}
+
+		return []corev1.EnvVar{
+			{Name: "POD_NAME", ValueFrom: fieldRef("metadata.name")},
+			{Name: "POD_NAMESPACE", ValueFrom: fieldRef("metadata.namespace")},
+			{Name: "POD_UID", ValueFrom: fieldRef("metadata.uid")},
+			{Name: "NODE_NAME", ValueFrom: fieldRef("spec.nodeName")},
+		}
+	}
+
+	// by default the container reads the virtual metadata from the host pod annotations
+	tr := &translator{}
+	envVar, _, err := tr.TranslateContainerEnv(nil, newEnv(), nil, vPod, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, envVar[0].ValueFrom.FieldRef.FieldPath, "metadata.annotations['"+NameAnnotation+"']")
+	assert.Equal(t, envVar[1].ValueFrom.FieldRef.FieldPath, "metadata.annotations['"+NamespaceAnnotation+"']")
+	assert.Equal(t, envVar[2].ValueFrom.FieldRef.FieldPath, "metadata.annotations['"+UIDAnnotation+"']")
+
+	// with the option the virtual metadata is injected as literal values
+	tr = &translator{injectVirtualMetadataEnv: true}
+	envVar, _, err = tr.TranslateContainerEnv(nil, newEnv(), nil, vPod, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, envVar, []corev1.EnvVar{
+		{Name: "POD_NAME", Value: "testpod"},
+		{Name: "POD_NAMESPACE", Value: "testns"},
+		{Name: "POD_UID", Value: "1234"},
+		{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "spec.nodeName"}}},
+	})
+}
+
+func TestTranslateProjectedVolumeSources(t *testing.T) {
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	registerCtx := generictesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient)
+	syncCtx := registerCtx.ToSyncContext("pods-syncer-translator-test")
+	tr := &translator{
+		eventRecorder: events.NewFakeRecorder(10),
+		log:           loghelper.New("pods-syncer-translator-test"),
+		pClient:       pClient,
+	}
+
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "testpod", Namespace: "testns"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name: "combined",
+				VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{
+							{ConfigMap: &corev1.ConfigMapProjection{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "my-config"},
+								Items:                []corev1.KeyToPath{{Key: "config.yaml", Path: "config.yaml"}},
+							}},
+							{Secret: &corev1.SecretProjection{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+								Optional:             ptr.To(true),
+							}},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	pPod := vPod.DeepCopy()
+	assert.NilError(t, tr.translateVolumes(syncCtx, pPod, vPod))
+	assert.DeepEqual(t, pPod.Spec.Volumes[0].Projected.Sources, []corev1.VolumeProjection{
+		{ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: translate.Default.HostName(syncCtx, "my-config", "testns").Name},
+			Items:                []corev1.KeyToPath{{Key: "config.yaml", Path: "config.yaml"}},
+		}},
+		{Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: translate.Default.HostName(syncCtx, "my-secret", "testns").Name},
+			Optional:             ptr.To(true),
+		}},
+	})
+}
+
+func TestTranslateSubdomain(t *testing.T) {
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	tr := &translator{
+		eventRecorder:      events.NewFakeRecorder(10),
+		log:                loghelper.New("translate-subdomain-test"),
+		vClient:            vClient,
+		pClient:            pClient,
+		imageTranslator:    &imageTranslator{},
+		clusterDomain:      "cluster.local",
+		overrideHosts:      true,
+		overrideHostsImage: "library/alpine:3.20",
+	}
+
+	registerCtx := generictesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient)
+	syncCtx := registerCtx.ToSyncContext("test")
+	assert.NilError(t, vClient.Create(syncCtx.Context, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "testns"},
+	}))
+
+	// a stateful set pod that references its headless service
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "testns"},
+		Spec: corev1.PodSpec{
+			Hostname:   "web-0",
+			Subdomain:  "web-headless",
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+		},
+	}
+
+	pPod, err := tr.Translate(syncCtx, vPod, nil, "", "")
+	assert.NilError(t, err)
+	pServiceName := translate.Default.HostName(syncCtx, "web-headless", "testns").Name
+	assert.Equal(t, pPod.Spec.Subdomain, pServiceName)
+	assert.Equal(t, pPod.Spec.Hostname, "web-0")
+
+	// the host fqdn in the hosts file is replaced with the virtual one
+	assert.Equal(t, len(pPod.Spec.InitContainers), 1)
+	assert.Equal(t, pPod.Spec.InitContainers[0].Args[1], `sed -E -e 's/^(\d+.\d+.\d+.\d+\s+)web-0\.`+pServiceName+`\.[^[:space:]]+\s+web-0$/\1 web-0.web-headless.testns.svc.cluster.local web-0/' /etc/hosts > /hosts/hosts`)
+}
@@ -11,7 +11,7 @@ import (
 
 func (s *priorityClassSyncer) translate(ctx *synccontext.SyncContext, vObj client.Object) *schedulingv1.PriorityClass {
 	// translate the priority class
-	priorityClass := translate.HostMetadata(vObj.(*schedulingv1.PriorityClass), s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj))
+	priorityClass := translate.HostMetadataWithContext(ctx, vObj.(*schedulingv1.PriorityClass), s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj))
 	priorityClass.GlobalDefault = false
 	if priorityClass.Value > 1000000000 {
 		priorityClass.Value = 1000000000
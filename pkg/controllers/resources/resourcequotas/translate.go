@@ -0,0 +1,31 @@
+package resourcequotas
+
+import (
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TranslateResourceQuotaToHost rewrites the scope selector of a virtual resource quota so that it selects the
+// host pods of the synced virtual pods. Only the PriorityClass scope references objects by name: if priority
+// classes are synced to the host, the referenced priority class names are translated to their host names the
+// same way the priorityClassName of synced pods is translated. All other scopes (Terminating, NotTerminating,
+// BestEffort, NotBestEffort, CrossNamespacePodAffinity and VolumeAttributesClass) as well as spec.scopes are
+// passed through as they are.
+func TranslateResourceQuotaToHost(ctx *synccontext.SyncContext, rq *corev1.ResourceQuota) {
+	if rq.Spec.ScopeSelector == nil || !ctx.Config.Sync.ToHost.PriorityClasses.Enabled {
+		return
+	}
+
+	for i, expression := range rq.Spec.ScopeSelector.MatchExpressions {
+		if expression.ScopeName != corev1.ResourceQuotaScopePriorityClass {
+			continue
+		}
+
+		values := make([]string, 0, len(expression.Values))
+		for _, value := range expression.Values {
+			values = append(values, translate.Default.HostNameCluster(value))
+		}
+		rq.Spec.ScopeSelector.MatchExpressions[i].Values = values
+	}
+}
@@ -0,0 +1,56 @@
+package resourcequotas
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTranslateResourceQuotaToHost(t *testing.T) {
+	newResourceQuota := func() *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			Spec: corev1.ResourceQuotaSpec{
+				Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeNotTerminating},
+				ScopeSelector: &corev1.ScopeSelector{
+					MatchExpressions: []corev1.ScopedResourceSelectorRequirement{
+						{
+							ScopeName: corev1.ResourceQuotaScopePriorityClass,
+							Operator:  corev1.ScopeSelectorOpIn,
+							Values:    []string{"high", "low"},
+						},
+						{
+							ScopeName: corev1.ResourceQuotaScopeBestEffort,
+							Operator:  corev1.ScopeSelectorOpExists,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	ctx := &synccontext.SyncContext{Config: testingutil.NewFakeConfig()}
+
+	// priority class names are kept if priority classes are not synced to the host
+	rq := newResourceQuota()
+	TranslateResourceQuotaToHost(ctx, rq)
+	assert.DeepEqual(t, rq, newResourceQuota())
+
+	ctx.Config.Sync.ToHost.PriorityClasses.Enabled = true
+	rq = newResourceQuota()
+	TranslateResourceQuotaToHost(ctx, rq)
+	assert.DeepEqual(t, rq.Spec.ScopeSelector.MatchExpressions[0].Values, []string{
+		translate.Default.HostNameCluster("high"),
+		translate.Default.HostNameCluster("low"),
+	})
+	assert.DeepEqual(t, rq.Spec.ScopeSelector.MatchExpressions[1], newResourceQuota().Spec.ScopeSelector.MatchExpressions[1])
+	assert.DeepEqual(t, rq.Spec.Scopes, newResourceQuota().Spec.Scopes)
+
+	// quotas without scope selector are passed through
+	rq = &corev1.ResourceQuota{}
+	TranslateResourceQuotaToHost(ctx, rq)
+	assert.DeepEqual(t, rq, &corev1.ResourceQuota{})
+}
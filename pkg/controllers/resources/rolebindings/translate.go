@@ -0,0 +1,30 @@
+package rolebindings
+
+import (
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// TranslateSubjectsToHost returns the subjects of a virtual role binding or cluster role binding with their service
+// account subjects rewritten to the synced host service accounts. Service account subjects always carry their
+// namespace, so this works the same for namespaced and cluster scoped bindings. User and group subjects are not
+// synced objects and are passed through.
+func TranslateSubjectsToHost(ctx *synccontext.SyncContext, subjects []rbacv1.Subject) []rbacv1.Subject {
+	if subjects == nil {
+		return nil
+	}
+
+	pSubjects := make([]rbacv1.Subject, 0, len(subjects))
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1.ServiceAccountKind && subject.Name != "" && subject.Namespace != "" {
+			pName := translate.Default.HostName(ctx, subject.Name, subject.Namespace)
+			subject.Name = pName.Name
+			subject.Namespace = pName.Namespace
+		}
+
+		pSubjects = append(pSubjects, subject)
+	}
+
+	return pSubjects
+}
@@ -0,0 +1,34 @@
+package rolebindings
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestTranslateSubjectsToHost(t *testing.T) {
+	subjects := []rbacv1.Subject{
+		{Kind: rbacv1.ServiceAccountKind, Name: "builder", Namespace: "ci"},
+		{Kind: rbacv1.ServiceAccountKind, Name: "default", Namespace: "team-a"},
+		{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "jane@example.com"},
+		{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "system:authenticated"},
+	}
+
+	pSubjects := TranslateSubjectsToHost(nil, subjects)
+	builder := translate.Default.HostName(nil, "builder", "ci")
+	teamA := translate.Default.HostName(nil, "default", "team-a")
+	assert.DeepEqual(t, pSubjects, []rbacv1.Subject{
+		{Kind: rbacv1.ServiceAccountKind, Name: builder.Name, Namespace: builder.Namespace},
+		{Kind: rbacv1.ServiceAccountKind, Name: teamA.Name, Namespace: teamA.Namespace},
+		{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "jane@example.com"},
+		{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "system:authenticated"},
+	})
+
+	// the virtual subjects are not modified
+	assert.Equal(t, subjects[0].Name, "builder")
+	assert.Equal(t, subjects[0].Namespace, "ci")
+
+	assert.Assert(t, TranslateSubjectsToHost(nil, nil) == nil)
+}
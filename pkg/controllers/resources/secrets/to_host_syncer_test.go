@@ -43,12 +43,13 @@ func TestSync(t *testing.T) {
 			Name:      translate.Default.HostName(nil, baseSecret.Name, baseSecret.Namespace).Name,
 			Namespace: "test",
 			Annotations: map[string]string{
-				translate.NameAnnotation:          baseSecret.Name,
-				translate.NamespaceAnnotation:     baseSecret.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Secret").String(),
-				translate.HostNamespaceAnnotation: "test",
-				translate.HostNameAnnotation:      translate.Default.HostName(nil, baseSecret.Name, baseSecret.Namespace).Name,
+				translate.NameAnnotation:             baseSecret.Name,
+				translate.NamespaceAnnotation:        baseSecret.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Secret").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    "test",
+				translate.HostNameAnnotation:         translate.Default.HostName(nil, baseSecret.Name, baseSecret.Namespace).Name,
 			},
 			Labels: map[string]string{
 				translate.NamespaceLabel: baseSecret.Namespace,
@@ -46,6 +46,7 @@ func TestSync(t *testing.T) {
 				translate.NamespaceAnnotation:          vSA.Namespace,
 				translate.UIDAnnotation:                "",
 				translate.KindAnnotation:               corev1.SchemeGroupVersion.WithKind("ServiceAccount").String(),
+				translate.TranslateVersionAnnotation:   "1",
 				translate.HostNamespaceAnnotation:      "test",
 				translate.HostNameAnnotation:           translate.Default.HostName(nil, vSA.Name, vSA.Namespace).Name,
 			},
@@ -215,7 +215,11 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, event *synccontext.Sy
 	if !apiequality.Semantic.DeepEqual(event.VirtualOld.Spec.Selector, event.Virtual.Spec.Selector) || apiequality.Semantic.DeepEqual(event.HostOld.Spec.Selector, event.Host.Spec.Selector) {
 		event.Host.Spec.Selector = translate.HostLabelsMap(event.Virtual.Spec.Selector, event.Host.Spec.Selector, event.Virtual.Namespace, false)
 	} else {
-		event.Virtual.Spec.Selector = translate.VirtualLabelsMap(event.Host.Spec.Selector, event.Virtual.Spec.Selector)
+		var droppedKeys []string
+		event.Virtual.Spec.Selector, droppedKeys = translate.VirtualLabelsMapWithDroppedKeys(event.Host.Spec.Selector, event.Virtual.Spec.Selector, translate.LabelsOptions{})
+		if len(droppedKeys) > 0 {
+			ctx.Log.Infof("host service %s/%s selector keys %v can't be translated back and are not synced to the virtual service", event.Host.Namespace, event.Host.Name, droppedKeys)
+		}
 	}
 
 	return ctrl.Result{}, nil
@@ -25,12 +25,13 @@ func TestSync(t *testing.T) {
 		Name:      translate.Default.HostName(nil, "testservice", "testns").Name,
 		Namespace: "test",
 		Annotations: map[string]string{
-			translate.NameAnnotation:          vObjectMeta.Name,
-			translate.NamespaceAnnotation:     vObjectMeta.Namespace,
-			translate.UIDAnnotation:           "",
-			translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Service").String(),
-			translate.HostNamespaceAnnotation: "test",
-			translate.HostNameAnnotation:      translate.Default.HostName(nil, "testservice", "testns").Name,
+			translate.NameAnnotation:             vObjectMeta.Name,
+			translate.NamespaceAnnotation:        vObjectMeta.Namespace,
+			translate.UIDAnnotation:              "",
+			translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Service").String(),
+			translate.TranslateVersionAnnotation: "1",
+			translate.HostNamespaceAnnotation:    "test",
+			translate.HostNameAnnotation:         translate.Default.HostName(nil, "testservice", "testns").Name,
 		},
 		Labels: map[string]string{
 			translate.NamespaceLabel: vObjectMeta.Namespace,
@@ -82,13 +83,14 @@ func TestSync(t *testing.T) {
 			Name:      pObjectMeta.Name,
 			Namespace: pObjectMeta.Namespace,
 			Annotations: map[string]string{
-				translate.NameAnnotation:          vObjectMeta.Name,
-				translate.NamespaceAnnotation:     vObjectMeta.Namespace,
-				translate.UIDAnnotation:           "",
-				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Service").String(),
-				translate.HostNamespaceAnnotation: pObjectMeta.Namespace,
-				translate.HostNameAnnotation:      pObjectMeta.Name,
-				"a":                               "b",
+				translate.NameAnnotation:             vObjectMeta.Name,
+				translate.NamespaceAnnotation:        vObjectMeta.Namespace,
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Service").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNamespaceAnnotation:    pObjectMeta.Namespace,
+				translate.HostNameAnnotation:         pObjectMeta.Name,
+				"a":                                  "b",
 			},
 			Labels: pObjectMeta.Labels,
 		},
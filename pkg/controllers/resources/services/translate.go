@@ -1,14 +1,18 @@
 package services
 
 import (
+	"fmt"
+
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func (s *serviceSyncer) translate(ctx *synccontext.SyncContext, vObj *corev1.Service) *corev1.Service {
-	newService := translate.HostMetadata(vObj, s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj), s.excludedAnnotations...)
+	newService := translate.HostMetadataWithContext(ctx, vObj, s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj), s.excludedAnnotations...)
 	newService.Spec.Selector = translate.HostLabelsMap(vObj.Spec.Selector, nil, vObj.Namespace, false)
 	if newService.Spec.ClusterIP != "None" {
 		newService.Spec.ClusterIP = ""
@@ -50,3 +54,50 @@ func StripNodePorts(vObj *corev1.Service) {
 		vObj.Spec.Ports[i].NodePort = 0
 	}
 }
+
+// ValidateServicePortTargets checks that every named target port of the service resolves to a container port with the
+// same name and protocol in the given pod template, like the endpoints controller would resolve it. Ports are not
+// translated during sync, so this works with the virtual as well as the translated host pod template.
+func ValidateServicePortTargets(svc *corev1.Service, podTemplate *corev1.PodTemplateSpec) error {
+	containerPorts := map[string]corev1.Protocol{}
+	addPorts := func(container corev1.Container) {
+		for _, port := range container.Ports {
+			if port.Name != "" {
+				containerPorts[port.Name] = defaultProtocol(port.Protocol)
+			}
+		}
+	}
+	for _, container := range podTemplate.Spec.Containers {
+		addPorts(container)
+	}
+	for _, container := range podTemplate.Spec.InitContainers {
+		// sidecar containers keep running, so their ports can be targeted as well
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addPorts(container)
+		}
+	}
+
+	var errs []error
+	for _, port := range svc.Spec.Ports {
+		if port.TargetPort.Type != intstr.String || port.TargetPort.StrVal == "" {
+			continue
+		}
+
+		protocol, ok := containerPorts[port.TargetPort.StrVal]
+		if !ok {
+			errs = append(errs, fmt.Errorf("target port %s of service port %s does not exist in the pod template", port.TargetPort.StrVal, port.Name))
+		} else if protocol != defaultProtocol(port.Protocol) {
+			errs = append(errs, fmt.Errorf("target port %s of service port %s has protocol %s, but the container port has protocol %s", port.TargetPort.StrVal, port.Name, defaultProtocol(port.Protocol), protocol))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func defaultProtocol(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+
+	return protocol
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/workloads"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateServicePortTargets(t *testing.T) {
+	podTemplate := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "web",
+				Ports: []corev1.ContainerPort{
+					{Name: "http", ContainerPort: 8080},
+					{Name: "dns", ContainerPort: 5353, Protocol: corev1.ProtocolUDP},
+				},
+			}},
+			InitContainers: []corev1.Container{{
+				Name:          "proxy",
+				RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
+				Ports:         []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9090}},
+			}},
+		},
+	}
+	workloads.TranslatePodTemplateToHost(nil, "test", podTemplate)
+
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromString("dns")},
+				{Name: "metrics", Port: 9090, TargetPort: intstr.FromString("metrics")},
+				{Name: "numeric", Port: 8443, TargetPort: intstr.FromInt32(8443)},
+			},
+		},
+	}
+	assert.NilError(t, ValidateServicePortTargets(svc, podTemplate))
+
+	// missing named port
+	svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{Name: "grpc", Port: 9000, TargetPort: intstr.FromString("grpc")})
+	assert.Error(t, ValidateServicePortTargets(svc, podTemplate), "target port grpc of service port grpc does not exist in the pod template")
+
+	// protocol mismatch
+	svc.Spec.Ports = []corev1.ServicePort{{Name: "dns-tcp", Port: 53, TargetPort: intstr.FromString("dns")}}
+	assert.Error(t, ValidateServicePortTargets(svc, podTemplate), "target port dns of service port dns-tcp has protocol TCP, but the container port has protocol UDP")
+}
+
+func TestTranslateSelectorNotAllowlisted(t *testing.T) {
+	translate.SyncLabelKeys = []string{"team"}
+	defer func() { translate.SyncLabelKeys = nil }()
+
+	pClient := testingutil.NewFakeClient(scheme.Scheme)
+	vClient := testingutil.NewFakeClient(scheme.Scheme)
+	syncCtx, syncer := syncertesting.FakeStartSyncer(t, syncertesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), pClient, vClient), New)
+
+	vService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test", Labels: map[string]string{"app": "web", "team": "a"}},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}
+	pService := syncer.(*serviceSyncer).translate(syncCtx, vService)
+
+	// the allowlist only applies to the labels of the service
+	assert.DeepEqual(t, pService.Labels, map[string]string{
+		"team":                   "a",
+		translate.MarkerLabel:    translate.VClusterName,
+		translate.NamespaceLabel: "test",
+	})
+
+	// selector keys that are not allowlisted are kept, otherwise the host service would select all pods of the namespace
+	assert.DeepEqual(t, pService.Spec.Selector, map[string]string{
+		"app":                    "web",
+		translate.MarkerLabel:    translate.VClusterName,
+		translate.NamespaceLabel: "test",
+	})
+	assert.DeepEqual(t, syncer.(*serviceSyncer).translateToVirtual(syncCtx, pService).Spec.Selector, vService.Spec.Selector)
+}
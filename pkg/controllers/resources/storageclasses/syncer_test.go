@@ -35,10 +35,11 @@ func TestSync(t *testing.T) {
 				translate.MarkerLabel: translate.VClusterName,
 			},
 			Annotations: map[string]string{
-				translate.NameAnnotation:     "testsc",
-				translate.UIDAnnotation:      "",
-				translate.KindAnnotation:     storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
-				translate.HostNameAnnotation: translate.Default.HostNameCluster(vObjectMeta.Name),
+				translate.NameAnnotation:             "testsc",
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNameAnnotation:         translate.Default.HostNameCluster(vObjectMeta.Name),
 			},
 		},
 		Provisioner: "my-provisioner",
@@ -57,10 +58,11 @@ func TestSync(t *testing.T) {
 				translate.MarkerLabel: translate.VClusterName,
 			},
 			Annotations: map[string]string{
-				translate.NameAnnotation:     "testsc",
-				translate.UIDAnnotation:      "",
-				translate.KindAnnotation:     storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
-				translate.HostNameAnnotation: translate.Default.HostNameCluster(vObjectMeta.Name),
+				translate.NameAnnotation:             "testsc",
+				translate.UIDAnnotation:              "",
+				translate.KindAnnotation:             storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
+				translate.TranslateVersionAnnotation: "1",
+				translate.HostNameAnnotation:         translate.Default.HostNameCluster(vObjectMeta.Name),
 			},
 		},
 		Provisioner: "my-provisioner",
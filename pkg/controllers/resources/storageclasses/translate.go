@@ -0,0 +1,45 @@
+package storageclasses
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+)
+
+// csiSecretParameterPrefixes are the prefixes of the well known storage class parameters that reference a
+// secret via a "-name" and "-namespace" parameter pair
+var csiSecretParameterPrefixes = []string{
+	"csi.storage.k8s.io/provisioner-secret",
+	"csi.storage.k8s.io/controller-publish-secret",
+	"csi.storage.k8s.io/node-stage-secret",
+	"csi.storage.k8s.io/node-publish-secret",
+	"csi.storage.k8s.io/controller-expand-secret",
+	"csi.storage.k8s.io/node-expand-secret",
+}
+
+// TranslateStorageClassParametersToHost returns a copy of the parameters of a virtual StorageClass with the secret
+// references of the csi provisioner rewritten to the synced host secrets, so that the host csi driver can read them.
+// References that use templates such as ${pvc.namespace} are resolved by the csi driver and are kept as they are,
+// as are all other parameters. The provisioner of the storage class is not a reference and is never changed.
+func TranslateStorageClassParametersToHost(ctx *synccontext.SyncContext, parameters map[string]string) map[string]string {
+	if parameters == nil {
+		return nil
+	}
+
+	pParameters := maps.Clone(parameters)
+	for _, prefix := range csiSecretParameterPrefixes {
+		nameKey, namespaceKey := prefix+"-name", prefix+"-namespace"
+		name, namespace := parameters[nameKey], parameters[namespaceKey]
+		if name == "" || namespace == "" || strings.Contains(name, "${") || strings.Contains(namespace, "${") {
+			continue
+		}
+
+		pName := translate.Default.HostName(ctx, name, namespace)
+		pParameters[nameKey] = pName.Name
+		pParameters[namespaceKey] = pName.Namespace
+	}
+
+	return pParameters
+}
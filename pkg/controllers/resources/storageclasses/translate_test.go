@@ -0,0 +1,32 @@
+package storageclasses
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+)
+
+func TestTranslateStorageClassParametersToHost(t *testing.T) {
+	parameters := map[string]string{
+		"type": "gp3",
+		"csi.storage.k8s.io/provisioner-secret-name":      "csi-credentials",
+		"csi.storage.k8s.io/provisioner-secret-namespace": "storage",
+		"csi.storage.k8s.io/node-stage-secret-name":       "${pvc.name}",
+		"csi.storage.k8s.io/node-stage-secret-namespace":  "${pvc.namespace}",
+	}
+
+	pParameters := TranslateStorageClassParametersToHost(nil, parameters)
+	pName := translate.Default.HostName(nil, "csi-credentials", "storage")
+	assert.DeepEqual(t, pParameters, map[string]string{
+		"type": "gp3",
+		"csi.storage.k8s.io/provisioner-secret-name":      pName.Name,
+		"csi.storage.k8s.io/provisioner-secret-namespace": pName.Namespace,
+		"csi.storage.k8s.io/node-stage-secret-name":       "${pvc.name}",
+		"csi.storage.k8s.io/node-stage-secret-namespace":  "${pvc.namespace}",
+	})
+
+	// the virtual parameters are not changed
+	assert.Equal(t, parameters["csi.storage.k8s.io/provisioner-secret-name"], "csi-credentials")
+	assert.Assert(t, TranslateStorageClassParametersToHost(nil, nil) == nil)
+}
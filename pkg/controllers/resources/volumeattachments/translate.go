@@ -0,0 +1,33 @@
+package volumeattachments
+
+import (
+	"fmt"
+
+	translatepods "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+// TranslateVolumeAttachmentToHost rewrites the references of a virtual volume attachment to the host objects. The
+// persistent volume name is translated to its host name if persistent volumes are synced to the host, otherwise
+// virtual and host persistent volumes share the same name. Node names are translated with the given node name mapper,
+// the same way the node name of pinned pods is translated. As virtual nodes are usually named after their host nodes,
+// a nil mapper keeps the node name.
+func TranslateVolumeAttachmentToHost(ctx *synccontext.SyncContext, va *storagev1.VolumeAttachment, nodeNameMapper translatepods.NodeNameMapper) error {
+	if ctx.Config.Sync.ToHost.PersistentVolumes.Enabled && va.Spec.Source.PersistentVolumeName != nil && *va.Spec.Source.PersistentVolumeName != "" {
+		pvName := translate.Default.HostNameCluster(*va.Spec.Source.PersistentVolumeName)
+		va.Spec.Source.PersistentVolumeName = &pvName
+	}
+
+	if nodeNameMapper != nil && va.Spec.NodeName != "" {
+		nodeName, err := nodeNameMapper(ctx, va.Spec.NodeName)
+		if err != nil {
+			return fmt.Errorf("translate node name %s: %w", va.Spec.NodeName, err)
+		}
+
+		va.Spec.NodeName = nodeName
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package volumeattachments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestTranslateVolumeAttachmentToHost(t *testing.T) {
+	newVolumeAttachment := func() *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: "csi.example.com",
+				NodeName: "virtual-node",
+				Source: storagev1.VolumeAttachmentSource{
+					PersistentVolumeName: ptr.To("my-pv"),
+				},
+			},
+		}
+	}
+	nodeNameMapper := func(_ *synccontext.SyncContext, vNodeName string) (string, error) {
+		return "host-" + vNodeName, nil
+	}
+	ctx := &synccontext.SyncContext{Context: context.Background(), Config: testingutil.NewFakeConfig()}
+
+	// persistent volumes that are not synced to the host keep their name
+	va := newVolumeAttachment()
+	assert.NilError(t, TranslateVolumeAttachmentToHost(ctx, va, nil))
+	assert.DeepEqual(t, va, newVolumeAttachment())
+
+	ctx.Config.Sync.ToHost.PersistentVolumes.Enabled = true
+	va = newVolumeAttachment()
+	assert.NilError(t, TranslateVolumeAttachmentToHost(ctx, va, nodeNameMapper))
+	assert.Equal(t, *va.Spec.Source.PersistentVolumeName, translate.Default.HostNameCluster("my-pv"))
+	assert.Equal(t, va.Spec.NodeName, "host-virtual-node")
+	assert.Equal(t, va.Spec.Attacher, "csi.example.com")
+
+	// inline volumes have no persistent volume to translate
+	va = &storagev1.VolumeAttachment{Spec: storagev1.VolumeAttachmentSpec{NodeName: "virtual-node"}}
+	assert.NilError(t, TranslateVolumeAttachmentToHost(ctx, va, nil))
+	assert.Assert(t, va.Spec.Source.PersistentVolumeName == nil)
+	assert.Equal(t, va.Spec.NodeName, "virtual-node")
+}
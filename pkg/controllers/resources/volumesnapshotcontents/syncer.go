@@ -70,7 +70,7 @@ func (s *volumeSnapshotContentSyncer) SyncToVirtual(ctx *synccontext.SyncContext
 		return ctrl.Result{}, nil
 	}
 
-	vVSC := s.translateBackwards(event.Host, vVS)
+	vVSC := s.translateBackwards(ctx, event.Host, vVS)
 	err = pro.ApplyPatchesVirtualObject(ctx, nil, vVSC, event.Host, ctx.Config.Sync.ToHost.VolumeSnapshotContents.Patches, false)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -70,10 +70,11 @@ func TestSync(t *testing.T) {
 		Name:            translate.Default.HostNameCluster(vPreProvisioned.Name),
 		ResourceVersion: "12345",
 		Annotations: map[string]string{
-			translate.NameAnnotation:     vObjectMeta.Name,
-			translate.UIDAnnotation:      "",
-			translate.KindAnnotation:     volumesnapshotv1.SchemeGroupVersion.WithKind("VolumeSnapshotContent").String(),
-			translate.HostNameAnnotation: translate.Default.HostNameCluster(vPreProvisioned.Name),
+			translate.NameAnnotation:             vObjectMeta.Name,
+			translate.UIDAnnotation:              "",
+			translate.KindAnnotation:             volumesnapshotv1.SchemeGroupVersion.WithKind("VolumeSnapshotContent").String(),
+			translate.TranslateVersionAnnotation: "1",
+			translate.HostNameAnnotation:         translate.Default.HostNameCluster(vPreProvisioned.Name),
 		},
 	}
 	pPreProvisioned := &volumesnapshotv1.VolumeSnapshotContent{
@@ -93,6 +94,7 @@ func TestSync(t *testing.T) {
 			translate.ManagedAnnotationsAnnotation: "vcluster.loft.sh/host-volumesnapshotcontent",
 			translate.HostNameAnnotation:           "snap-abcd",
 			translate.KindAnnotation:               "snapshot.storage.k8s.io/v1, Kind=VolumeSnapshotContent",
+			translate.TranslateVersionAnnotation:   "1",
 			translate.NameAnnotation:               "snap-abcd",
 			translate.UIDAnnotation:                "",
 		},
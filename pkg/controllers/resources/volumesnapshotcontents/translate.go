@@ -12,7 +12,7 @@ import (
 )
 
 func (s *volumeSnapshotContentSyncer) translate(ctx *synccontext.SyncContext, vVSC *volumesnapshotv1.VolumeSnapshotContent) *volumesnapshotv1.VolumeSnapshotContent {
-	pVSC := translate.HostMetadata(vVSC, s.VirtualToHost(ctx, types.NamespacedName{Name: vVSC.GetName(), Namespace: vVSC.GetNamespace()}, vVSC))
+	pVSC := translate.HostMetadataWithContext(ctx, vVSC, s.VirtualToHost(ctx, types.NamespacedName{Name: vVSC.GetName(), Namespace: vVSC.GetNamespace()}, vVSC))
 	pVolumeSnapshot := mappings.VirtualToHost(ctx, vVSC.Spec.VolumeSnapshotRef.Name, vVSC.Spec.VolumeSnapshotRef.Namespace, mappings.VolumeSnapshots())
 	pVSC.Spec.VolumeSnapshotRef = corev1.ObjectReference{
 		Namespace: pVolumeSnapshot.Namespace,
@@ -21,9 +21,9 @@ func (s *volumeSnapshotContentSyncer) translate(ctx *synccontext.SyncContext, vV
 	return pVSC
 }
 
-func (s *volumeSnapshotContentSyncer) translateBackwards(pVSC *volumesnapshotv1.VolumeSnapshotContent, vVS *volumesnapshotv1.VolumeSnapshot) *volumesnapshotv1.VolumeSnapshotContent {
+func (s *volumeSnapshotContentSyncer) translateBackwards(ctx *synccontext.SyncContext, pVSC *volumesnapshotv1.VolumeSnapshotContent, vVS *volumesnapshotv1.VolumeSnapshot) *volumesnapshotv1.VolumeSnapshotContent {
 	// build virtual VolumeSnapshotContent object
-	vObj := translate.CopyObjectWithName(pVSC, types.NamespacedName{Name: pVSC.Name}, false)
+	vObj := translate.CopyObjectWithContext(ctx, pVSC, types.NamespacedName{Name: pVSC.Name}, false)
 	if vVS != nil {
 		vObj.Spec.VolumeSnapshotRef = translateVolumeSnapshotRefBackwards(&vObj.Spec.VolumeSnapshotRef, vVS)
 	}
@@ -13,7 +13,7 @@ import (
 )
 
 func (s *volumeSnapshotSyncer) translate(ctx *synccontext.SyncContext, vVS *volumesnapshotv1.VolumeSnapshot) (*volumesnapshotv1.VolumeSnapshot, error) {
-	pVS := translate.HostMetadata(vVS, s.VirtualToHost(ctx, types.NamespacedName{Name: vVS.GetName(), Namespace: vVS.GetNamespace()}, vVS))
+	pVS := translate.HostMetadataWithContext(ctx, vVS, s.VirtualToHost(ctx, types.NamespacedName{Name: vVS.GetName(), Namespace: vVS.GetNamespace()}, vVS))
 	if vVS.Annotations != nil && vVS.Annotations[constants.SkipTranslationAnnotation] == "true" {
 		pVS.Spec.Source = vVS.Spec.Source
 	} else {
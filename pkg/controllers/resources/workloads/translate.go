@@ -0,0 +1,98 @@
+package workloads
+
+import (
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// cronJobJobSuffix is appended to the host name of a virtual Job that would otherwise collide with a Job
+// spawned by the host CronJob. Spawned Jobs always end in a numeric suffix, so names ending in it never collide.
+const cronJobJobSuffix = "v"
+
+// TranslatePodTemplateToHost translates the metadata of a workload pod template the same way the metadata of synced
+// pods is translated, so that pods created from the template on the host match the selector returned by
+// TranslateSelectorToHost. The annotations are translated like the annotations of synced pods, except for the
+// name, uid and kind annotations, as the pods created from the template have no virtual counterpart.
+func TranslatePodTemplateToHost(_ *synccontext.SyncContext, vNamespace string, tmpl *corev1.PodTemplateSpec) {
+	labels := tmpl.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: vNamespace, Annotations: tmpl.Annotations}}
+	annotations := translate.HostAnnotationsWithOptions(vPod, nil, translate.HostAnnotationsOptions{SkipKindAnnotation: true})
+	delete(annotations, translate.NameAnnotation)
+	delete(annotations, translate.UIDAnnotation)
+
+	tmpl.Labels = translate.HostLabelsMap(labels, nil, vNamespace, true)
+	tmpl.Annotations = annotations
+}
+
+// TranslateSelectorToHost translates the pod selector of a workload in the given virtual namespace. Besides the
+// translated selector labels, the selector only matches pods of the same virtual namespace and vCluster, see
+// translate.NamespaceSelectorLabels.
+func TranslateSelectorToHost(vNamespace string, selector *metav1.LabelSelector) *metav1.LabelSelector {
+	if selector == nil {
+		return nil
+	}
+
+	return translate.MergeLabelSelectors(translate.HostLabelSelector(selector), &metav1.LabelSelector{
+		MatchLabels: translate.NamespaceSelectorLabels(vNamespace),
+	})
+}
+
+// CronJobHostName returns the host name of a virtual CronJob. The host CronJob spawns its own Jobs named
+// "<host cronjob name>-<scheduled time>", see JobHostName for how synced virtual Jobs avoid these names.
+func CronJobHostName(ctx *synccontext.SyncContext, vCronJob *batchv1.CronJob) types.NamespacedName {
+	return translate.Default.HostName(ctx, vCronJob.Name, vCronJob.Namespace)
+}
+
+// JobHostName returns the host name of a virtual Job. Jobs spawned by a virtual CronJob are named
+// "<cronjob name>-<scheduled time>" and if the translator keeps names as they are, their host name is exactly
+// the name the host CronJob uses for the Job it spawns for the same schedule. In that case the host name gets
+// a deterministic suffix, so the same virtual Job always maps to the same host Job and never to a host
+// spawned one.
+func JobHostName(ctx *synccontext.SyncContext, vJob *batchv1.Job) types.NamespacedName {
+	pName := translate.Default.HostName(ctx, vJob.Name, vJob.Namespace)
+	owner := metav1.GetControllerOf(vJob)
+	if owner == nil || owner.Kind != "CronJob" {
+		return pName
+	}
+
+	pCronJobName := translate.Default.HostName(ctx, owner.Name, vJob.Namespace)
+	if pCronJobName.Namespace == pName.Namespace && IsCronJobJobName(pCronJobName.Name, pName.Name) {
+		pName.Name = translate.SafeConcatName(pName.Name, cronJobJobSuffix)
+	}
+
+	return pName
+}
+
+// IsCronJobJobName checks if jobName has the form the CronJob controller uses for Jobs spawned by
+// the CronJob cronJobName.
+func IsCronJobJobName(cronJobName, jobName string) bool {
+	scheduledTime, ok := strings.CutPrefix(jobName, cronJobName+"-")
+	if !ok || scheduledTime == "" {
+		return false
+	}
+
+	for _, c := range scheduledTime {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsHostCronJobJob checks if a host Job was spawned by a host CronJob instead of being synced from the
+// virtual cluster. The Job syncer should neither import nor delete these Jobs.
+func IsHostCronJobJob(pJob *batchv1.Job) bool {
+	owner := metav1.GetControllerOf(pJob)
+	return owner != nil && owner.Kind == "CronJob"
+}
@@ -0,0 +1,149 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestTranslatePodTemplateToHost(t *testing.T) {
+	vSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "web", translate.VClusterReleaseLabel: "my-release"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend"}},
+		},
+	}
+	tmpl := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"app": "web", translate.VClusterReleaseLabel: "my-release", "tier": "frontend"},
+			Annotations: map[string]string{"prometheus.io/scrape": "true", translate.NameAnnotation: "spoofed"},
+		},
+	}
+
+	TranslatePodTemplateToHost(nil, "test", tmpl)
+	assert.Equal(t, tmpl.Labels[translate.HostLabel(translate.VClusterReleaseLabel)], "my-release")
+	assert.Equal(t, tmpl.Labels[translate.NamespaceLabel], "test")
+	assert.Equal(t, tmpl.Annotations["prometheus.io/scrape"], "true")
+	assert.Equal(t, tmpl.Annotations[translate.ManagedAnnotationsAnnotation], "prometheus.io/scrape")
+	assert.Equal(t, tmpl.Annotations[translate.NamespaceAnnotation], "test")
+	_, ok := tmpl.Annotations[translate.NameAnnotation]
+	assert.Assert(t, !ok, "pods created from the template have no virtual name")
+
+	// the host template matches the translated selector
+	hostSelector, err := metav1.LabelSelectorAsSelector(TranslateSelectorToHost("test", vSelector))
+	assert.NilError(t, err)
+	assert.Assert(t, hostSelector.Matches(labels.Set(tmpl.Labels)))
+
+	// but pods of the same workload in another namespace don't
+	otherTmpl := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web", translate.VClusterReleaseLabel: "my-release", "tier": "frontend"}}}
+	TranslatePodTemplateToHost(nil, "other", otherTmpl)
+	assert.Assert(t, !hostSelector.Matches(labels.Set(otherTmpl.Labels)))
+
+	// the untranslated selector doesn't match the host template
+	vSelectorParsed, err := metav1.LabelSelectorAsSelector(vSelector)
+	assert.NilError(t, err)
+	assert.Assert(t, !vSelectorParsed.Matches(labels.Set(tmpl.Labels)))
+}
+
+// keepNameTranslator keeps the names of objects and only maps the namespace, like the multi namespace mode does
+type keepNameTranslator struct {
+	translate.Translator
+}
+
+func (keepNameTranslator) HostName(_ *synccontext.SyncContext, vName, vNamespace string) types.NamespacedName {
+	return types.NamespacedName{Name: vName, Namespace: "host-" + vNamespace}
+}
+
+func newCronJobJob(name, namespace, cronJobName string) *batchv1.Job {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if cronJobName != "" {
+		job.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "batch/v1",
+			Kind:       "CronJob",
+			Name:       cronJobName,
+			Controller: ptr.To(true),
+		}}
+	}
+
+	return job
+}
+
+func TestTranslateSelectorToHostAnnotationsForTopology(t *testing.T) {
+	defer func(useAnnotations bool, vClusterName string) {
+		translate.UseAnnotationsForTopology, translate.VClusterName = useAnnotations, vClusterName
+	}(translate.UseAnnotationsForTopology, translate.VClusterName)
+	translate.UseAnnotationsForTopology = true
+
+	// pods with identical labels in two virtual namespaces and in another vCluster
+	hostPodLabels := func(vNamespace string) labels.Set {
+		tmpl := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}}}
+		TranslatePodTemplateToHost(nil, vNamespace, tmpl)
+		return tmpl.Labels
+	}
+	podA := hostPodLabels("team-a")
+	podB := hostPodLabels("team-b")
+	translate.VClusterName = "other"
+	podOther := hostPodLabels("team-a")
+	translate.VClusterName = "suffix"
+
+	// the topology is not exposed in the labels
+	_, ok := podA[translate.MarkerLabel]
+	assert.Assert(t, !ok)
+	_, ok = podA[translate.NamespaceLabel]
+	assert.Assert(t, !ok)
+
+	selector, err := metav1.LabelSelectorAsSelector(TranslateSelectorToHost("team-a", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}))
+	assert.NilError(t, err)
+	assert.Assert(t, selector.Matches(podA))
+	assert.Assert(t, !selector.Matches(podB))
+	assert.Assert(t, !selector.Matches(podOther))
+}
+
+func TestJobHostName(t *testing.T) {
+	defer func(translator translate.Translator) { translate.Default = translator }(translate.Default)
+
+	vCronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "test"}}
+
+	// names are kept, so the virtual job overlaps with the job spawned by the host cronjob
+	translate.Default = keepNameTranslator{Translator: translate.Default}
+	pCronJobName := CronJobHostName(nil, vCronJob)
+	assert.Equal(t, pCronJobName, types.NamespacedName{Name: "backup", Namespace: "host-test"})
+	hostSpawnedName := pCronJobName.Name + "-28790100"
+	assert.Assert(t, IsCronJobJobName(pCronJobName.Name, hostSpawnedName))
+
+	pJobName := JobHostName(nil, newCronJobJob("backup-28790100", "test", "backup"))
+	assert.Equal(t, pJobName, types.NamespacedName{Name: "backup-28790100-v", Namespace: "host-test"})
+	assert.Assert(t, pJobName.Name != hostSpawnedName)
+	assert.Assert(t, !IsCronJobJobName(pCronJobName.Name, pJobName.Name))
+
+	// the disambiguation is deterministic
+	assert.Equal(t, JobHostName(nil, newCronJobJob("backup-28790100", "test", "backup")), pJobName)
+
+	// jobs not owned by a cronjob are left alone
+	assert.Equal(t, JobHostName(nil, newCronJobJob("backup-28790100", "test", "")), types.NamespacedName{Name: "backup-28790100", Namespace: "host-test"})
+
+	// jobs of a cronjob that were renamed don't overlap
+	assert.Equal(t, JobHostName(nil, newCronJobJob("backup-manual", "test", "backup")), types.NamespacedName{Name: "backup-manual", Namespace: "host-test"})
+
+	// translated names never overlap, so they are not changed
+	translate.Default = translate.NewSingleNamespaceTranslator("vcluster")
+	ctx := &synccontext.SyncContext{}
+	pJobName = JobHostName(ctx, newCronJobJob("backup-28790100", "test", "backup"))
+	assert.Equal(t, pJobName, translate.Default.HostName(ctx, "backup-28790100", "test"))
+	assert.Assert(t, !IsCronJobJobName(CronJobHostName(ctx, vCronJob).Name, pJobName.Name))
+}
+
+func TestIsHostCronJobJob(t *testing.T) {
+	assert.Assert(t, IsHostCronJobJob(newCronJobJob("backup-28790100", "test", "backup")))
+	assert.Assert(t, !IsHostCronJobJob(newCronJobJob("backup-28790100", "test", "")))
+	assert.Assert(t, !IsCronJobJobName("backup", "backup-"))
+	assert.Assert(t, !IsCronJobJobName("backup", "backups-123"))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
@@ -109,7 +110,7 @@ func TryToTranslateBackByAnnotations(ctx *synccontext.SyncContext, req types.Nam
 	}
 
 	// exclude objects that are from other vClusters
-	markerLabel := pObj.GetLabels()[translate.MarkerLabel]
+	markerLabel := translate.GetMarker(pObj)
 	if markerLabel != "" {
 		if pObj.GetNamespace() != "" && markerLabel != translate.VClusterName {
 			return types.NamespacedName{}
@@ -299,3 +300,30 @@ func (n *mapper) IsManaged(ctx *synccontext.SyncContext, pObj client.Object) (bo
 
 	return translate.Default.IsManaged(ctx, pObj), nil
 }
+
+// CheckNameReversible verifies that the host name the translator computes for the given virtual object can be
+// translated back to the virtual name via the annotations written by translate.HostMetadataWithContext. This is meant to
+// lock the naming contract of Translator implementations in tests and for debugging.
+func CheckNameReversible(ctx *synccontext.SyncContext, translator translate.Translator, gvk schema.GroupVersionKind, vName, vNamespace string) error {
+	virtualName := types.NamespacedName{Name: vName, Namespace: vNamespace}
+	hostName := types.NamespacedName{Name: translator.HostNameCluster(vName)}
+	if vNamespace != "" {
+		hostName = translator.HostName(ctx, vName, vNamespace)
+	}
+	if hostName.Name == "" {
+		return fmt.Errorf("translator returned an empty host name for %s %s", gvk.Kind, virtualName.String())
+	}
+
+	vObj := &unstructured.Unstructured{}
+	vObj.SetGroupVersionKind(gvk)
+	vObj.SetName(vName)
+	vObj.SetNamespace(vNamespace)
+	pObj := translate.HostMetadataWithContext(ctx, vObj, hostName)
+
+	reversedName := TryToTranslateBackByAnnotations(ctx, hostName, pObj, gvk)
+	if reversedName != virtualName {
+		return fmt.Errorf("host name %s of %s %s translates back to %q", hostName.String(), gvk.Kind, virtualName.String(), reversedName.String())
+	}
+
+	return nil
+}
@@ -357,3 +357,36 @@ func TestTryToTranslateBackByName(t *testing.T) {
 	}
 	assert.Equal(t, TryToTranslateBackByName(syncContext, req, gvk).String(), req.String())
 }
+
+// identityTranslator keeps virtual names and namespaces as they are on the host
+type identityTranslator struct {
+	translate.Translator
+}
+
+func (identityTranslator) HostName(_ *synccontext.SyncContext, vName, vNamespace string) types.NamespacedName {
+	return types.NamespacedName{Name: vName, Namespace: vNamespace}
+}
+
+// emptyTranslator can't translate any names
+type emptyTranslator struct {
+	translate.Translator
+}
+
+func (emptyTranslator) HostName(_ *synccontext.SyncContext, _, _ string) types.NamespacedName {
+	return types.NamespacedName{}
+}
+
+func TestCheckNameReversible(t *testing.T) {
+	syncContext := &synccontext.SyncContext{
+		Context: context.TODO(),
+	}
+	secretGvk := corev1.SchemeGroupVersion.WithKind("Secret")
+	singleNamespace := translate.NewSingleNamespaceTranslator("target-namespace")
+
+	assert.NilError(t, CheckNameReversible(syncContext, singleNamespace, secretGvk, "my-secret", "my-namespace"))
+	assert.NilError(t, CheckNameReversible(syncContext, singleNamespace, secretGvk, "a-very-long-secret-name-that-will-be-hashed-by-the-single-namespace-translator", "my-namespace"))
+	assert.NilError(t, CheckNameReversible(syncContext, identityTranslator{Translator: singleNamespace}, secretGvk, "my-secret", "my-namespace"))
+
+	err := CheckNameReversible(syncContext, emptyTranslator{Translator: singleNamespace}, secretGvk, "my-secret", "my-namespace")
+	assert.ErrorContains(t, err, "empty host name")
+}
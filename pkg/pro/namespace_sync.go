@@ -7,6 +7,8 @@ import (
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 )
 
+// GetNamespaceMapper returns the mapper of the namespace syncer. It matches host namespaces with the mappings of
+// namespaces.HostMappings, which include the host name prefix.
 var GetNamespaceMapper = func(_ *synccontext.RegisterContext, _ synccontext.Mapper) (synccontext.Mapper, error) {
 	return nil, NewFeatureError(licenseapi.SyncNamespacesTohost)
 }
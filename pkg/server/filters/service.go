@@ -210,7 +210,7 @@ func createService(ctx *synccontext.SyncContext, req *http.Request, decoder enco
 		vService.Name = vService.GenerateName + random.String(5)
 	}
 
-	newService := translate.HostMetadata(vService, mappings.VirtualToHost(ctx, vService.Name, vService.Namespace, mappings.Services()))
+	newService := translate.HostMetadataWithContext(ctx, vService, mappings.VirtualToHost(ctx, vService.Name, vService.Namespace, mappings.Services()))
 	if newService.Annotations == nil {
 		newService.Annotations = map[string]string{}
 	}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
@@ -13,8 +15,11 @@ import (
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/loft-sh/vcluster/pkg/util/namespaces"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -25,6 +30,8 @@ import (
 const (
 	AnnotationDistro = "vcluster.loft.sh/distro"
 	AnnotationStore  = "vcluster.loft.sh/store"
+
+	hashSaltKey = "salt"
 )
 
 func InitClientConfig() (*rest.Config, string, error) {
@@ -76,7 +83,7 @@ func InitClients(vConfig *config.VirtualClusterConfig) error {
 
 	// get workload target namespace translator
 	if vConfig.Sync.ToHost.Namespaces.Enabled {
-		translate.Default, err = pro.GetWithSyncedNamespacesTranslator(vConfig.HostNamespace, vConfig.Sync.ToHost.Namespaces.Mappings)
+		translate.Default, err = pro.GetWithSyncedNamespacesTranslator(vConfig.HostNamespace, namespaces.HostMappings(&vConfig.Config))
 		if err != nil {
 			return err
 		}
@@ -90,6 +97,16 @@ func InitClients(vConfig *config.VirtualClusterConfig) error {
 func InitAndValidateConfig(ctx context.Context, vConfig *config.VirtualClusterConfig) error {
 	// set global vCluster name
 	translate.VClusterName = vConfig.Name
+	translate.UseAnnotationsForTopology = vConfig.Experimental.SyncSettings.UseAnnotationsForTopology
+	translate.PreserveHostAnnotationKeys = vConfig.Experimental.SyncSettings.PreserveHostAnnotations
+	translate.SyncLabelKeys = vConfig.Experimental.SyncSettings.SyncLabels
+	if mode := vConfig.Experimental.SyncSettings.TranslateCompatibilityMode; mode != 0 {
+		if err := translate.ValidateCompatibilityMode(mode); err != nil {
+			return err
+		}
+
+		translate.CompatibilityMode = mode
+	}
 
 	// set workload namespace
 	err := os.Setenv("NAMESPACE", vConfig.HostNamespace)
@@ -103,6 +120,13 @@ func InitAndValidateConfig(ctx context.Context, vConfig *config.VirtualClusterCo
 		return err
 	}
 
+	if vConfig.Experimental.SyncSettings.SaltHostNames {
+		translate.HashSalt, err = EnsureHashSalt(ctx, vConfig.HostClient, vConfig.Name, vConfig.HostNamespace)
+		if err != nil {
+			return fmt.Errorf("ensure hash salt: %w", err)
+		}
+	}
+
 	if err := EnsureBackingStoreChanges(
 		ctx,
 		vConfig.HostClient,
@@ -140,6 +164,47 @@ func EnsureBackingStoreChanges(ctx context.Context, client kubernetes.Interface,
 	return nil
 }
 
+// EnsureHashSalt returns the hash salt of the vCluster stored in the secret vc-hash-salt-<name>. If the secret does not
+// exist yet, a new random salt is generated and stored, so that the salt stays the same for the lifetime of the vCluster.
+func EnsureHashSalt(ctx context.Context, client kubernetes.Interface, name, namespace string) (string, error) {
+	secretName := "vc-hash-salt-" + name
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err == nil {
+		if len(secret.Data[hashSaltKey]) == 0 {
+			return "", fmt.Errorf("secret %s/%s has no %s", namespace, secretName, hashSaltKey)
+		}
+
+		return string(secret.Data[hashSaltKey]), nil
+	} else if !kerrors.IsNotFound(err) {
+		return "", fmt.Errorf("get secret: %w", err)
+	}
+
+	saltBytes := make([]byte, 32)
+	if _, err := rand.Read(saltBytes); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	salt := hex.EncodeToString(saltBytes)
+	_, err = client.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			hashSaltKey: []byte(salt),
+		},
+	}, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		// another replica created the salt in the meantime
+		return EnsureHashSalt(ctx, client, name, namespace)
+	} else if err != nil {
+		return "", fmt.Errorf("create secret: %w", err)
+	}
+
+	klog.Infof("Created hash salt secret %s/%s", namespace, secretName)
+	return salt, nil
+}
+
 // CheckUsingSecretAnnotation checks for backend store and distro changes using annotations on the vCluster's secret annotations.
 // Returns true, if both annotations are set and the check was successful, otherwise false.
 func CheckUsingSecretAnnotation(ctx context.Context, client kubernetes.Interface, name, namespace string, backingStoreType vclusterconfig.StoreType) (bool, error) {
@@ -20,6 +20,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/telemetry"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -392,6 +393,8 @@ func initControllerContext(
 
 		StopChan: stopChan,
 		Config:   vClusterOptions,
+
+		Owner: translate.Owner,
 	}
 
 	etcdClient, err := etcd.NewFromConfig(ctx, vClusterOptions)
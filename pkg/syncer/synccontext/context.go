@@ -38,6 +38,9 @@ type ControllerContext struct {
 
 	// Mappings hold the objects mappings store
 	Mappings MappingsRegistry
+
+	// Owner is the object that owns synced host objects, usually the vCluster service
+	Owner client.Object
 }
 
 type RegisterContext struct {
@@ -52,6 +55,8 @@ type RegisterContext struct {
 
 	VirtualManager ctrl.Manager
 	HostManager    ctrl.Manager
+
+	Owner client.Object
 }
 
 type Filter func(http.Handler, *ControllerContext) http.Handler
@@ -71,6 +76,8 @@ func (c *ControllerContext) ToRegisterContext() *RegisterContext {
 		HostManager:    c.HostManager,
 
 		Mappings: c.Mappings,
+
+		Owner: c.Owner,
 	}
 }
 
@@ -82,6 +89,7 @@ func (r *RegisterContext) ToSyncContext(logName string) *SyncContext {
 		CurrentNamespace:       r.CurrentNamespace,
 		CurrentNamespaceClient: r.CurrentNamespaceClient,
 		Mappings:               r.Mappings,
+		Owner:                  r.Owner,
 	}
 	if r.HostManager != nil {
 		syncCtx.HostClient = r.HostManager.GetClient()
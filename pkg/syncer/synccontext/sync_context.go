@@ -25,6 +25,9 @@ type SyncContext struct {
 
 	CurrentNamespace       string
 	CurrentNamespaceClient client.Client
+
+	// Owner is the owner of synced namespaced host objects. If nil, the default owner of the translate package is used.
+	Owner client.Object
 }
 
 func (s *SyncContext) Close() error {
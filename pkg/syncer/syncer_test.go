@@ -54,7 +54,7 @@ func (s *mockSyncer) Syncer() syncertypes.Sync[client.Object] {
 
 // SyncToHost is called when a virtual object was created and needs to be synced down to the physical cluster
 func (s *mockSyncer) SyncToHost(ctx *synccontext.SyncContext, event *synccontext.SyncToHostEvent[*corev1.Secret]) (ctrl.Result, error) {
-	pObj := translate.HostMetadata(event.Virtual, s.VirtualToHost(ctx, types.NamespacedName{Name: event.Virtual.GetName(), Namespace: event.Virtual.GetNamespace()}, event.Virtual))
+	pObj := translate.HostMetadataWithContext(ctx, event.Virtual, s.VirtualToHost(ctx, types.NamespacedName{Name: event.Virtual.GetName(), Namespace: event.Virtual.GetNamespace()}, event.Virtual))
 	if pObj == nil {
 		return ctrl.Result{}, errors.New("naive translate create failed")
 	}
@@ -199,12 +199,13 @@ func TestController(t *testing.T) {
 							Name:      translator.HostName(nil, "a", namespaceInVClusterA).Name,
 							Namespace: testingutil.DefaultTestTargetNamespace,
 							Annotations: map[string]string{
-								translate.NameAnnotation:          "a",
-								translate.NamespaceAnnotation:     namespaceInVClusterA,
-								translate.UIDAnnotation:           "123",
-								translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Secret").String(),
-								translate.HostNameAnnotation:      translator.HostName(nil, "a", namespaceInVClusterA).Name,
-								translate.HostNamespaceAnnotation: testingutil.DefaultTestTargetNamespace,
+								translate.NameAnnotation:             "a",
+								translate.NamespaceAnnotation:        namespaceInVClusterA,
+								translate.UIDAnnotation:              "123",
+								translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Secret").String(),
+								translate.TranslateVersionAnnotation: "1",
+								translate.HostNameAnnotation:         translator.HostName(nil, "a", namespaceInVClusterA).Name,
+								translate.HostNamespaceAnnotation:    testingutil.DefaultTestTargetNamespace,
 							},
 							Labels: map[string]string{
 								translate.NamespaceLabel: namespaceInVClusterA,
@@ -360,12 +361,13 @@ func TestReconcile(t *testing.T) {
 							Name:      translator.HostName(nil, "a", namespaceInVClusterA).Name,
 							Namespace: testingutil.DefaultTestTargetNamespace,
 							Annotations: map[string]string{
-								translate.NameAnnotation:          "a",
-								translate.NamespaceAnnotation:     namespaceInVClusterA,
-								translate.UIDAnnotation:           "123",
-								translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Secret").String(),
-								translate.HostNamespaceAnnotation: testingutil.DefaultTestTargetNamespace,
-								translate.HostNameAnnotation:      translator.HostName(nil, "a", namespaceInVClusterA).Name,
+								translate.NameAnnotation:             "a",
+								translate.NamespaceAnnotation:        namespaceInVClusterA,
+								translate.UIDAnnotation:              "123",
+								translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Secret").String(),
+								translate.TranslateVersionAnnotation: "1",
+								translate.HostNamespaceAnnotation:    testingutil.DefaultTestTargetNamespace,
+								translate.HostNameAnnotation:         translator.HostName(nil, "a", namespaceInVClusterA).Name,
 							},
 							Labels: map[string]string{
 								translate.NamespaceLabel: namespaceInVClusterA,
@@ -442,13 +444,14 @@ func TestReconcile(t *testing.T) {
 							Name:      translator.HostName(nil, "a", namespaceInVClusterA).Name,
 							Namespace: testingutil.DefaultTestTargetNamespace,
 							Annotations: map[string]string{
-								"app":                             "existing",
-								translate.NameAnnotation:          "a",
-								translate.NamespaceAnnotation:     namespaceInVClusterA,
-								translate.UIDAnnotation:           "123",
-								translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Secret").String(),
-								translate.HostNameAnnotation:      translator.HostName(nil, "a", namespaceInVClusterA).Name,
-								translate.HostNamespaceAnnotation: testingutil.DefaultTestTargetNamespace,
+								"app":                                "existing",
+								translate.NameAnnotation:             "a",
+								translate.NamespaceAnnotation:        namespaceInVClusterA,
+								translate.UIDAnnotation:              "123",
+								translate.KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Secret").String(),
+								translate.TranslateVersionAnnotation: "1",
+								translate.HostNameAnnotation:         translator.HostName(nil, "a", namespaceInVClusterA).Name,
+								translate.HostNamespaceAnnotation:    testingutil.DefaultTestTargetNamespace,
 							},
 							Labels: map[string]string{
 								translate.NamespaceLabel: namespaceInVClusterA,
@@ -125,7 +125,7 @@ func matchesHostObject(ctx *synccontext.SyncContext, hostName, hostNamespace str
 			return types.NamespacedName{}, false
 		}
 
-		vNamespace, matched := namespaces.TranslateHostNamespace(ctx.Config.Name, hostNamespace, ctx.Config.Config.Sync.ToHost.Namespaces.Mappings.ByName)
+		vNamespace, matched := namespaces.TranslateHostNamespace(ctx.Config.Name, hostNamespace, namespaces.HostMappings(&ctx.Config.Config).ByName)
 		if matched {
 			return types.NamespacedName{Namespace: vNamespace, Name: hostName}, true
 		}
@@ -0,0 +1,37 @@
+package namespaces
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OwnedNamespaceLabels returns the labels the namespace syncer sets on every host namespace it creates
+// for the given vCluster
+func OwnedNamespaceLabels(vClusterName, vClusterNamespace string) map[string]string {
+	return map[string]string{
+		constants.VClusterNameLabel:      vClusterName,
+		constants.VClusterNamespaceLabel: vClusterNamespace,
+	}
+}
+
+// OwnedNamespaceSelector returns a selector matching all host namespaces that were created by the given vCluster
+func OwnedNamespaceSelector(vClusterName, vClusterNamespace string) labels.Selector {
+	return labels.SelectorFromSet(OwnedNamespaceLabels(vClusterName, vClusterNamespace))
+}
+
+// ListOwnedNamespaces lists all host namespaces that were created by the given vCluster. This also works after
+// the vCluster was deleted, e.g. to clean up orphaned namespaces.
+func ListOwnedNamespaces(ctx context.Context, hostClient client.Client, vClusterName, vClusterNamespace string) ([]corev1.Namespace, error) {
+	namespaceList := &corev1.NamespaceList{}
+	err := hostClient.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: OwnedNamespaceSelector(vClusterName, vClusterNamespace)})
+	if err != nil {
+		return nil, fmt.Errorf("list namespaces of vcluster %s/%s: %w", vClusterNamespace, vClusterName, err)
+	}
+
+	return namespaceList.Items, nil
+}
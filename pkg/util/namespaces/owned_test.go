@@ -0,0 +1,37 @@
+package namespaces_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	"github.com/loft-sh/vcluster/pkg/util/namespaces"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestListOwnedNamespaces(t *testing.T) {
+	newNamespace := func(name string, nsLabels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nsLabels}}
+	}
+
+	hostClient := testingutil.NewFakeClient(scheme.Scheme,
+		newNamespace("team-a", namespaces.OwnedNamespaceLabels("vcluster", "vcluster-ns")),
+		newNamespace("team-b", labels.Merge(namespaces.OwnedNamespaceLabels("vcluster", "vcluster-ns"), map[string]string{"extra": "label"})),
+		newNamespace("other-vcluster", namespaces.OwnedNamespaceLabels("other", "vcluster-ns")),
+		newNamespace("same-name-other-namespace", namespaces.OwnedNamespaceLabels("vcluster", "other-ns")),
+		newNamespace("unmanaged", nil),
+	)
+
+	owned, err := namespaces.ListOwnedNamespaces(context.Background(), hostClient, "vcluster", "vcluster-ns")
+	assert.NilError(t, err)
+
+	names := []string{}
+	for _, namespace := range owned {
+		names = append(names, namespace.Name)
+	}
+	assert.DeepEqual(t, names, []string{"team-a", "team-b"})
+}
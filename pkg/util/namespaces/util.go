@@ -2,6 +2,8 @@ package namespaces
 
 import (
 	"strings"
+
+	"github.com/loft-sh/vcluster/config"
 )
 
 const (
@@ -43,6 +45,31 @@ func ProcessNamespaceName(namespaceName string, vclusterName string) string {
 	return strings.ReplaceAll(namespaceName, NamePlaceholder, vclusterName)
 }
 
+// PrefixHostNamespaces returns a copy of the mappings with the prefix prepended to every host namespace and host
+// namespace pattern, see sync.toHost.namespaces.hostNamePrefix. Host namespaces without the prefix are then never
+// matched by the mappings.
+func PrefixHostNamespaces(mappings map[string]string, prefix string) map[string]string {
+	if mappings == nil {
+		return nil
+	}
+
+	prefixed := make(map[string]string, len(mappings))
+	for vName, hName := range mappings {
+		prefixed[vName] = prefix + hName
+	}
+
+	return prefixed
+}
+
+// HostMappings returns the mappings of the namespace syncer with sync.toHost.namespaces.hostNamePrefix prepended
+// to every host namespace, see PrefixHostNamespaces. Everything that matches host namespaces against the mappings
+// uses these instead of sync.toHost.namespaces.mappings, the config itself is not changed.
+func HostMappings(c *config.Config) config.FromHostMappings {
+	mappings := c.Sync.ToHost.Namespaces.Mappings
+	mappings.ByName = PrefixHostNamespaces(mappings.ByName, c.Sync.ToHost.Namespaces.HostNamePrefix)
+	return mappings
+}
+
 // TranslateHostNamespace returns virtual namespace name based on host namespace and mappings
 func TranslateHostNamespace(vClusterName, hostNamespace string, mappings map[string]string) (string, bool) {
 	// Priority 1: Exact host name to exact virtual name match
@@ -0,0 +1,54 @@
+package namespaces_test
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/util/namespaces"
+	"gotest.tools/v3/assert"
+)
+
+func TestPrefixHostNamespaces(t *testing.T) {
+	mappings := namespaces.PrefixHostNamespaces(map[string]string{
+		"exact":   "host-exact",
+		"vns-*":   "hns-*",
+		"named-*": "${name}-*",
+	}, "team-a-")
+	assert.DeepEqual(t, mappings, map[string]string{
+		"exact":   "team-a-host-exact",
+		"vns-*":   "team-a-hns-*",
+		"named-*": "team-a-${name}-*",
+	})
+
+	// only host namespaces with the prefix are matched
+	for hostNamespace, expected := range map[string]string{
+		"team-a-host-exact":    "exact",
+		"team-a-hns-foo":       "vns-foo",
+		"team-a-my-vcluster-x": "named-x",
+		"host-exact":           "",
+		"hns-foo":              "",
+		"team-b-hns-foo":       "",
+		"my-vcluster-x":        "",
+	} {
+		vNamespace, matched := namespaces.TranslateHostNamespace("my-vcluster", hostNamespace, mappings)
+		assert.Equal(t, matched, expected != "", hostNamespace)
+		assert.Equal(t, vNamespace, expected, hostNamespace)
+	}
+}
+
+func TestHostMappings(t *testing.T) {
+	c := &config.Config{}
+	c.Sync.ToHost.Namespaces.HostNamePrefix = "team-a-"
+	c.Sync.ToHost.Namespaces.Mappings.ByName = map[string]string{"vns-*": "hns-*"}
+
+	assert.DeepEqual(t, namespaces.HostMappings(c).ByName, map[string]string{"vns-*": "team-a-hns-*"})
+
+	// the config is not changed, so the prefix is never applied twice
+	assert.Equal(t, c.Sync.ToHost.Namespaces.HostNamePrefix, "team-a-")
+	assert.DeepEqual(t, c.Sync.ToHost.Namespaces.Mappings.ByName, map[string]string{"vns-*": "hns-*"})
+	assert.DeepEqual(t, namespaces.HostMappings(c).ByName, map[string]string{"vns-*": "team-a-hns-*"})
+
+	// without a prefix the mappings are kept as they are
+	c.Sync.ToHost.Namespaces.HostNamePrefix = ""
+	assert.DeepEqual(t, namespaces.HostMappings(c).ByName, map[string]string{"vns-*": "hns-*"})
+}
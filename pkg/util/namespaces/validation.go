@@ -20,11 +20,17 @@ func ValidateNamespaceSyncConfig(c *config.Config, name, namespace string) error
 		return fmt.Errorf("%s are empty", configPathIdentifier)
 	}
 
+	// the host name prefix is validated as part of the host namespaces it is prepended to
+	hostNamePrefix := c.Sync.ToHost.Namespaces.HostNamePrefix
+	if hostNamePrefix != "" && strings.Contains(hostNamePrefix, WildcardChar) {
+		return fmt.Errorf("config.sync.toHost.namespaces.hostNamePrefix: '%s' cannot contain a wildcard '*'", hostNamePrefix)
+	}
+
 	virtualNamespaceSet := sets.NewString()
 	hostNamespaceSet := sets.NewString()
 
 	// for each vnamespace:hostNamespace mapping
-	for vNS, hNS := range c.Sync.ToHost.Namespaces.Mappings.ByName {
+	for vNS, hNS := range PrefixHostNamespaces(c.Sync.ToHost.Namespaces.Mappings.ByName, hostNamePrefix) {
 		// first check for duplicate entries
 		if virtualNamespaceSet.Has(vNS) {
 			return fmt.Errorf("%s: duplicate virtual namespace '%s' found in mappings", configPathIdentifier, vNS)
@@ -0,0 +1,78 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Audit receives an entry for every virtual object that is translated to a host object by HostMetadataWithContext.
+// It is nil by default, which disables auditing, usually set at start time
+var Audit AuditSink
+
+// AuditSink records the translations of virtual objects to host objects
+type AuditSink interface {
+	// Record is called for every translated object. It is called synchronously during translation,
+	// so slow sinks should buffer entries.
+	Record(entry AuditEntry)
+}
+
+// AuditEntry is a single translation of a virtual object to a host object
+type AuditEntry struct {
+	VirtualGVK schema.GroupVersionKind `json:"virtualGVK"`
+	Virtual    types.NamespacedName    `json:"virtual"`
+	Host       types.NamespacedName    `json:"host"`
+	Timestamp  time.Time               `json:"timestamp"`
+}
+
+func recordAudit(vObj client.Object, pName types.NamespacedName) {
+	if Audit == nil {
+		return
+	}
+
+	gvk, _ := apiutil.GVKForObject(vObj, scheme.Scheme)
+	Audit.Record(AuditEntry{
+		VirtualGVK: gvk,
+		Virtual:    types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()},
+		Host:       pName,
+		Timestamp:  time.Now(),
+	})
+}
+
+// NewFileAuditSink returns an audit sink that appends the entries as json lines to the file at path.
+func NewFileAuditSink(path string) (AuditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit file: %w", err)
+	}
+
+	return &fileAuditSink{file: file}, nil
+}
+
+type fileAuditSink struct {
+	m    sync.Mutex
+	file *os.File
+}
+
+func (f *fileAuditSink) Record(entry AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		klog.Errorf("Error encoding translation audit entry: %v", err)
+		return
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		klog.Errorf("Error writing translation audit entry: %v", err)
+	}
+}
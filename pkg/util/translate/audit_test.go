@@ -0,0 +1,74 @@
+package translate
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type collectingAuditSink struct {
+	entries []AuditEntry
+}
+
+func (c *collectingAuditSink) Record(entry AuditEntry) {
+	c.entries = append(c.entries, entry)
+}
+
+func TestAudit(t *testing.T) {
+	defer func() { Audit = nil }()
+
+	vObjs := []*corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "test"}},
+	}
+	nameFunc := func(vObj *corev1.ConfigMap) types.NamespacedName {
+		return Default.HostName(nil, vObj.Name, vObj.Namespace)
+	}
+
+	// nothing is recorded without a sink
+	HostMetadataWithContext(nil, vObjs[0], nameFunc(vObjs[0]))
+
+	sink := &collectingAuditSink{}
+	Audit = sink
+	HostMetadataWithContext(nil, vObjs[0], nameFunc(vObjs[0]))
+	TranslateListToHost(nil, vObjs, nameFunc)
+
+	assert.Equal(t, len(sink.entries), 3)
+	for i, vObj := range []*corev1.ConfigMap{vObjs[0], vObjs[0], vObjs[1]} {
+		assert.Equal(t, sink.entries[i].VirtualGVK, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		assert.Equal(t, sink.entries[i].Virtual, types.NamespacedName{Name: vObj.Name, Namespace: vObj.Namespace})
+		assert.Equal(t, sink.entries[i].Host, nameFunc(vObj))
+		assert.Assert(t, !sink.entries[i].Timestamp.IsZero())
+	}
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for range 2 {
+		sink, err := NewFileAuditSink(path)
+		assert.NilError(t, err)
+		sink.Record(AuditEntry{Virtual: types.NamespacedName{Name: "a", Namespace: "default"}, Host: types.NamespacedName{Name: "a-x-default-x-suffix", Namespace: "test"}})
+	}
+
+	// entries are appended
+	file, err := os.Open(path)
+	assert.NilError(t, err)
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := AuditEntry{}
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		assert.Equal(t, entry.Host.Name, "a-x-default-x-suffix")
+		lines++
+	}
+	assert.Equal(t, lines, 2)
+}
@@ -0,0 +1,91 @@
+package translate
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DescribeTranslation translates the given virtual object with the translator and returns a human-readable report
+// about its host name, labels, annotations and owner references. This is meant for debugging a single object, so
+// the translation is not recorded in the Audit sink.
+func DescribeTranslation(ctx *synccontext.SyncContext, translator Translator, vObj client.Object) (string, error) {
+	if vObj.GetName() == "" {
+		return "", fmt.Errorf("object has no name")
+	}
+
+	hostName := types.NamespacedName{Name: translator.HostNameCluster(vObj.GetName())}
+	if vObj.GetNamespace() != "" {
+		hostName = translator.HostName(ctx, vObj.GetName(), vObj.GetNamespace())
+	}
+	if hostName.Name == "" {
+		return "", fmt.Errorf("translator returned an empty host name for %s", client.ObjectKeyFromObject(vObj).String())
+	}
+
+	vObj = vObj.DeepCopyObject().(client.Object)
+	pObj := hostMetadataWithContext(ctx, vObj, hostName)
+
+	report := &strings.Builder{}
+	fmt.Fprintf(report, "Kind: %s\n", kindAnnotationValue(vObj))
+	fmt.Fprintf(report, "Virtual: %s\n", client.ObjectKeyFromObject(vObj).String())
+	fmt.Fprintf(report, "Host name: %s\n", hostName.Name)
+	if hostName.Namespace != "" {
+		fmt.Fprintf(report, "Host namespace: %s\n", hostName.Namespace)
+	} else {
+		fmt.Fprintf(report, "Host namespace: <cluster scoped>\n")
+	}
+
+	// labels
+	report.WriteString("Labels:\n")
+	vLabels, pLabels := vObj.GetLabels(), pObj.GetLabels()
+	hostKeys := map[string]bool{}
+	for _, key := range slices.Sorted(maps.Keys(vLabels)) {
+		hostKey := translator.HostLabel(key)
+		if _, ok := pLabels[hostKey]; !ok {
+			fmt.Fprintf(report, "  %s (dropped)\n", key)
+			continue
+		}
+
+		hostKeys[hostKey] = true
+		if hostKey == key {
+			fmt.Fprintf(report, "  %s (pass-through)\n", key)
+		} else {
+			fmt.Fprintf(report, "  %s -> %s (hashed)\n", key, hostKey)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(pLabels)) {
+		if !hostKeys[key] {
+			fmt.Fprintf(report, "  + %s=%s\n", key, pLabels[key])
+		}
+	}
+
+	// annotations
+	report.WriteString("Annotations:\n")
+	vAnnotations, pAnnotations := vObj.GetAnnotations(), pObj.GetAnnotations()
+	for _, key := range slices.Sorted(maps.Keys(pAnnotations)) {
+		if vValue, ok := vAnnotations[key]; !ok || vValue != pAnnotations[key] {
+			fmt.Fprintf(report, "  + %s=%s\n", key, pAnnotations[key])
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(vAnnotations)) {
+		if _, ok := pAnnotations[key]; !ok {
+			fmt.Fprintf(report, "  - %s\n", key)
+		}
+	}
+
+	// owner references
+	ownerReferences := pObj.GetOwnerReferences()
+	if len(ownerReferences) == 0 {
+		report.WriteString("Owner: <none>\n")
+	}
+	for _, ownerReference := range ownerReferences {
+		fmt.Fprintf(report, "Owner: %s %s (%s)\n", ownerReference.Kind, ownerReference.Name, ownerReference.UID)
+	}
+
+	return report.String(), nil
+}
@@ -0,0 +1,72 @@
+package translate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDescribeTranslation(t *testing.T) {
+	oldDefault := Default
+	Default = NewSingleNamespaceTranslator("host-namespace")
+	defer func() { Default = oldDefault }()
+
+	vObj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-config",
+			Namespace:   "my-namespace",
+			Labels:      map[string]string{"app": "web", VClusterReleaseLabel: "my-release"},
+			Annotations: map[string]string{"note": "hello"},
+		},
+	}
+
+	report, err := DescribeTranslation(&synccontext.SyncContext{Context: context.TODO()}, Default, vObj)
+	assert.NilError(t, err)
+
+	hostName := Default.HostName(nil, vObj.Name, vObj.Namespace)
+	assert.Assert(t, strings.Contains(report, "Host name: "+hostName.Name+"\n"), report)
+	assert.Assert(t, strings.Contains(report, "Host namespace: host-namespace\n"), report)
+	assert.Assert(t, strings.Contains(report, "  app (pass-through)\n"), report)
+	assert.Assert(t, strings.Contains(report, "  "+VClusterReleaseLabel+" -> "+HostLabel(VClusterReleaseLabel)+" (hashed)\n"), report)
+	assert.Assert(t, strings.Contains(report, "  + "+MarkerLabel+"="+VClusterName+"\n"), report)
+	assert.Assert(t, strings.Contains(report, "  + "+NameAnnotation+"=my-config\n"), report)
+	assert.Assert(t, !strings.Contains(report, "  - note\n"), report)
+
+	// the virtual object is not modified
+	assert.DeepEqual(t, vObj.Annotations, map[string]string{"note": "hello"})
+
+	_, err = DescribeTranslation(&synccontext.SyncContext{Context: context.TODO()}, Default, &corev1.ConfigMap{})
+	assert.ErrorContains(t, err, "no name")
+}
+
+func TestDescribeTranslationLabels(t *testing.T) {
+	oldDefault := Default
+	Default = NewSingleNamespaceTranslator("host-namespace")
+	SyncLabelKeys = []string{"app", VClusterReleaseLabel}
+	sink := &collectingAuditSink{}
+	Audit = sink
+	defer func() { Default, SyncLabelKeys, Audit = oldDefault, nil, nil }()
+
+	vObj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-config",
+			Namespace: "my-namespace",
+			Labels:    map[string]string{"app": "web", "secret": "not-allowlisted", VClusterReleaseLabel: ""},
+		},
+	}
+
+	report, err := DescribeTranslation(&synccontext.SyncContext{Context: context.TODO()}, Default, vObj)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(report, "  app (pass-through)\n"), report)
+	assert.Assert(t, strings.Contains(report, "  secret (dropped)\n"), report)
+	assert.Assert(t, strings.Contains(report, "  "+VClusterReleaseLabel+" -> "+HostLabel(VClusterReleaseLabel)+" (hashed)\n"), report)
+	assert.Assert(t, !strings.Contains(report, "  + "+HostLabel(VClusterReleaseLabel)), report)
+
+	// describing an object is not a translation that is audited
+	assert.Equal(t, len(sink.entries), 0)
+}
@@ -2,6 +2,7 @@ package translate
 
 import (
 	"maps"
+	"slices"
 	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
@@ -11,6 +12,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// IsTranslatedLabel returns the virtual label key if label is the host label key of a label that is always
+// translated. It uses the package level settings and Default, see TranslatorConfig.IsTranslatedLabel for a
+// translator rebuilt from a snapshot.
 func IsTranslatedLabel(label string) (string, bool) {
 	for k := range Default.LabelsToTranslate() {
 		if convertLabelKeyWithPrefix(LabelPrefix, k) == label {
@@ -20,14 +24,48 @@ func IsTranslatedLabel(label string) (string, bool) {
 	return "", false
 }
 
-func HostLabel(vLabel string) string {
-	if Default.LabelsToTranslate()[vLabel] {
-		return convertLabelKeyWithPrefix(LabelPrefix, vLabel)
+// LabelCollision is a host label key that multiple virtual label keys are translated to
+type LabelCollision struct {
+	HostLabel     string
+	VirtualLabels []string
+}
+
+// CheckLabelCollisions returns the host label keys that more than one of the given virtual label keys are translated
+// to by hostLabel, e.g. HostLabelNamespace. Translated label keys only contain the first 40 bits of the sha256 of the
+// virtual key, so collisions are unlikely but possible for large numbers of keys. Colliding virtual labels would
+// silently be merged into a single host label.
+func CheckLabelCollisions(vLabelKeys []string, hostLabel func(vLabel string) string) []LabelCollision {
+	virtualLabels := map[string][]string{}
+	for _, vLabel := range vLabelKeys {
+		pLabel := hostLabel(vLabel)
+		if !slices.Contains(virtualLabels[pLabel], vLabel) {
+			virtualLabels[pLabel] = append(virtualLabels[pLabel], vLabel)
+		}
+	}
+
+	collisions := []LabelCollision{}
+	for pLabel, vLabels := range virtualLabels {
+		if len(vLabels) < 2 {
+			continue
+		}
+
+		slices.Sort(vLabels)
+		collisions = append(collisions, LabelCollision{HostLabel: pLabel, VirtualLabels: vLabels})
 	}
+	slices.SortFunc(collisions, func(a, b LabelCollision) int {
+		return strings.Compare(a.HostLabel, b.HostLabel)
+	})
 
-	return vLabel
+	return collisions
 }
 
+func HostLabel(vLabel string) string {
+	return Default.HostLabel(vLabel)
+}
+
+// VirtualLabel returns the virtual label key of the host label key pLabel and false if the label is not synced
+// back. It uses the package level settings and Default, see TranslatorConfig.VirtualLabel for a translator
+// rebuilt from a snapshot.
 func VirtualLabel(pLabel string) (string, bool) {
 	if Default.LabelsToTranslate()[pLabel] {
 		return "", false
@@ -40,29 +78,102 @@ func VirtualLabel(pLabel string) (string, bool) {
 	return pLabel, true
 }
 
+// HostLabelsMap translates the virtual labels to host labels. The ControllerLabel is never taken
+// from the virtual labels, but preserved from the existing host labels, see ControllerLabelKey.
+// It uses the package level settings and Default, also for translators rebuilt from a snapshot.
 func HostLabelsMap(vLabels, pLabels map[string]string, vNamespace string, isMetadata bool) map[string]string {
+	return HostLabelsMapWithOptions(vLabels, pLabels, vNamespace, isMetadata, LabelsOptions{})
+}
+
+// LabelsOptions configure the translation of labels between the virtual and host objects
+type LabelsOptions struct {
+	// ExcludedPrefixes are prefixes of virtual label keys, e.g. kubectl.kubernetes.io/, that are never synced
+	// to the host. Labels with these prefixes are kept on the virtual object.
+	ExcludedPrefixes []string
+}
+
+// isExcluded returns true if the virtual label key has one of the excluded prefixes and is not synced between
+// the virtual and host object
+func (o LabelsOptions) isExcluded(key string) bool {
+	for _, prefix := range o.ExcludedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAllowlisted returns true if the virtual label key is part of the SyncLabelKeys allowlist or there is no
+// allowlist. The allowlist only applies to the labels of objects, never to selectors, as dropping a selector
+// key would widen the selector on the host.
+func isAllowlisted(key string) bool {
+	return len(SyncLabelKeys) == 0 || slices.Contains(SyncLabelKeys, key)
+}
+
+// HostLabelsMapWithOptions is like HostLabelsMap, but skips the labels excluded by the options
+func HostLabelsMapWithOptions(vLabels, pLabels map[string]string, vNamespace string, isMetadata bool, opts LabelsOptions) map[string]string {
+	return hostLabelsMap(vLabels, pLabels, vNamespace, vNamespace == "", isMetadata, opts)
+}
+
+func hostLabelsMap(vLabels, pLabels map[string]string, vNamespace string, clusterScoped, isMetadata bool, opts LabelsOptions) map[string]string {
 	if vLabels == nil {
 		return nil
 	}
 
+	// check if we should add namespace and marker label
+	var topology map[string]string
+	if isMetadata || pLabels == nil || pLabels[MarkerLabel] != "" {
+		topology = topologyLabels(vNamespace, clusterScoped)
+	}
+
+	return hostLabelsMapWithTopology(vLabels, pLabels, topology, isMetadata, opts)
+}
+
+// topologyLabels returns the marker and namespace labels of host objects in the given virtual namespace. They only
+// depend on the namespace, so they can be shared by all objects of a namespace, see TranslateListToHost. If
+// UseAnnotationsForTopology is set, namespaced objects only get the ScopeLabel and cluster scoped objects no label.
+func topologyLabels(vNamespace string, clusterScoped bool) map[string]string {
+	if UseAnnotationsForTopology {
+		if clusterScoped {
+			return nil
+		}
+
+		return map[string]string{ScopeLabel: ScopeLabelValue(vNamespace)}
+	} else if clusterScoped {
+		return map[string]string{MarkerLabel: Default.MarkerLabelCluster()}
+	} else if vNamespace == "" {
+		return map[string]string{MarkerLabel: VClusterName}
+	}
+
+	return map[string]string{
+		MarkerLabel:    VClusterName,
+		NamespaceLabel: vNamespace,
+	}
+}
+
+// NamespaceSelectorLabels returns the labels a host selector needs to match, so that it only selects host objects of
+// the given virtual namespace and this vCluster, e.g. the pods of a synced workload.
+func NamespaceSelectorLabels(vNamespace string) map[string]string {
+	return topologyLabels(vNamespace, false)
+}
+
+// ScopeLabelValue returns the value of the ScopeLabel of host objects in the given virtual namespace. It is a hash of
+// the vCluster name and virtual namespace, so the namespace name is not exposed in the labels of host objects.
+func ScopeLabelValue(vNamespace string) string {
+	return shortHash(VClusterName+"/"+vNamespace, scopeLabelHashLength)
+}
+
+func hostLabelsMapWithTopology(vLabels, pLabels, topology map[string]string, isMetadata bool, opts LabelsOptions) map[string]string {
 	newLabels := map[string]string{}
 	for k, v := range vLabels {
-		if _, ok := IsTranslatedLabel(k); ok {
+		if _, ok := IsTranslatedLabel(k); ok || opts.isExcluded(k) || (isMetadata && !isAllowlisted(k)) {
 			continue
 		}
 
 		newLabels[HostLabel(k)] = v
 	}
-
-	// check if we should add namespace and marker label
-	if isMetadata || pLabels == nil || pLabels[MarkerLabel] != "" {
-		if vNamespace == "" {
-			newLabels[MarkerLabel] = Default.MarkerLabelCluster()
-		} else {
-			newLabels[MarkerLabel] = VClusterName
-			newLabels[NamespaceLabel] = vNamespace
-		}
-	}
+	maps.Copy(newLabels, topology)
 
 	// set controller label
 	if pLabels[ControllerLabel] != "" {
@@ -72,17 +183,57 @@ func HostLabelsMap(vLabels, pLabels map[string]string, vNamespace string, isMeta
 	return newLabels
 }
 
+// ControllerLabelKey returns the label key that marks a host object as controlled by another object.
+// The label is set on the host object directly and HostLabelsMap preserves it across updates.
+func ControllerLabelKey() string {
+	return ControllerLabel
+}
+
+// BuildControllerSelector returns a label selector that matches host objects whose controller label
+// is set to the given value.
+func BuildControllerSelector(value string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			ControllerLabelKey(): value,
+		},
+	}
+}
+
+// VirtualLabelsMap translates the host labels back to virtual labels. As it is also used for selectors, the
+// SyncLabelKeys allowlist is not applied, see VirtualLabels for the labels of objects.
 func VirtualLabelsMap(pLabels, vLabels map[string]string, excluded ...string) map[string]string {
+	return VirtualLabelsMapWithOptions(pLabels, vLabels, LabelsOptions{}, excluded...)
+}
+
+// VirtualLabelsMapWithOptions is like VirtualLabelsMap, but keeps the virtual labels excluded by the options
+func VirtualLabelsMapWithOptions(pLabels, vLabels map[string]string, opts LabelsOptions, excluded ...string) map[string]string {
+	retLabels, _ := VirtualLabelsMapWithDroppedKeys(pLabels, vLabels, opts, excluded...)
+	return retLabels
+}
+
+// VirtualLabelsMapWithDroppedKeys is like VirtualLabelsMapWithOptions, but also returns the sorted keys of the host
+// labels that can't be translated back, e.g. a release label that was added directly on the host object. These labels
+// are not synced to the virtual object, because HostLabelsMap would write them back to the host object under their
+// translated key.
+func VirtualLabelsMapWithDroppedKeys(pLabels, vLabels map[string]string, opts LabelsOptions, excluded ...string) (map[string]string, []string) {
+	return virtualLabelsMap(pLabels, vLabels, false, opts, excluded...)
+}
+
+// virtualLabelsMap translates the host labels back to virtual labels. If isMetadata is set, virtual labels that are not
+// part of the SyncLabelKeys allowlist are kept as they are, see isAllowlisted. As host keys might be translated, the
+// allowlist is always checked against the virtual key.
+func virtualLabelsMap(pLabels, vLabels map[string]string, isMetadata bool, opts LabelsOptions, excluded ...string) (map[string]string, []string) {
 	if pLabels == nil {
-		return nil
+		return nil, nil
 	}
 
-	excluded = append(excluded, MarkerLabel, NamespaceLabel, ControllerLabel)
+	excluded = append(excluded, MarkerLabel, NamespaceLabel, ScopeLabel, ControllerLabel)
 	retLabels := copyMaps(pLabels, vLabels, func(key string) bool {
-		return exists(excluded, key) || strings.HasPrefix(key, NamespaceLabelPrefix)
+		return exists(excluded, key) || strings.HasPrefix(key, NamespaceLabelPrefix) || opts.isExcluded(key) || (isMetadata && !isAllowlisted(virtualLabelKey(key)))
 	})
 
 	// try to translate back
+	var droppedKeys []string
 	for key, value := range retLabels {
 		// if the original key was on vLabels we want to preserve it
 		vValue, ok := vLabels[key]
@@ -93,15 +244,30 @@ func VirtualLabelsMap(pLabels, vLabels map[string]string, excluded ...string) ma
 		}
 
 		// if the virtual label can be converted we will add it back
-		vKey, ok := VirtualLabel(key)
-		if ok {
+		vKey, translated := VirtualLabel(key)
+		if translated {
 			retLabels[vKey] = value
+		} else if !ok {
+			droppedKeys = append(droppedKeys, key)
 		}
 	}
 
-	return retLabels
+	slices.Sort(droppedKeys)
+	return retLabels, droppedKeys
+}
+
+// virtualLabelKey returns the virtual key of the given label key. Keys that can't be translated back, e.g. virtual
+// keys of labels that are translated on the host, are returned as they are.
+func virtualLabelKey(key string) string {
+	if vKey, ok := VirtualLabel(key); ok {
+		return vKey
+	}
+
+	return key
 }
 
+// VirtualLabelSelector translates the keys of a host label selector back to virtual label keys. Keys of labels that
+// are only set on host objects, such as the MarkerLabel, can't be translated back and are kept as they are.
 func VirtualLabelSelector(labelSelector *metav1.LabelSelector) *metav1.LabelSelector {
 	return virtualLabelSelector(labelSelector, func(key string) (string, bool) {
 		return VirtualLabel(key)
@@ -133,16 +299,31 @@ func virtualLabelSelector(labelSelector *metav1.LabelSelector, labelFunc vLabelF
 			pLabel = r.Key
 		}
 
-		newLabelSelector.MatchExpressions = append(newLabelSelector.MatchExpressions, metav1.LabelSelectorRequirement{
-			Key:      pLabel,
-			Operator: r.Operator,
-			Values:   r.Values,
-		})
+		newLabelSelector.MatchExpressions = append(newLabelSelector.MatchExpressions, translateLabelSelectorRequirement(r, pLabel))
 	}
 
 	return newLabelSelector
 }
 
+// translateLabelSelectorRequirement returns the requirement with the translated key. The values are copied, so the
+// translated selector doesn't share them with the original one. Exists and DoesNotExist requirements only check
+// the key, so their values are always dropped, as the api server would reject them.
+func translateLabelSelectorRequirement(requirement metav1.LabelSelectorRequirement, key string) metav1.LabelSelectorRequirement {
+	var values []string
+	if requirement.Operator != metav1.LabelSelectorOpExists && requirement.Operator != metav1.LabelSelectorOpDoesNotExist {
+		values = slices.Clone(requirement.Values)
+	}
+
+	return metav1.LabelSelectorRequirement{
+		Key:      key,
+		Operator: requirement.Operator,
+		Values:   values,
+	}
+}
+
+// HostLabelSelector translates the keys of a virtual label selector to the keys HostLabelsMap uses on host objects.
+// Requirements on internal labels such as the MarkerLabel are translated like the labels of virtual objects, so they
+// select on the virtual labels and not on the labels vCluster sets on host objects.
 func HostLabelSelector(labelSelector *metav1.LabelSelector) *metav1.LabelSelector {
 	return hostLabelSelector(labelSelector, func(key string) string {
 		return HostLabel(key)
@@ -164,17 +345,18 @@ func hostLabelSelector(labelSelector *metav1.LabelSelector, labelFunc labelFunc)
 		}
 	}
 	for _, r := range labelSelector.MatchExpressions {
-		newLabelSelector.MatchExpressions = append(newLabelSelector.MatchExpressions, metav1.LabelSelectorRequirement{
-			Key:      labelFunc(r.Key),
-			Operator: r.Operator,
-			Values:   r.Values,
-		})
+		newLabelSelector.MatchExpressions = append(newLabelSelector.MatchExpressions, translateLabelSelectorRequirement(r, labelFunc(r.Key)))
 	}
 
 	return newLabelSelector
 }
 
 func VirtualLabels(pObj, vObj client.Object) map[string]string {
+	return VirtualLabelsWithOptions(pObj, vObj, LabelsOptions{})
+}
+
+// VirtualLabelsWithOptions is like VirtualLabels, but keeps the virtual labels excluded by the options
+func VirtualLabelsWithOptions(pObj, vObj client.Object, opts LabelsOptions) map[string]string {
 	pLabels := pObj.GetLabels()
 	if pLabels == nil {
 		pLabels = map[string]string{}
@@ -183,7 +365,7 @@ func VirtualLabels(pObj, vObj client.Object) map[string]string {
 	if vObj != nil {
 		vLabels = vObj.GetLabels()
 	}
-	retLabels := VirtualLabelsMap(pLabels, vLabels)
+	retLabels, _ := virtualLabelsMap(pLabels, vLabels, true, opts)
 	if len(retLabels) == 0 {
 		return nil
 	}
@@ -191,23 +373,43 @@ func VirtualLabels(pObj, vObj client.Object) map[string]string {
 }
 
 func HostLabels(vObj, pObj client.Object) map[string]string {
-	vLabels := vObj.GetLabels()
-	if vLabels == nil {
-		vLabels = map[string]string{}
-	}
+	return HostLabelsWithOptions(vObj, pObj, LabelsOptions{})
+}
+
+// HostLabelsWithOptions is like HostLabels, but skips the labels excluded by the options
+func HostLabelsWithOptions(vObj, pObj client.Object, opts LabelsOptions) map[string]string {
+	return hostLabels(vObj, pObj, vObj.GetNamespace() == "", opts)
+}
+
+// HostLabelsWithContext is like HostLabels, but decides with IsClusterScoped whether the virtual object is
+// cluster scoped, so namespaced objects that have no namespace yet get the namespaced marker label
+func HostLabelsWithContext(ctx *synccontext.SyncContext, vObj, pObj client.Object) map[string]string {
+	return hostLabels(vObj, pObj, IsClusterScoped(ctx, vObj), LabelsOptions{})
+}
+
+func hostLabels(vObj, pObj client.Object, clusterScoped bool, opts LabelsOptions) map[string]string {
 	var pLabels map[string]string
 	if pObj != nil {
 		pLabels = pObj.GetLabels()
 	}
-	retLabels := HostLabelsMap(vLabels, pLabels, vObj.GetNamespace(), true)
+
+	return hostLabelsWithTopology(vObj, pLabels, topologyLabels(vObj.GetNamespace(), clusterScoped), opts)
+}
+
+// hostLabelsWithTopology returns the host labels of the virtual object with the given topology labels, see topologyLabels
+func hostLabelsWithTopology(vObj client.Object, pLabels, topology map[string]string, opts LabelsOptions) map[string]string {
+	retLabels := hostLabelsMapWithTopology(vObj.GetLabels(), pLabels, topology, true, opts)
 	if len(retLabels) == 0 {
 		return nil
 	}
 	return retLabels
 }
 
+// MergeLabelSelectors merges the given label selectors. Match labels of later selectors override earlier ones and
+// identical match expressions are only added once.
 func MergeLabelSelectors(elems ...*metav1.LabelSelector) *metav1.LabelSelector {
 	out := &metav1.LabelSelector{}
+	seenExpressions := map[string]bool{}
 	for _, selector := range elems {
 		if selector == nil {
 			continue
@@ -220,13 +422,57 @@ func MergeLabelSelectors(elems ...*metav1.LabelSelector) *metav1.LabelSelector {
 				out.MatchLabels[k] = v
 			}
 		}
-		out.MatchExpressions = append(out.MatchExpressions, selector.MatchExpressions...)
+		for _, expression := range selector.MatchExpressions {
+			id := labelSelectorRequirementID(expression)
+			if seenExpressions[id] {
+				continue
+			}
+
+			seenExpressions[id] = true
+			out.MatchExpressions = append(out.MatchExpressions, expression)
+		}
+	}
+	return out
+}
+
+// labelSelectorRequirementID identifies a requirement by its key, operator and values, regardless of the order of the values
+func labelSelectorRequirementID(requirement metav1.LabelSelectorRequirement) string {
+	values := slices.Clone(requirement.Values)
+	slices.Sort(values)
+	return requirement.Key + "\x00" + string(requirement.Operator) + "\x00" + strings.Join(values, "\x00")
+}
+
+// MergeLabels merges the given label maps, later maps override the values of earlier ones.
+// It returns nil if all maps are empty.
+func MergeLabels(elems ...map[string]string) map[string]string {
+	return mergeStringMaps(elems)
+}
+
+// MergeAnnotations merges the given annotation maps, later maps override the values of earlier ones.
+// It returns nil if all maps are empty.
+func MergeAnnotations(elems ...map[string]string) map[string]string {
+	return mergeStringMaps(elems)
+}
+
+func mergeStringMaps(elems []map[string]string) map[string]string {
+	var out map[string]string
+	for _, elem := range elems {
+		if len(elem) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(elem))
+		}
+		for k, v := range elem {
+			out[k] = v
+		}
 	}
 	return out
 }
 
 func AnnotationsBidirectionalUpdateFunction[T client.Object](event *synccontext.SyncEvent[T], transformFromHost, transformToHost func(key string, value interface{}) (string, interface{})) (map[string]string, map[string]string) {
-	excludeAnnotations := []string{HostNameAnnotation, HostNamespaceAnnotation, NameAnnotation, UIDAnnotation, KindAnnotation, NamespaceAnnotation, ManagedAnnotationsAnnotation, ManagedLabelsAnnotation}
+	excludeAnnotations := []string{HostNameAnnotation, HostNamespaceAnnotation, NameAnnotation, UIDAnnotation, KindAnnotation, NamespaceAnnotation, MarkerAnnotation, TranslateVersionAnnotation, ManagedAnnotationsAnnotation, ManagedLabelsAnnotation}
+	excludeAnnotations = append(excludeAnnotations, PreserveHostAnnotationKeys...)
 	newVirtual := maps.Clone(event.Virtual.GetAnnotations())
 	newHost := maps.Clone(event.Host.GetAnnotations())
 	if newHost == nil {
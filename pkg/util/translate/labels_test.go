@@ -1,13 +1,21 @@
 package translate
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAnnotationsSync(t *testing.T) {
@@ -47,10 +55,11 @@ func TestAnnotationsSync(t *testing.T) {
 	})
 	assert.DeepEqual(t, pAnnotations, map[string]string{
 		"storageclass.kubernetes.io/is-default-class": "my-other",
-		NameAnnotation:     "",
-		UIDAnnotation:      "",
-		KindAnnotation:     storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
-		HostNameAnnotation: "",
+		NameAnnotation:             "",
+		UIDAnnotation:              "",
+		KindAnnotation:             storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
+		TranslateVersionAnnotation: "1",
+		HostNameAnnotation:         "",
 	})
 
 	// not exclude the default class
@@ -89,10 +98,11 @@ func TestAnnotationsSync(t *testing.T) {
 	})
 	assert.DeepEqual(t, pAnnotations, map[string]string{
 		"storageclass.kubernetes.io/is-default-class": "my-other",
-		NameAnnotation:     "",
-		UIDAnnotation:      "",
-		KindAnnotation:     storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
-		HostNameAnnotation: "",
+		NameAnnotation:             "",
+		UIDAnnotation:              "",
+		KindAnnotation:             storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
+		TranslateVersionAnnotation: "1",
+		HostNameAnnotation:         "",
 	})
 
 	// check on creation with exclude host -> virtual
@@ -104,7 +114,7 @@ func TestAnnotationsSync(t *testing.T) {
 			},
 		},
 	}
-	vObj := CopyObjectWithName(pObj, types.NamespacedName{}, true, "storageclass.kubernetes.io/is-default-class")
+	vObj := CopyObjectWithContext(nil, pObj, types.NamespacedName{}, true, "storageclass.kubernetes.io/is-default-class")
 	vAnnotations = VirtualAnnotations(pObj, vObj, "storageclass.kubernetes.io/is-default-class")
 	assert.DeepEqual(t, vAnnotations, map[string]string{
 		"not-excluded": "true",
@@ -119,7 +129,7 @@ func TestAnnotationsSync(t *testing.T) {
 			},
 		},
 	}
-	pObj = CopyObjectWithName(vObj, types.NamespacedName{}, true, "storageclass.kubernetes.io/is-default-class")
+	pObj = CopyObjectWithContext(nil, vObj, types.NamespacedName{}, true, "storageclass.kubernetes.io/is-default-class")
 	pAnnotations = HostAnnotations(vObj, pObj, "storageclass.kubernetes.io/is-default-class")
 	assert.DeepEqual(t, pAnnotations, map[string]string{
 		"not-excluded":               "true",
@@ -127,6 +137,328 @@ func TestAnnotationsSync(t *testing.T) {
 		NameAnnotation:               "",
 		UIDAnnotation:                "",
 		KindAnnotation:               storagev1.SchemeGroupVersion.WithKind("StorageClass").String(),
+		TranslateVersionAnnotation:   "1",
 		HostNameAnnotation:           "",
 	})
 }
+
+func TestControllerLabel(t *testing.T) {
+	assert.Equal(t, ControllerLabelKey(), ControllerLabel)
+
+	pLabels := map[string]string{
+		ControllerLabelKey(): "my-controller",
+		"app":                "test",
+	}
+	newLabels := HostLabelsMap(map[string]string{"app": "test"}, pLabels, "test", true)
+	assert.Equal(t, newLabels[ControllerLabelKey()], "my-controller")
+
+	// the controller label can't be set from the virtual object
+	newLabels = HostLabelsMap(map[string]string{ControllerLabelKey(): "other"}, nil, "test", true)
+	assert.Equal(t, newLabels[ControllerLabelKey()], "")
+
+	selector, err := metav1.LabelSelectorAsSelector(BuildControllerSelector("my-controller"))
+	assert.NilError(t, err)
+	assert.Assert(t, selector.Matches(labels.Set(pLabels)))
+	assert.Assert(t, !selector.Matches(labels.Set(map[string]string{ControllerLabelKey(): "other"})))
+	assert.Assert(t, !selector.Matches(labels.Set(map[string]string{"app": "test"})))
+}
+
+func TestUseAnnotationsForTopology(t *testing.T) {
+	defer func(translator Translator, useAnnotations bool) {
+		Default = translator
+		UseAnnotationsForTopology = useAnnotations
+	}(Default, UseAnnotationsForTopology)
+	Default = NewSingleNamespaceTranslator("host-namespace")
+	syncCtx := &synccontext.SyncContext{}
+
+	vObj := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-secret",
+			Namespace: "default",
+			UID:       "1234",
+			Labels: map[string]string{
+				"app": "test",
+			},
+		},
+	}
+	vNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-namespace",
+			UID:  "1234",
+		},
+	}
+
+	// labels mode
+	UseAnnotationsForTopology = false
+	pObj := HostMetadataWithContext(syncCtx, vObj, Default.HostName(syncCtx, vObj.Name, vObj.Namespace))
+	assert.Equal(t, pObj.Labels[MarkerLabel], VClusterName)
+	assert.Equal(t, pObj.Labels[NamespaceLabel], "default")
+	assert.Equal(t, pObj.Annotations[MarkerAnnotation], "")
+	assert.Assert(t, Default.IsManaged(syncCtx, pObj))
+	pClusterObj := HostMetadataWithContext(syncCtx, vNamespace, types.NamespacedName{Name: Default.HostNameCluster(vNamespace.Name)})
+	assert.Equal(t, pClusterObj.Labels[MarkerLabel], Default.MarkerLabelCluster())
+	assert.Assert(t, Default.IsManaged(syncCtx, pClusterObj))
+
+	// annotations mode
+	UseAnnotationsForTopology = true
+	pObj = HostMetadataWithContext(syncCtx, vObj, Default.HostName(syncCtx, vObj.Name, vObj.Namespace))
+	assert.DeepEqual(t, pObj.Labels, map[string]string{"app": "test", ScopeLabel: ScopeLabelValue("default")})
+	assert.Equal(t, pObj.Annotations[MarkerAnnotation], VClusterName)
+	assert.Equal(t, pObj.Annotations[NamespaceAnnotation], "default")
+	assert.Assert(t, Default.IsManaged(syncCtx, pObj))
+	pClusterObj = HostMetadataWithContext(syncCtx, vNamespace, types.NamespacedName{Name: Default.HostNameCluster(vNamespace.Name)})
+	assert.Equal(t, pClusterObj.Labels[MarkerLabel], "")
+	assert.Equal(t, pClusterObj.Annotations[MarkerAnnotation], Default.MarkerLabelCluster())
+	assert.Assert(t, Default.IsManaged(syncCtx, pClusterObj))
+
+	// marker annotation of another vCluster
+	pObj.Annotations[MarkerAnnotation] = "other"
+	assert.Assert(t, !Default.IsManaged(syncCtx, pObj))
+
+	// objects synced in labels mode are still recognized
+	UseAnnotationsForTopology = false
+	pObj = HostMetadataWithContext(syncCtx, vObj, Default.HostName(syncCtx, vObj.Name, vObj.Namespace))
+	UseAnnotationsForTopology = true
+	assert.Assert(t, Default.IsManaged(syncCtx, pObj))
+
+	// the marker annotation is never synced back
+	vAnnotations := VirtualAnnotations(pObj, vObj)
+	_, ok := vAnnotations[MarkerAnnotation]
+	assert.Assert(t, !ok)
+}
+
+func TestCustomLabelPrefix(t *testing.T) {
+	defaultHostLabel := HostLabel(VClusterReleaseLabel)
+
+	oldPrefix := LabelPrefix
+	LabelPrefix = "other-product.example.com/label"
+	defer func() { LabelPrefix = oldPrefix }()
+
+	hostLabel := HostLabel(VClusterReleaseLabel)
+	assert.Assert(t, strings.HasPrefix(hostLabel, LabelPrefix), hostLabel)
+	assert.Assert(t, hostLabel != defaultHostLabel)
+
+	// labels translated with the custom prefix round-trip
+	vLabel, ok := VirtualLabel(hostLabel)
+	assert.Assert(t, ok)
+	assert.Equal(t, vLabel, VClusterReleaseLabel)
+	assert.DeepEqual(t, VirtualLabelsMap(map[string]string{hostLabel: "my-release"}, nil), map[string]string{VClusterReleaseLabel: "my-release"})
+
+	// labels translated with the default prefix are not picked up
+	vLabel, ok = VirtualLabel(defaultHostLabel)
+	assert.Assert(t, ok)
+	assert.Equal(t, vLabel, defaultHostLabel)
+}
+
+func TestCheckLabelCollisions(t *testing.T) {
+	// these keys share the first 10 hex characters of their sha256
+	collidingA, collidingB := "example.com/key-281371", "example.com/key-1395424"
+	assert.Equal(t, HostLabelNamespace(collidingA), HostLabelNamespace(collidingB))
+
+	collisions := CheckLabelCollisions([]string{"app", collidingB, "team", collidingA, "app"}, HostLabelNamespace)
+	assert.DeepEqual(t, collisions, []LabelCollision{
+		{HostLabel: HostLabelNamespace(collidingA), VirtualLabels: []string{collidingB, collidingA}},
+	})
+
+	assert.DeepEqual(t, CheckLabelCollisions([]string{"app", "team"}, HostLabelNamespace), []LabelCollision{})
+}
+
+func TestMergeLabels(t *testing.T) {
+	// nil and empty maps are skipped
+	assert.Assert(t, MergeLabels() == nil)
+	assert.Assert(t, MergeLabels(nil, map[string]string{}) == nil)
+	assert.Assert(t, MergeAnnotations(nil) == nil)
+
+	// later maps override earlier ones
+	base := map[string]string{"app": "nginx", "team": "a"}
+	merged := MergeLabels(base, nil, map[string]string{"team": "b", "tier": "web"})
+	assert.DeepEqual(t, merged, map[string]string{"app": "nginx", "team": "b", "tier": "web"})
+	assert.DeepEqual(t, MergeAnnotations(map[string]string{"a": "1"}, map[string]string{"a": "2"}), map[string]string{"a": "2"})
+
+	// the inputs are not modified
+	assert.DeepEqual(t, base, map[string]string{"app": "nginx", "team": "a"})
+	merged["app"] = "other"
+	assert.Equal(t, base["app"], "nginx")
+}
+
+func TestMergeLabelSelectors(t *testing.T) {
+	merged := MergeLabelSelectors(
+		&metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "nginx", "team": "a"},
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "api"}},
+			},
+		},
+		nil,
+		&metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "b"},
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"api", "web"}},
+				{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"web", "api"}},
+				{Key: "env", Operator: metav1.LabelSelectorOpExists},
+			},
+		},
+	)
+
+	assert.DeepEqual(t, merged, &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "nginx", "team": "b"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "api"}},
+			{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"web", "api"}},
+			{Key: "env", Operator: metav1.LabelSelectorOpExists},
+		},
+	})
+}
+
+func TestLabelsExcludedPrefixes(t *testing.T) {
+	opts := LabelsOptions{ExcludedPrefixes: []string{"kubectl.kubernetes.io/", "tools.example.com/"}}
+	vObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{
+		"app":                                "nginx",
+		"kubectl.kubernetes.io/last-applied": "true",
+		"tools.example.com/owner":            "me",
+	}}}
+
+	pLabels := HostLabelsWithOptions(vObj, nil, opts)
+	assert.Equal(t, pLabels["app"], "nginx")
+	_, ok := pLabels["kubectl.kubernetes.io/last-applied"]
+	assert.Assert(t, !ok)
+	_, ok = pLabels["tools.example.com/owner"]
+	assert.Assert(t, !ok)
+
+	// without options all labels are synced
+	_, ok = HostLabels(vObj, nil)["tools.example.com/owner"]
+	assert.Assert(t, ok)
+
+	// excluded labels stay on the virtual object and are not taken from the host
+	pObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-x-test", Namespace: "host", Labels: map[string]string{}}}
+	for k, v := range pLabels {
+		pObj.Labels[k] = v
+	}
+	pObj.Labels["tools.example.com/host-only"] = "true"
+	vLabels := VirtualLabelsWithOptions(pObj, vObj, opts)
+	assert.DeepEqual(t, vLabels, vObj.Labels)
+}
+
+func TestSyncLabelKeys(t *testing.T) {
+	SyncLabelKeys = []string{"app"}
+	defer func() { SyncLabelKeys = nil }()
+
+	vObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{
+		"app":    "nginx",
+		"secret": "do-not-sync",
+	}}}
+
+	// only allowlisted and internal labels reach the host
+	pLabels := HostLabels(vObj, nil)
+	assert.DeepEqual(t, pLabels, map[string]string{
+		"app":          "nginx",
+		MarkerLabel:    VClusterName,
+		NamespaceLabel: "test",
+	})
+
+	// labels that are not allowlisted stay on the virtual object
+	pObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-x-test", Namespace: "host", Labels: pLabels}}
+	assert.DeepEqual(t, VirtualLabels(pObj, vObj), vObj.Labels)
+}
+
+func TestSyncLabelKeysTranslatedLabel(t *testing.T) {
+	SyncLabelKeys = []string{"release", "app"}
+	defer func() { SyncLabelKeys = nil }()
+
+	vObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{
+		"app":     "a2",
+		"release": "r1",
+	}}}
+
+	// allowlisted labels that are translated on the host survive the round trip
+	pLabels := HostLabels(vObj, nil)
+	assert.Equal(t, pLabels[HostLabel("release")], "r1")
+	pObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-x-test", Namespace: "host", Labels: pLabels}}
+	assert.DeepEqual(t, VirtualLabels(pObj, vObj), vObj.Labels)
+
+	// and pick up changes made on the host
+	pObj.Labels[HostLabel("release")] = "r2"
+	assert.DeepEqual(t, VirtualLabels(pObj, vObj), map[string]string{"app": "a2", "release": "r2"})
+}
+
+func TestIsClusterScoped(t *testing.T) {
+	oldDefault := Default
+	Default = NewSingleNamespaceTranslator("host-namespace")
+	defer func() { Default = oldDefault }()
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	restMapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	ctx := &synccontext.SyncContext{
+		Context:       context.TODO(),
+		VirtualClient: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRESTMapper(restMapper).Build(),
+	}
+
+	// the namespace of a pod that is being created may still be empty
+	vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"app": "test"}}}
+	vClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	assert.Assert(t, !IsClusterScoped(ctx, vPod))
+	assert.Assert(t, IsClusterScoped(ctx, vClusterRole))
+
+	// without a context or mapping only the namespace is checked
+	assert.Assert(t, IsClusterScoped(nil, vPod))
+	assert.Assert(t, IsClusterScoped(ctx, &corev1.ConfigMap{}))
+	assert.Assert(t, !IsClusterScoped(nil, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "test"}}))
+
+	UseAnnotationsForTopology = false
+	pLabels := HostLabelsWithContext(ctx, vPod, nil)
+	assert.DeepEqual(t, pLabels, map[string]string{HostLabel("app"): "test", MarkerLabel: VClusterName})
+	assert.Equal(t, HostLabels(vPod, nil)[MarkerLabel], Default.MarkerLabelCluster())
+	assert.Equal(t, HostLabelsWithContext(ctx, vClusterRole, nil)[MarkerLabel], Default.MarkerLabelCluster())
+
+	pPod := HostMetadataWithContext(ctx, vPod, types.NamespacedName{Name: "test-x-test", Namespace: "host-namespace"})
+	assert.DeepEqual(t, pPod.Labels, pLabels)
+}
+
+func TestLabelSelectorExistsOperators(t *testing.T) {
+	oldDefault := Default
+	Default = NewSingleNamespaceTranslator("host-namespace")
+	defer func() { Default = oldDefault }()
+	UseAnnotationsForTopology = false
+
+	vSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: metav1.LabelSelectorOpExists},
+			{Key: VClusterReleaseLabel, Operator: metav1.LabelSelectorOpExists, Values: []string{"ignored"}},
+			{Key: MarkerLabel, Operator: metav1.LabelSelectorOpDoesNotExist},
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web"}},
+		},
+	}
+	pSelector := HostLabelSelector(vSelector)
+	assert.DeepEqual(t, pSelector.MatchExpressions, []metav1.LabelSelectorRequirement{
+		{Key: "app", Operator: metav1.LabelSelectorOpExists},
+		{Key: HostLabel(VClusterReleaseLabel), Operator: metav1.LabelSelectorOpExists},
+		{Key: HostLabel(MarkerLabel), Operator: metav1.LabelSelectorOpDoesNotExist},
+		{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web"}},
+	})
+
+	// values are not shared with the original selector
+	pSelector.MatchExpressions[3].Values[0] = "changed"
+	assert.Equal(t, vSelector.MatchExpressions[3].Values[0], "web")
+	pSelector.MatchExpressions[3].Values[0] = "web"
+
+	// the host selector matches the host labels of matching virtual objects, even though the host object has the marker label
+	selector, err := metav1.LabelSelectorAsSelector(pSelector)
+	assert.NilError(t, err)
+	pLabels := HostLabelsMap(map[string]string{"app": "test", VClusterReleaseLabel: "release", "tier": "web"}, nil, "test", true)
+	assert.Equal(t, pLabels[MarkerLabel], VClusterName)
+	assert.Assert(t, selector.Matches(labels.Set(pLabels)))
+	pLabels = HostLabelsMap(map[string]string{"app": "test", VClusterReleaseLabel: "release", "tier": "web", MarkerLabel: "other"}, nil, "test", true)
+	assert.Assert(t, !selector.Matches(labels.Set(pLabels)))
+
+	// host only labels can't be translated back and keep their key
+	assert.DeepEqual(t, VirtualLabelSelector(pSelector).MatchExpressions, []metav1.LabelSelectorRequirement{
+		{Key: "app", Operator: metav1.LabelSelectorOpExists},
+		{Key: VClusterReleaseLabel, Operator: metav1.LabelSelectorOpExists},
+		{Key: MarkerLabel, Operator: metav1.LabelSelectorOpDoesNotExist},
+		{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web"}},
+	})
+	assert.DeepEqual(t, VirtualLabelSelector(&metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: NamespaceLabel, Operator: metav1.LabelSelectorOpExists}},
+	}).MatchExpressions, []metav1.LabelSelectorRequirement{{Key: NamespaceLabel, Operator: metav1.LabelSelectorOpExists}})
+}
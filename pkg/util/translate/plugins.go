@@ -0,0 +1,87 @@
+package translate
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TranslatorPlugin translates the fields of a custom resource that reference other objects and
+// therefore cannot be handled by the generic metadata translation, e.g. a namespace nested in the spec.
+type TranslatorPlugin interface {
+	// TranslateToHost is called with the host object after its metadata was translated
+	TranslateToHost(ctx *synccontext.SyncContext, obj *unstructured.Unstructured) error
+
+	// TranslateToVirtual is called with the virtual object after its metadata was translated
+	TranslateToVirtual(ctx *synccontext.SyncContext, obj *unstructured.Unstructured) error
+}
+
+var (
+	translatorPluginsMutex sync.RWMutex
+	translatorPlugins      = map[schema.GroupVersionKind]TranslatorPlugin{}
+)
+
+// RegisterTranslatorPlugin registers the plugin for the given group version kind. Only a single
+// plugin can be registered per kind.
+func RegisterTranslatorPlugin(gvk schema.GroupVersionKind, plugin TranslatorPlugin) error {
+	if plugin == nil {
+		return fmt.Errorf("translator plugin for %s is nil", gvk.String())
+	}
+
+	translatorPluginsMutex.Lock()
+	defer translatorPluginsMutex.Unlock()
+
+	if _, ok := translatorPlugins[gvk]; ok {
+		return fmt.Errorf("translator plugin for %s is already registered", gvk.String())
+	}
+
+	translatorPlugins[gvk] = plugin
+	return nil
+}
+
+// TranslatorPluginFor returns the plugin registered for the given group version kind
+func TranslatorPluginFor(gvk schema.GroupVersionKind) (TranslatorPlugin, bool) {
+	translatorPluginsMutex.RLock()
+	defer translatorPluginsMutex.RUnlock()
+
+	plugin, ok := translatorPlugins[gvk]
+	return plugin, ok
+}
+
+// CustomResourceToHost translates the metadata of the virtual custom resource and afterwards
+// calls the plugin registered for its kind, if there is any.
+func CustomResourceToHost(ctx *synccontext.SyncContext, vObj *unstructured.Unstructured, name types.NamespacedName) (*unstructured.Unstructured, error) {
+	pObj := HostMetadataWithContext(ctx, vObj, name)
+	plugin, ok := TranslatorPluginFor(vObj.GroupVersionKind())
+	if !ok {
+		return pObj, nil
+	}
+
+	err := plugin.TranslateToHost(ctx, pObj)
+	if err != nil {
+		return nil, fmt.Errorf("translate %s %s/%s to host: %w", vObj.GetKind(), vObj.GetNamespace(), vObj.GetName(), err)
+	}
+
+	return pObj, nil
+}
+
+// CustomResourceToVirtual translates the metadata of the host custom resource and afterwards
+// calls the plugin registered for its kind, if there is any.
+func CustomResourceToVirtual(ctx *synccontext.SyncContext, pObj *unstructured.Unstructured, name types.NamespacedName) (*unstructured.Unstructured, error) {
+	vObj := VirtualMetadata(pObj, name)
+	plugin, ok := TranslatorPluginFor(pObj.GroupVersionKind())
+	if !ok {
+		return vObj, nil
+	}
+
+	err := plugin.TranslateToVirtual(ctx, vObj)
+	if err != nil {
+		return nil, fmt.Errorf("translate %s %s/%s to virtual: %w", pObj.GetKind(), pObj.GetNamespace(), pObj.GetName(), err)
+	}
+
+	return vObj, nil
+}
@@ -0,0 +1,83 @@
+package translate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var clusterGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Cluster"}
+
+// clusterRefPlugin rewrites spec.clusterRef.namespace, which references a namespace in the virtual cluster
+type clusterRefPlugin struct{}
+
+func (clusterRefPlugin) TranslateToHost(ctx *synccontext.SyncContext, obj *unstructured.Unstructured) error {
+	namespace, found, err := unstructured.NestedString(obj.Object, "spec", "clusterRef", "namespace")
+	if err != nil || !found {
+		return err
+	}
+
+	return unstructured.SetNestedField(obj.Object, Default.HostNamespace(ctx, namespace), "spec", "clusterRef", "namespace")
+}
+
+func (clusterRefPlugin) TranslateToVirtual(_ *synccontext.SyncContext, obj *unstructured.Unstructured) error {
+	// the host namespace is not reversible, so fall back to the namespace of the object itself
+	_, found, err := unstructured.NestedString(obj.Object, "spec", "clusterRef", "namespace")
+	if err != nil || !found {
+		return err
+	}
+
+	return unstructured.SetNestedField(obj.Object, obj.GetNamespace(), "spec", "clusterRef", "namespace")
+}
+
+func TestTranslatorPlugin(t *testing.T) {
+	defer func() {
+		translatorPluginsMutex.Lock()
+		delete(translatorPlugins, clusterGVK)
+		translatorPluginsMutex.Unlock()
+	}()
+
+	ctx := &synccontext.SyncContext{Context: context.TODO()}
+	vObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"clusterRef": map[string]interface{}{
+				"name":      "my-cluster",
+				"namespace": "test",
+			},
+		},
+	}}
+	vObj.SetGroupVersionKind(clusterGVK)
+	vObj.SetName("test")
+	vObj.SetNamespace("test")
+
+	// without a plugin only the metadata is translated
+	pName := types.NamespacedName{Name: "test-x-test-x-suffix", Namespace: "host"}
+	pObj, err := CustomResourceToHost(ctx, vObj, pName)
+	assert.NilError(t, err)
+	assert.Equal(t, pObj.GetName(), pName.Name)
+	namespace, _, _ := unstructured.NestedString(pObj.Object, "spec", "clusterRef", "namespace")
+	assert.Equal(t, namespace, "test")
+
+	assert.NilError(t, RegisterTranslatorPlugin(clusterGVK, clusterRefPlugin{}))
+	assert.ErrorContains(t, RegisterTranslatorPlugin(clusterGVK, clusterRefPlugin{}), "already registered")
+
+	pObj, err = CustomResourceToHost(ctx, vObj, pName)
+	assert.NilError(t, err)
+	namespace, _, _ = unstructured.NestedString(pObj.Object, "spec", "clusterRef", "namespace")
+	assert.Equal(t, namespace, Default.HostNamespace(ctx, "test"))
+
+	// the virtual object is untouched
+	namespace, _, _ = unstructured.NestedString(vObj.Object, "spec", "clusterRef", "namespace")
+	assert.Equal(t, namespace, "test")
+
+	vObj, err = CustomResourceToVirtual(ctx, pObj, types.NamespacedName{Name: "test", Namespace: "test"})
+	assert.NilError(t, err)
+	assert.Equal(t, vObj.GetName(), "test")
+	namespace, _, _ = unstructured.NestedString(vObj.Object, "spec", "clusterRef", "namespace")
+	assert.Equal(t, namespace, "test")
+}
@@ -2,7 +2,6 @@ package translate
 
 import (
 	"crypto/sha256"
-	"encoding/hex"
 	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/mappings"
@@ -26,6 +25,10 @@ func NewSingleNamespaceTranslator(targetNamespace string) Translator {
 
 type singleNamespace struct {
 	targetNamespace string
+
+	// snapshot is the translation configuration of a translator rebuilt with NewTranslatorFromSnapshot.
+	// If nil, the package level settings are used.
+	snapshot *TranslatorConfig
 }
 
 func (s *singleNamespace) SingleNamespaceTarget() bool {
@@ -37,8 +40,9 @@ func (s *singleNamespace) HostName(ctx *synccontext.SyncContext, vName, vNamespa
 		return types.NamespacedName{}
 	}
 
+	cfg := s.config()
 	return types.NamespacedName{
-		Name:      SingleNamespaceHostName(vName, vNamespace, VClusterName),
+		Name:      safeConcatName(strings.Join([]string{vName, "x", vNamespace, "x", cfg.VClusterName}, "-"), cfg.hashSalt(), DefaultNameHashLength),
 		Namespace: s.HostNamespace(ctx, vNamespace),
 	}
 }
@@ -49,7 +53,8 @@ func (s *singleNamespace) HostNameShort(ctx *synccontext.SyncContext, vName, vNa
 	}
 
 	// we use base36 to avoid as much conflicts as possible
-	digest := sha256.Sum256([]byte(strings.Join([]string{vName, "x", vNamespace, "x", VClusterName}, "-")))
+	cfg := s.config()
+	digest := sha256.Sum256([]byte(cfg.hashSalt() + strings.Join([]string{vName, "x", vNamespace, "x", cfg.VClusterName}, "-")))
 	return types.NamespacedName{
 		Name:      "v" + base36.EncodeBytes(digest[:])[0:13], // needs to start with a character for certain objects (e.g. services)
 		Namespace: s.HostNamespace(ctx, vNamespace),
@@ -60,24 +65,25 @@ func SingleNamespaceHostName(name, namespace, suffix string) string {
 	if name == "" {
 		return ""
 	}
-	return SafeConcatName(name, "x", namespace, "x", suffix)
+	return safeConcatSaltedName(name, "x", namespace, "x", suffix)
 }
 
 func (s *singleNamespace) HostNameCluster(name string) string {
 	if name == "" {
 		return ""
 	}
-	return SafeConcatName("vcluster", name, "x", s.targetNamespace, "x", VClusterName)
+	cfg := s.config()
+	return safeConcatName(strings.Join([]string{"vcluster", name, "x", s.targetNamespace, "x", cfg.VClusterName}, "-"), cfg.hashSalt(), DefaultNameHashLength)
 }
 
 func (s *singleNamespace) MarkerLabelCluster() string {
-	return SafeConcatName(s.targetNamespace, "x", VClusterName)
+	return SafeConcatName(s.targetNamespace, "x", s.config().VClusterName)
 }
 
 func (s *singleNamespace) IsManaged(ctx *synccontext.SyncContext, pObj client.Object) bool {
 	// check if cluster scoped object
 	if pObj.GetNamespace() == "" {
-		return pObj.GetLabels()[MarkerLabel] == s.MarkerLabelCluster()
+		return s.getMarker(pObj) == s.MarkerLabelCluster()
 	}
 
 	// is object not in our target namespace?
@@ -86,7 +92,7 @@ func (s *singleNamespace) IsManaged(ctx *synccontext.SyncContext, pObj client.Ob
 	}
 
 	// if host namespace is mapped, we don't check for marker label
-	if pObj.GetLabels()[MarkerLabel] != VClusterName {
+	if s.getMarker(pObj) != s.config().VClusterName {
 		return false
 	}
 
@@ -135,16 +141,22 @@ func (s *singleNamespace) IsTargetedNamespace(_ *synccontext.SyncContext, pNames
 	return pNamespace == s.targetNamespace
 }
 
-func (s *singleNamespace) LabelsToTranslate() map[string]bool {
-	return map[string]bool{
-		// rewrite release
-		VClusterReleaseLabel: true,
-
-		// namespace, marker & controlled-by
-		NamespaceLabel:  true,
-		MarkerLabel:     true,
-		ControllerLabel: true,
+// getMarker is like GetMarker, but reads the marker with the configuration of the translator
+func (s *singleNamespace) getMarker(pObj client.Object) string {
+	cfg := s.config()
+	if cfg.UseAnnotationsForTopology && pObj.GetAnnotations()[cfg.MarkerAnnotation] != "" {
+		return pObj.GetAnnotations()[cfg.MarkerAnnotation]
 	}
+
+	return pObj.GetLabels()[cfg.MarkerLabel]
+}
+
+func (s *singleNamespace) LabelsToTranslate() map[string]bool {
+	return s.config().labelsToTranslate()
+}
+
+func (s *singleNamespace) HostLabel(vLabel string) string {
+	return s.config().HostLabel(vLabel)
 }
 
 func (s *singleNamespace) HostNamespace(_ *synccontext.SyncContext, vNamespace string) string {
@@ -155,6 +167,8 @@ func (s *singleNamespace) HostNamespace(_ *synccontext.SyncContext, vNamespace s
 	return s.targetNamespace
 }
 
+// HostLabelNamespace translates the label key of a namespace label selector with the package level settings,
+// see TranslatorConfig.HostLabelNamespace for a translator rebuilt from a snapshot
 func HostLabelNamespace(key string) string {
 	return convertLabelKeyWithPrefix(NamespaceLabelPrefix, key)
 }
@@ -166,6 +180,9 @@ func HostLabelSelectorNamespace(labelSelector *metav1.LabelSelector) *metav1.Lab
 }
 
 func convertLabelKeyWithPrefix(prefix, key string) string {
-	digest := sha256.Sum256([]byte(key))
-	return SafeConcatName(prefix, VClusterName, "x", hex.EncodeToString(digest[0:])[0:10])
+	return labelKeyWithPrefix(prefix, VClusterName, key)
+}
+
+func labelKeyWithPrefix(prefix, vClusterName, key string) string {
+	return SafeConcatName(prefix, vClusterName, "x", shortHash(key, 10))
 }
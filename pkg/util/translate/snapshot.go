@@ -0,0 +1,185 @@
+package translate
+
+import (
+	"fmt"
+	"slices"
+)
+
+// TranslatorConfig is a serializable snapshot of the effective translation configuration. It can be
+// used to rebuild a translator that translates names, markers and label keys exactly like the one of a live
+// vCluster, e.g. for tests or offline translation. The namespace mappings of the namespace syncer are part of
+// the vCluster config and not of the translator, so they are not included. As it contains the HashSalt it
+// should be treated as a secret.
+type TranslatorConfig struct {
+	// SingleNamespaceTarget signals if all objects are synced into a single namespace
+	SingleNamespaceTarget bool `json:"singleNamespaceTarget"`
+
+	// TargetNamespace is the host namespace objects are synced to
+	TargetNamespace string `json:"targetNamespace"`
+
+	// VClusterName is the name of the vCluster
+	VClusterName string `json:"vClusterName"`
+
+	// LabelPrefix and NamespaceLabelPrefix are the prefixes of translated label keys
+	LabelPrefix          string `json:"labelPrefix"`
+	NamespaceLabelPrefix string `json:"namespaceLabelPrefix"`
+
+	// VClusterReleaseLabel, NamespaceLabel, MarkerLabel, ScopeLabel and ControllerLabel are the labels vCluster sets on host objects
+	VClusterReleaseLabel string `json:"vClusterReleaseLabel"`
+	NamespaceLabel       string `json:"namespaceLabel"`
+	MarkerLabel          string `json:"markerLabel"`
+	ScopeLabel           string `json:"scopeLabel"`
+	ControllerLabel      string `json:"controllerLabel"`
+
+	// MarkerAnnotation stores the marker of host objects if UseAnnotationsForTopology is set
+	MarkerAnnotation string `json:"markerAnnotation"`
+
+	// LabelsToTranslate are the label keys that are always translated, sorted alphabetically
+	LabelsToTranslate []string `json:"labelsToTranslate,omitempty"`
+
+	// SyncLabelKeys is the allowlist of label keys that are synced to host objects
+	SyncLabelKeys []string `json:"syncLabelKeys,omitempty"`
+
+	// PreserveHostAnnotationKeys are annotations that are owned by the host cluster
+	PreserveHostAnnotationKeys []string `json:"preserveHostAnnotationKeys,omitempty"`
+
+	// UseAnnotationsForTopology stores the marker of host objects in an annotation instead of a label
+	UseAnnotationsForTopology bool `json:"useAnnotationsForTopology,omitempty"`
+
+	// HashSalt is mixed into the hashes of host names
+	HashSalt string `json:"hashSalt,omitempty"`
+
+	// CompatibilityMode is the version of the translation algorithm used for host names
+	CompatibilityMode int `json:"compatibilityMode"`
+}
+
+// hashSalt returns the HashSalt if the CompatibilityMode mixes it into host names.
+func (c TranslatorConfig) hashSalt() string {
+	return compatibleHashSalt(c.CompatibilityMode, c.HashSalt)
+}
+
+// labelsToTranslate returns the label keys that are always translated
+func (c TranslatorConfig) labelsToTranslate() map[string]bool {
+	return map[string]bool{
+		// rewrite release
+		c.VClusterReleaseLabel: true,
+
+		// namespace, marker, scope & controlled-by
+		c.NamespaceLabel:  true,
+		c.MarkerLabel:     true,
+		c.ScopeLabel:      true,
+		c.ControllerLabel: true,
+	}
+}
+
+// HostLabel is like the package level HostLabel, but uses this configuration
+func (c TranslatorConfig) HostLabel(vLabel string) string {
+	if c.labelsToTranslate()[vLabel] {
+		return labelKeyWithPrefix(c.LabelPrefix, c.VClusterName, vLabel)
+	}
+
+	return vLabel
+}
+
+// HostLabelNamespace is like the package level HostLabelNamespace, but uses this configuration
+func (c TranslatorConfig) HostLabelNamespace(key string) string {
+	return labelKeyWithPrefix(c.NamespaceLabelPrefix, c.VClusterName, key)
+}
+
+// IsTranslatedLabel is like the package level IsTranslatedLabel, but uses this configuration
+func (c TranslatorConfig) IsTranslatedLabel(label string) (string, bool) {
+	for k := range c.labelsToTranslate() {
+		if labelKeyWithPrefix(c.LabelPrefix, c.VClusterName, k) == label {
+			return k, true
+		}
+	}
+
+	return "", false
+}
+
+// VirtualLabel is like the package level VirtualLabel, but uses this configuration
+func (c TranslatorConfig) VirtualLabel(pLabel string) (string, bool) {
+	if c.labelsToTranslate()[pLabel] {
+		return "", false
+	}
+
+	if originalLabel, ok := c.IsTranslatedLabel(pLabel); ok {
+		return originalLabel, true
+	}
+
+	return pLabel, true
+}
+
+// config returns the translation configuration of the translator. Translators that were not rebuilt from a
+// snapshot use the package level settings. LabelsToTranslate is only filled in by Snapshot.
+func (s *singleNamespace) config() TranslatorConfig {
+	if s.snapshot != nil {
+		return *s.snapshot
+	}
+
+	return TranslatorConfig{
+		SingleNamespaceTarget:      true,
+		TargetNamespace:            s.targetNamespace,
+		VClusterName:               VClusterName,
+		LabelPrefix:                LabelPrefix,
+		NamespaceLabelPrefix:       NamespaceLabelPrefix,
+		VClusterReleaseLabel:       VClusterReleaseLabel,
+		NamespaceLabel:             NamespaceLabel,
+		MarkerLabel:                MarkerLabel,
+		ScopeLabel:                 ScopeLabel,
+		ControllerLabel:            ControllerLabel,
+		MarkerAnnotation:           MarkerAnnotation,
+		SyncLabelKeys:              SyncLabelKeys,
+		PreserveHostAnnotationKeys: PreserveHostAnnotationKeys,
+		UseAnnotationsForTopology:  UseAnnotationsForTopology,
+		HashSalt:                   HashSalt,
+		CompatibilityMode:          CompatibilityMode,
+	}
+}
+
+// Snapshot returns the effective translation configuration of the translator.
+func (s *singleNamespace) Snapshot() TranslatorConfig {
+	labelsToTranslate := []string{}
+	for label, translate := range s.LabelsToTranslate() {
+		if translate {
+			labelsToTranslate = append(labelsToTranslate, label)
+		}
+	}
+	slices.Sort(labelsToTranslate)
+
+	cfg := s.config()
+	cfg.LabelsToTranslate = labelsToTranslate
+	cfg.SyncLabelKeys = slices.Clone(cfg.SyncLabelKeys)
+	cfg.PreserveHostAnnotationKeys = slices.Clone(cfg.PreserveHostAnnotationKeys)
+	return cfg
+}
+
+// NewTranslatorFromSnapshot rebuilds a translator from a snapshot. The rebuilt translator carries its own copy
+// of the configuration and doesn't change the package level translation settings, so it can be used next to
+// the translator of a live vCluster. Only single namespace translators can be rebuilt.
+//
+// Only the methods of the rebuilt translator use the snapshot. The package level helpers such as HostLabelsMap,
+// HostLabelNamespace, VirtualLabel and HostMetadata keep using the package level settings and Default, use the
+// methods of TranslatorConfig to translate label keys with the snapshot instead.
+func NewTranslatorFromSnapshot(cfg TranslatorConfig) (Translator, error) {
+	if !cfg.SingleNamespaceTarget {
+		return nil, fmt.Errorf("cannot rebuild translator from snapshot: only single namespace translators are supported")
+	} else if cfg.TargetNamespace == "" {
+		return nil, fmt.Errorf("cannot rebuild translator from snapshot: target namespace is missing")
+	} else if err := ValidateCompatibilityMode(cfg.CompatibilityMode); err != nil {
+		return nil, fmt.Errorf("cannot rebuild translator from snapshot: %w", err)
+	}
+
+	cfg.LabelsToTranslate = slices.Clone(cfg.LabelsToTranslate)
+	cfg.SyncLabelKeys = slices.Clone(cfg.SyncLabelKeys)
+	cfg.PreserveHostAnnotationKeys = slices.Clone(cfg.PreserveHostAnnotationKeys)
+	translator := &singleNamespace{
+		targetNamespace: cfg.TargetNamespace,
+		snapshot:        &cfg,
+	}
+	if snapshot := translator.Snapshot(); !slices.Equal(snapshot.LabelsToTranslate, cfg.LabelsToTranslate) {
+		return nil, fmt.Errorf("cannot rebuild translator from snapshot: labels to translate %v differ from %v", snapshot.LabelsToTranslate, cfg.LabelsToTranslate)
+	}
+
+	return translator, nil
+}
@@ -0,0 +1,81 @@
+package translate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	defer func(vClusterName, labelPrefix, hashSalt string) {
+		VClusterName, LabelPrefix, HashSalt = vClusterName, labelPrefix, hashSalt
+	}(VClusterName, LabelPrefix, HashSalt)
+	defer func() { SyncLabelKeys = nil }()
+
+	VClusterName = "my-vcluster"
+	LabelPrefix = "example.com/label"
+	HashSalt = "salt"
+	SyncLabelKeys = []string{"app", VClusterReleaseLabel}
+	translator := NewSingleNamespaceTranslator("vcluster-my-vcluster")
+
+	longName := "a-very-long-pod-name-that-is-way-too-long-for-kubernetes"
+	hostName := translator.HostName(nil, longName, "default")
+	hostNameCluster := translator.HostNameCluster("my-cluster-role")
+	hostReleaseLabel := HostLabel(VClusterReleaseLabel)
+	hostNamespaceLabel := HostLabelNamespace("team")
+
+	// round trip the snapshot through json
+	snapshot := translator.Snapshot()
+	assert.Equal(t, snapshot.TargetNamespace, "vcluster-my-vcluster")
+	assert.DeepEqual(t, snapshot.LabelsToTranslate, []string{VClusterReleaseLabel, ControllerLabel, MarkerLabel, NamespaceLabel, ScopeLabel})
+	assert.Equal(t, snapshot.MarkerAnnotation, MarkerAnnotation)
+	assert.DeepEqual(t, snapshot.SyncLabelKeys, []string{"app", VClusterReleaseLabel})
+	raw, err := json.Marshal(snapshot)
+	assert.NilError(t, err)
+
+	// reset the package level settings as if we were in another process
+	VClusterName = "suffix"
+	LabelPrefix = "vcluster.loft.sh/label"
+	HashSalt = ""
+	SyncLabelKeys = nil
+
+	restoredSnapshot := TranslatorConfig{}
+	assert.NilError(t, json.Unmarshal(raw, &restoredSnapshot))
+	restored, err := NewTranslatorFromSnapshot(restoredSnapshot)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, restored.Snapshot(), snapshot)
+	assert.Equal(t, restored.HostName(nil, longName, "default"), hostName)
+	assert.Equal(t, restored.HostNameCluster("my-cluster-role"), hostNameCluster)
+	assert.Equal(t, restored.HostLabel(VClusterReleaseLabel), hostReleaseLabel)
+	assert.Equal(t, restored.HostLabel("app"), "app")
+
+	// label keys are translated with the snapshot through its methods
+	assert.Equal(t, restoredSnapshot.HostLabel(VClusterReleaseLabel), hostReleaseLabel)
+	assert.Equal(t, restoredSnapshot.HostLabelNamespace("team"), hostNamespaceLabel)
+	vLabel, ok := restoredSnapshot.VirtualLabel(hostReleaseLabel)
+	assert.Assert(t, ok)
+	assert.Equal(t, vLabel, VClusterReleaseLabel)
+	_, ok = restoredSnapshot.VirtualLabel(VClusterReleaseLabel)
+	assert.Assert(t, !ok)
+
+	// the package level settings are not changed and the package level helpers keep using them
+	assert.Equal(t, VClusterName, "suffix")
+	assert.Equal(t, LabelPrefix, "vcluster.loft.sh/label")
+	assert.Equal(t, HashSalt, "")
+	assert.Assert(t, HostLabel(VClusterReleaseLabel) != hostReleaseLabel)
+	assert.Assert(t, HostLabelNamespace("team") != hostNamespaceLabel)
+	_, ok = IsTranslatedLabel(hostReleaseLabel)
+	assert.Assert(t, !ok)
+	_, ok = HostLabelsMap(map[string]string{VClusterReleaseLabel: "my-release"}, nil, "default", true)[hostReleaseLabel]
+	assert.Assert(t, !ok)
+
+	// invalid snapshots are rejected
+	_, err = NewTranslatorFromSnapshot(TranslatorConfig{TargetNamespace: "test"})
+	assert.ErrorContains(t, err, "only single namespace translators are supported")
+	invalidSnapshot := restoredSnapshot
+	invalidSnapshot.LabelsToTranslate = []string{"other"}
+	_, err = NewTranslatorFromSnapshot(invalidSnapshot)
+	assert.ErrorContains(t, err, "labels to translate")
+	assert.Equal(t, VClusterName, "suffix")
+}
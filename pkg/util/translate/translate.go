@@ -5,25 +5,38 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"maps"
 	"math"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/loft-sh/vcluster/pkg/mappings"
 	"github.com/loft-sh/vcluster/pkg/scheme"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/stringutil"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1clientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
@@ -32,9 +45,41 @@ const (
 	SkipBackSyncInMultiNamespaceMode = "vcluster.loft.sh/skip-backsync"
 )
 
+// Owner is the default owner of synced namespaced host objects, usually the vCluster service. The syncers get
+// their owner from the SyncContext, this is only the fallback if the context carries no owner, see OwnerFor.
 var Owner client.Object
 
+// OwnerFor returns the owner of synced namespaced host objects for the given context. It falls back to the
+// package level Owner if the context has no owner.
+func OwnerFor(ctx *synccontext.SyncContext) client.Object {
+	if ctx != nil && ctx.Owner != nil {
+		return ctx.Owner
+	}
+
+	return Owner
+}
+
+// CopyObjectWithContext copies the object and resets its metadata. If setOwner is true, the owner reference is set
+// to the owner of the context, see OwnerFor.
+func CopyObjectWithContext[T client.Object](ctx *synccontext.SyncContext, obj T, name types.NamespacedName, setOwner bool, excludedAnnotations ...string) T {
+	var owner client.Object
+	if setOwner {
+		owner = OwnerFor(ctx)
+	}
+
+	return CopyObjectWithOwner(obj, name, owner, excludedAnnotations...)
+}
+
+// CopyObjectWithName is like CopyObjectWithContext, but sets the owner reference to the package level Owner.
+//
+// Deprecated: use CopyObjectWithContext, which takes the owner from the context.
 func CopyObjectWithName[T client.Object](obj T, name types.NamespacedName, setOwner bool, excludedAnnotations ...string) T {
+	return CopyObjectWithContext(nil, obj, name, setOwner, excludedAnnotations...)
+}
+
+// CopyObjectWithOwner is like CopyObjectWithContext, but sets the owner reference to the given owner instead of the
+// owner of the context. A nil owner sets no owner reference.
+func CopyObjectWithOwner[T client.Object](obj T, name types.NamespacedName, owner client.Object, excludedAnnotations ...string) T {
 	target := obj.DeepCopyObject().(T)
 
 	// reset metadata & translate name and namespace
@@ -44,8 +89,8 @@ func CopyObjectWithName[T client.Object](obj T, name types.NamespacedName, setOw
 		target.SetNamespace(name.Namespace)
 
 		// set owning stateful set if defined
-		if setOwner && Owner != nil {
-			target.SetOwnerReferences(GetOwnerReference(obj))
+		if owner != nil {
+			target.SetOwnerReferences(GetOwnerReferenceFor(owner, obj))
 		}
 	}
 
@@ -53,21 +98,144 @@ func CopyObjectWithName[T client.Object](obj T, name types.NamespacedName, setOw
 	return target
 }
 
+// HostMetadataWithContext returns a copy of the virtual object with the host name and the translated labels and
+// annotations. The owner reference is set to the owner of the context, see OwnerFor, and the labels are translated
+// with HostLabelsWithContext, so namespaced objects that have no namespace yet get the namespaced topology labels.
+func HostMetadataWithContext[T client.Object](ctx *synccontext.SyncContext, vObj T, name types.NamespacedName, excludedAnnotations ...string) T {
+	pObj := hostMetadataWithContext(ctx, vObj, name, excludedAnnotations...)
+	recordAudit(vObj, name)
+	return pObj
+}
+
+// hostMetadataWithContext is like HostMetadataWithContext, but doesn't record the translation in the Audit sink
+func hostMetadataWithContext[T client.Object](ctx *synccontext.SyncContext, vObj T, name types.NamespacedName, excludedAnnotations ...string) T {
+	pObj := hostMetadataWithoutLabels(vObj, name, kindAnnotationValue(vObj), OwnerFor(ctx), excludedAnnotations...)
+	pObj.SetLabels(HostLabelsWithContext(ctx, vObj, nil))
+	return pObj
+}
+
+// HostMetadata is like HostMetadataWithContext, but sets the owner reference to the package level Owner and
+// translates the labels with HostLabels.
+//
+// Deprecated: use HostMetadataWithContext, which takes the owner from the context.
 func HostMetadata[T client.Object](vObj T, name types.NamespacedName, excludedAnnotations ...string) T {
-	pObj := CopyObjectWithName(vObj, name, true, excludedAnnotations...)
-	stripExcludedAnnotations(vObj, excludedAnnotations...)
-	pObj.SetAnnotations(HostAnnotations(vObj, pObj, excludedAnnotations...))
+	pObj := hostMetadataWithoutLabels(vObj, name, kindAnnotationValue(vObj), Owner, excludedAnnotations...)
 	pObj.SetLabels(HostLabels(vObj, nil))
+	recordAudit(vObj, name)
+	return pObj
+}
+
+func hostMetadataWithoutLabels[T client.Object](vObj T, name types.NamespacedName, kind string, owner client.Object, excludedAnnotations ...string) T {
+	pObj := CopyObjectWithOwner(vObj, name, owner, excludedAnnotations...)
+	stripExcludedAnnotations(vObj, excludedAnnotations...)
+	pObj.SetAnnotations(hostAnnotations(vObj, pObj, kind, excludedAnnotations...))
 	return pObj
 }
 
+// TranslateListToHost applies HostMetadataWithContext to all given virtual objects. The host name of each object
+// is retrieved via nameFunc. In contrast to calling HostMetadataWithContext per item, the kind of the objects is
+// only looked up once for the whole list and the scope and topology labels, which depend on the host namespace,
+// only once per virtual namespace.
+func TranslateListToHost[T client.Object](ctx *synccontext.SyncContext, items []T, nameFunc func(T) types.NamespacedName, excludedAnnotations ...string) []T {
+	if len(items) == 0 {
+		return nil
+	}
+
+	// unstructured lists can contain objects of different kinds, so we can't share the lookups there
+	_, isUnstructured := any(items[0]).(runtime.Unstructured)
+	kind := kindAnnotationValue(items[0])
+	owner := OwnerFor(ctx)
+	namespaces := map[string]map[string]string{}
+	pObjs := make([]T, 0, len(items))
+	for _, vObj := range items {
+		if isUnstructured {
+			kind = kindAnnotationValue(vObj)
+		}
+
+		topology, ok := namespaces[vObj.GetNamespace()]
+		if !ok || isUnstructured {
+			topology = topologyLabels(vObj.GetNamespace(), IsClusterScoped(ctx, vObj))
+			namespaces[vObj.GetNamespace()] = topology
+		}
+
+		name := nameFunc(vObj)
+		pObj := hostMetadataWithoutLabels(vObj, name, kind, owner, excludedAnnotations...)
+		pObj.SetLabels(hostLabelsWithTopology(vObj, nil, topology, LabelsOptions{}))
+		recordAudit(vObj, name)
+		pObjs = append(pObjs, pObj)
+	}
+
+	return pObjs
+}
+
+// HostNames translates the given virtual names to host names with the default translator. Items that occur
+// multiple times within the batch are only translated once, so repeated long names are not hashed again even
+// if they were already evicted from the hash cache. The returned slice has the same order as items.
+func HostNames(ctx *synccontext.SyncContext, items []types.NamespacedName) []types.NamespacedName {
+	if len(items) == 0 {
+		return nil
+	}
+
+	translated := make(map[types.NamespacedName]types.NamespacedName, len(items))
+	hostNames := make([]types.NamespacedName, 0, len(items))
+	for _, item := range items {
+		hostName, ok := translated[item]
+		if !ok {
+			hostName = Default.HostName(ctx, item.Name, item.Namespace)
+			translated[item] = hostName
+		}
+
+		hostNames = append(hostNames, hostName)
+	}
+
+	return hostNames
+}
+
+// TranslateListToVirtual applies VirtualMetadata to all given host objects. The virtual name of each object
+// is retrieved via nameFunc.
+func TranslateListToVirtual[T client.Object](items []T, nameFunc func(T) types.NamespacedName, excludedAnnotations ...string) []T {
+	if len(items) == 0 {
+		return nil
+	}
+
+	vObjs := make([]T, 0, len(items))
+	for _, pObj := range items {
+		vObjs = append(vObjs, VirtualMetadata(pObj, nameFunc(pObj), excludedAnnotations...))
+	}
+
+	return vObjs
+}
+
 func VirtualMetadata[T client.Object](pObj T, name types.NamespacedName, excludedAnnotations ...string) T {
-	vObj := CopyObjectWithName(pObj, name, false, excludedAnnotations...)
+	vObj := CopyObjectWithOwner(pObj, name, nil, excludedAnnotations...)
 	vObj.SetAnnotations(VirtualAnnotations(pObj, nil, excludedAnnotations...))
 	vObj.SetLabels(VirtualLabels(pObj, nil))
 	return vObj
 }
 
+// HostObjectMeta returns the translated metadata of the host object for the given virtual object, including the name,
+// namespace, labels, annotations and owner references, see HostMetadataWithContext. The virtual object is not modified.
+func HostObjectMeta(ctx *synccontext.SyncContext, vObj client.Object, name types.NamespacedName, excludedAnnotations ...string) metav1.ObjectMeta {
+	pObj := HostMetadataWithContext(ctx, vObj.DeepCopyObject().(client.Object), name, excludedAnnotations...)
+	return translatedObjectMeta(pObj)
+}
+
+// VirtualObjectMeta returns the translated metadata of the virtual object for the given host object, see VirtualMetadata
+func VirtualObjectMeta(_ *synccontext.SyncContext, pObj client.Object, name types.NamespacedName, excludedAnnotations ...string) metav1.ObjectMeta {
+	vObj := VirtualMetadata(pObj, name, excludedAnnotations...)
+	return translatedObjectMeta(vObj)
+}
+
+func translatedObjectMeta(obj client.Object) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		Labels:          obj.GetLabels(),
+		Annotations:     obj.GetAnnotations(),
+		OwnerReferences: obj.GetOwnerReferences(),
+	}
+}
+
 func stripExcludedAnnotations(obj client.Object, excludedAnnotations ...string) {
 	annotations := obj.GetAnnotations()
 	for k := range annotations {
@@ -79,19 +247,51 @@ func stripExcludedAnnotations(obj client.Object, excludedAnnotations ...string)
 }
 
 func VirtualAnnotations(pObj, vObj client.Object, excluded ...string) map[string]string {
-	excluded = append(excluded, NameAnnotation, NamespaceAnnotation, HostNameAnnotation, HostNamespaceAnnotation, UIDAnnotation, KindAnnotation, ManagedAnnotationsAnnotation, ManagedLabelsAnnotation)
-	var toAnnotations map[string]string
+	var vAnnotations map[string]string
 	if vObj != nil {
-		toAnnotations = vObj.GetAnnotations()
+		vAnnotations = vObj.GetAnnotations()
 	}
 
-	return copyMaps(pObj.GetAnnotations(), toAnnotations, func(key string) bool {
+	return VirtualAnnotationsMap(pObj.GetAnnotations(), vAnnotations, excluded...)
+}
+
+// VirtualAnnotationsMap translates the host annotations to virtual annotations. The annotations vCluster sets on host
+// objects and the excluded annotations are never taken from the host, but kept from the existing virtual annotations.
+func VirtualAnnotationsMap(pAnnotations, vAnnotations map[string]string, excluded ...string) map[string]string {
+	vClusterAnnotations := [...]string{NameAnnotation, NamespaceAnnotation, HostNameAnnotation, HostNamespaceAnnotation, UIDAnnotation, KindAnnotation, MarkerAnnotation, TranslateVersionAnnotation, ManagedAnnotationsAnnotation, ManagedLabelsAnnotation}
+
+	// fast path: there is nothing to exclude, so the host annotations can be copied as they are
+	if !hasAnyKey(pAnnotations, vClusterAnnotations[:]) && !hasAnyKey(pAnnotations, excluded) && !hasAnyKey(vAnnotations, vClusterAnnotations[:]) && !hasAnyKey(vAnnotations, excluded) {
+		retMap := maps.Clone(pAnnotations)
+		if retMap == nil {
+			retMap = map[string]string{}
+		}
+		return retMap
+	}
+
+	excluded = append(excluded, vClusterAnnotations[:]...)
+	return copyMaps(pAnnotations, vAnnotations, func(key string) bool {
 		return exists(excluded, key)
 	})
 }
 
+// hasAnyKey checks if any of the given keys is set in the map
+func hasAnyKey(m map[string]string, keys []string) bool {
+	if len(m) == 0 {
+		return false
+	}
+
+	for _, key := range keys {
+		if _, ok := m[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 func copyMaps(fromMap, toMap map[string]string, excludeKey func(string) bool) map[string]string {
-	retMap := map[string]string{}
+	retMap := make(map[string]string, len(fromMap))
 	for k, v := range fromMap {
 		if excludeKey != nil && excludeKey(k) {
 			continue
@@ -113,7 +313,59 @@ func copyMaps(fromMap, toMap map[string]string, excludeKey func(string) bool) ma
 }
 
 func HostAnnotations(vObj, pObj client.Object, excluded ...string) map[string]string {
-	excluded = append(excluded, NameAnnotation, HostNameAnnotation, HostNamespaceAnnotation, UIDAnnotation, KindAnnotation, NamespaceAnnotation)
+	return hostAnnotations(vObj, pObj, kindAnnotationValue(vObj), excluded...)
+}
+
+// HostAnnotationsOptions configure HostAnnotationsWithOptions
+type HostAnnotationsOptions struct {
+	// GroupVersionKind is stored in the KindAnnotation instead of resolving the kind of the virtual object
+	// from the scheme, e.g. for unstructured objects of kinds that are not registered
+	GroupVersionKind schema.GroupVersionKind
+
+	// SkipKindAnnotation omits the KindAnnotation
+	SkipKindAnnotation bool
+
+	// Excluded are annotations that are not synced to the host
+	Excluded []string
+}
+
+// HostAnnotationsWithOptions is like HostAnnotations, but allows to set or omit the KindAnnotation explicitly
+func HostAnnotationsWithOptions(vObj, pObj client.Object, opts HostAnnotationsOptions) map[string]string {
+	kind := ""
+	switch {
+	case opts.SkipKindAnnotation:
+	case !opts.GroupVersionKind.Empty():
+		kind = opts.GroupVersionKind.String()
+	default:
+		kind = kindAnnotationValue(vObj)
+	}
+
+	retMap := hostAnnotations(vObj, pObj, kind, opts.Excluded...)
+	if opts.SkipKindAnnotation {
+		delete(retMap, KindAnnotation)
+	}
+
+	return retMap
+}
+
+// HostAnnotationsWithUID is like HostAnnotations, but stores the given uid in the UIDAnnotation instead of
+// the uid of the virtual object. This keeps the host object correlated with the virtual object if the
+// virtual object is recreated, e.g. during a migration, see DeterministicUID.
+func HostAnnotationsWithUID(vObj, pObj client.Object, uid string, excluded ...string) map[string]string {
+	retMap := HostAnnotations(vObj, pObj, excluded...)
+	retMap[UIDAnnotation] = uid
+	return retMap
+}
+
+// DeterministicUID returns a stable uid for the given object that is derived from its kind, namespace and name
+// and therefore stays the same if the object is recreated.
+func DeterministicUID(obj client.Object) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(kindAnnotationValue(obj)+"/"+obj.GetNamespace()+"/"+obj.GetName())).String()
+}
+
+func hostAnnotations(vObj, pObj client.Object, kind string, excluded ...string) map[string]string {
+	excluded = append(excluded, NameAnnotation, HostNameAnnotation, HostNamespaceAnnotation, UIDAnnotation, KindAnnotation, NamespaceAnnotation, MarkerAnnotation, TranslateVersionAnnotation)
+	excluded = append(excluded, PreserveHostAnnotationKeys...)
 	toAnnotations := map[string]string{}
 	if pObj != nil {
 		toAnnotations = pObj.GetAnnotations()
@@ -122,13 +374,17 @@ func HostAnnotations(vObj, pObj client.Object, excluded ...string) map[string]st
 		}
 	}
 
-	retMap := applyAnnotations(vObj.GetAnnotations(), toAnnotations, excluded...)
-	addHostAnnotations(retMap, vObj, pObj)
+	retMap, _ := applyAnnotations(vObj.GetAnnotations(), toAnnotations, false, excluded...)
+	addHostAnnotationsWithKind(retMap, vObj, pObj, kind)
 
 	return retMap
 }
 
 func addHostAnnotations(retMap map[string]string, vObj, pObj client.Object) {
+	addHostAnnotationsWithKind(retMap, vObj, pObj, kindAnnotationValue(vObj))
+}
+
+func addHostAnnotationsWithKind(retMap map[string]string, vObj, pObj client.Object, kind string) {
 	retMap[NameAnnotation] = vObj.GetName()
 	retMap[UIDAnnotation] = string(vObj.GetUID())
 	if pObj != nil {
@@ -143,10 +399,226 @@ func addHostAnnotations(retMap map[string]string, vObj, pObj client.Object) {
 		retMap[NamespaceAnnotation] = vObj.GetNamespace()
 	}
 
-	gvk, err := apiutil.GVKForObject(vObj, scheme.Scheme)
-	if err == nil {
-		retMap[KindAnnotation] = gvk.String()
+	// the marker is stored in the labels by default, see HostLabelsMap
+	switch {
+	case !UseAnnotationsForTopology:
+		delete(retMap, MarkerAnnotation)
+	case vObj.GetNamespace() == "":
+		retMap[MarkerAnnotation] = Default.MarkerLabelCluster()
+	default:
+		retMap[MarkerAnnotation] = VClusterName
+	}
+
+	if kind != "" {
+		retMap[KindAnnotation] = kind
+	}
+	retMap[TranslateVersionAnnotation] = strconv.Itoa(appliedTranslateVersion())
+}
+
+// kindAnnotationValue returns the value of the KindAnnotation for the given object or an empty string
+// if the kind can't be resolved
+func kindAnnotationValue(obj client.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		return ""
+	}
+
+	return gvk.String()
+}
+
+// GetMarker returns the marker of the given host object. If UseAnnotationsForTopology is set, the marker is read
+// from the MarkerAnnotation and falls back to the MarkerLabel for objects that were synced before.
+func GetMarker(pObj client.Object) string {
+	if UseAnnotationsForTopology && pObj.GetAnnotations()[MarkerAnnotation] != "" {
+		return pObj.GetAnnotations()[MarkerAnnotation]
+	}
+
+	return pObj.GetLabels()[MarkerLabel]
+}
+
+// VirtualNamespaceFromHostObject returns the virtual namespace of the given host object. It reads
+// the NamespaceLabel and falls back to the NamespaceAnnotation, which is also set when
+// UseAnnotationsForTopology is enabled.
+func VirtualNamespaceFromHostObject(pObj client.Object) (string, bool) {
+	if vNamespace := pObj.GetLabels()[NamespaceLabel]; vNamespace != "" {
+		return vNamespace, true
+	}
+	if vNamespace := pObj.GetAnnotations()[NamespaceAnnotation]; vNamespace != "" {
+		return vNamespace, true
+	}
+
+	return "", false
+}
+
+// HostFieldSelector translates the metadata.name and metadata.namespace requirements of the given field selector to
+// the host names. Names are translated within the namespace of the metadata.namespace requirement or vNamespace, names
+// of cluster scoped objects are translated if both are empty. Requirements on other fields are kept as they are.
+func HostFieldSelector(ctx *synccontext.SyncContext, fs fields.Selector, vNamespace string) fields.Selector {
+	if fs == nil {
+		return nil
+	}
+
+	requirements := fs.Requirements()
+	for _, requirement := range requirements {
+		if requirement.Field == "metadata.namespace" && requirement.Operator != selection.NotEquals {
+			vNamespace = requirement.Value
+		}
+	}
+
+	selectors := make([]fields.Selector, 0, len(requirements))
+	for _, requirement := range requirements {
+		value := requirement.Value
+		switch requirement.Field {
+		case "metadata.name":
+			if vNamespace == "" {
+				value = Default.HostNameCluster(value)
+			} else {
+				value = Default.HostName(ctx, value, vNamespace).Name
+			}
+		case "metadata.namespace":
+			value = Default.HostNamespace(ctx, value)
+		}
+
+		if requirement.Operator == selection.NotEquals {
+			selectors = append(selectors, fields.OneTermNotEqualSelector(requirement.Field, value))
+		} else {
+			selectors = append(selectors, fields.OneTermEqualSelector(requirement.Field, value))
+		}
+	}
+
+	return fields.AndSelectors(selectors...)
+}
+
+// IsClusterScoped returns true if the given object is cluster scoped. The scope is looked up via the rest mapping of
+// the virtual client of the context, because the namespace of unstructured objects or of objects that are still being
+// created may be empty. Without a context or a mapping for the object, objects without a namespace are cluster scoped.
+func IsClusterScoped(ctx *synccontext.SyncContext, obj client.Object) bool {
+	if ctx != nil && ctx.VirtualClient != nil {
+		namespaced, err := ctx.VirtualClient.IsObjectNamespaced(obj)
+		if err == nil {
+			return !namespaced
+		}
+	}
+
+	return obj.GetNamespace() == ""
+}
+
+// RoundTripName translates the virtual name with the Default translator and tries to recover the virtual name from
+// the host name alone. It returns true if the translation is lossless for the given name, which is not the case for
+// host names that were hashed or that can't be split unambiguously in single namespace mode. Host objects with lossy
+// names can only be mapped back through the mapping store or the annotations, see ResolveHostName.
+func RoundTripName(ctx *synccontext.SyncContext, vName, vNamespace string) (hostName string, recoveredVName string, lossless bool) {
+	hostName = Default.HostName(ctx, vName, vNamespace).Name
+	if !Default.SingleNamespaceTarget() {
+		return hostName, hostName, hostName == vName
+	}
+
+	// single namespace host names are name-x-namespace-x-suffix, unless they were too long and hashed
+	rest, ok := strings.CutSuffix(hostName, "-x-"+VClusterName)
+	if !ok || strings.Count(rest, "-x-") != 1 {
+		return hostName, "", false
+	}
+
+	recoveredVName, recoveredVNamespace, _ := strings.Cut(rest, "-x-")
+	if SingleNamespaceHostName(recoveredVName, recoveredVNamespace, VClusterName) != hostName {
+		return hostName, "", false
+	}
+
+	return hostName, recoveredVName, recoveredVName == vName && recoveredVNamespace == vNamespace
+}
+
+// ResolveHostName returns the name of the virtual object the given host object was synced from. It reads the
+// NameAnnotation and NamespaceAnnotation that are recorded during the sync, so it also works for truncated and
+// hashed host names. It returns false if the annotations are missing or, if the kind has a mapper in ctx, the
+// annotations don't translate back to the name of the host object.
+func ResolveHostName(ctx *synccontext.SyncContext, hostObj client.Object) (types.NamespacedName, bool) {
+	annotations := hostObj.GetAnnotations()
+	vName := types.NamespacedName{Name: annotations[NameAnnotation], Namespace: annotations[NamespaceAnnotation]}
+	if vName.Name == "" {
+		return types.NamespacedName{}, false
+	} else if vName.Namespace == "" && hostObj.GetNamespace() != "" {
+		vName.Namespace, _ = VirtualNamespaceFromHostObject(hostObj)
+	}
+
+	// make sure the annotations weren't copied from another object
+	if ctx != nil && ctx.Mappings != nil {
+		gvk, err := apiutil.GVKForObject(hostObj, scheme.Scheme)
+		if err == nil && ctx.Mappings.Has(gvk) && hostObj.GetName() != mappings.VirtualToHostName(ctx, vName.Name, vName.Namespace, gvk) {
+			return types.NamespacedName{}, false
+		}
+	}
+
+	return vName, true
+}
+
+// TranslateVersion returns the version of the translation algorithm the given host object was
+// written with. It returns false for objects that were synced before the version was recorded.
+func TranslateVersion(pObj client.Object) (int, bool) {
+	version, err := strconv.Atoi(pObj.GetAnnotations()[TranslateVersionAnnotation])
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// InformerNamespaces returns the sorted and deduplicated host namespaces a syncer needs to watch
+// for the given virtual namespaces, including the namespace vCluster itself is running in.
+func InformerNamespaces(ctx *synccontext.SyncContext, translator Translator, vNamespaces []string) []string {
+	namespaces := []string{}
+	if ctx.CurrentNamespace != "" {
+		namespaces = append(namespaces, ctx.CurrentNamespace)
+	}
+	for _, vNamespace := range vNamespaces {
+		if pNamespace := translator.HostNamespace(ctx, vNamespace); pNamespace != "" {
+			namespaces = append(namespaces, pNamespace)
+		}
 	}
+
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
+
+// ValidateHostNamespace returns an error if the virtual namespace is mapped to the namespace the vCluster control plane
+// is running in while namespaces are synced to the host. pNamespace is the host namespace the namespace mapper returns
+// for the virtual namespace. Synced workloads would otherwise end up next to the control plane, which is almost always
+// a misconfiguration. Set AllowHostNamespaceInCurrentNamespace to allow it.
+func ValidateHostNamespace(ctx *synccontext.SyncContext, vNamespace, pNamespace string) error {
+	if AllowHostNamespaceInCurrentNamespace || ctx.CurrentNamespace == "" {
+		return nil
+	}
+
+	if pNamespace == ctx.CurrentNamespace {
+		return fmt.Errorf("virtual namespace %s is mapped to host namespace %s, which is the namespace vCluster is running in", vNamespace, pNamespace)
+	}
+
+	return nil
+}
+
+// ValidateHostNamespaceOwner returns an error if the host namespace the virtual namespace is mapped to already exists
+// but was synced from a different virtual namespace. Objects of both virtual namespaces would otherwise silently end up
+// in the same host namespace.
+func ValidateHostNamespaceOwner(ctx *synccontext.SyncContext, vNamespace, pNamespace string) error {
+	if ctx.HostClient == nil || pNamespace == "" {
+		return nil
+	}
+
+	pObj := &corev1.Namespace{}
+	err := ctx.HostClient.Get(ctx, types.NamespacedName{Name: pNamespace}, pObj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("get host namespace %s: %w", pNamespace, err)
+	}
+
+	owner, ok := pObj.GetAnnotations()[NameAnnotation]
+	if ok && owner != vNamespace {
+		return fmt.Errorf("virtual namespace %s is mapped to host namespace %s, which is already used by virtual namespace %s", vNamespace, pNamespace, owner)
+	}
+
+	return nil
 }
 
 func ShouldDeleteHostObject(pObj client.Object) bool {
@@ -180,12 +652,92 @@ func ShouldDeleteHostObject(pObj client.Object) bool {
 	return true
 }
 
+// ValidateHostObjectAnnotations checks that the annotations vCluster sets on a host object via
+// HostAnnotations are present and consistent with the host object itself. Syncers can use this
+// to detect host objects that were tampered with.
+func ValidateHostObjectAnnotations(pObj client.Object) error {
+	annotations := pObj.GetAnnotations()
+	if annotations[NameAnnotation] == "" {
+		return fmt.Errorf("host object %s is missing annotation %s", pObj.GetName(), NameAnnotation)
+	} else if annotations[UIDAnnotation] == "" {
+		return fmt.Errorf("host object %s is missing annotation %s", pObj.GetName(), UIDAnnotation)
+	}
+
+	if annotations[HostNameAnnotation] != "" && annotations[HostNameAnnotation] != pObj.GetName() {
+		return fmt.Errorf("host object %s has annotation %s with unexpected value %s", pObj.GetName(), HostNameAnnotation, annotations[HostNameAnnotation])
+	}
+
+	// check namespace
+	if pObj.GetNamespace() == "" {
+		if annotations[NamespaceAnnotation] != "" {
+			return fmt.Errorf("cluster scoped host object %s has unexpected annotation %s", pObj.GetName(), NamespaceAnnotation)
+		}
+	} else {
+		if annotations[NamespaceAnnotation] == "" {
+			return fmt.Errorf("host object %s/%s is missing annotation %s", pObj.GetNamespace(), pObj.GetName(), NamespaceAnnotation)
+		} else if hostNamespace := Default.HostNamespace(nil, annotations[NamespaceAnnotation]); hostNamespace != pObj.GetNamespace() {
+			return fmt.Errorf("host object %s/%s has annotation %s=%s that translates to host namespace %s", pObj.GetNamespace(), pObj.GetName(), NamespaceAnnotation, annotations[NamespaceAnnotation], hostNamespace)
+		} else if annotations[HostNamespaceAnnotation] != "" && annotations[HostNamespaceAnnotation] != pObj.GetNamespace() {
+			return fmt.Errorf("host object %s/%s has annotation %s with unexpected value %s", pObj.GetNamespace(), pObj.GetName(), HostNamespaceAnnotation, annotations[HostNamespaceAnnotation])
+		}
+	}
+
+	// check kind
+	if annotations[KindAnnotation] == "" {
+		return nil
+	}
+	kindGVK, err := parseKindAnnotation(annotations[KindAnnotation])
+	if err != nil {
+		return fmt.Errorf("host object %s has invalid annotation %s: %w", pObj.GetName(), KindAnnotation, err)
+	}
+	gvk, err := apiutil.GVKForObject(pObj, scheme.Scheme)
+	if err == nil && gvk != kindGVK {
+		return fmt.Errorf("host object %s has annotation %s=%s, but is of kind %s", pObj.GetName(), KindAnnotation, annotations[KindAnnotation], gvk.String())
+	}
+
+	return nil
+}
+
+// parseKindAnnotation parses the value of the KindAnnotation, which is written via schema.GroupVersionKind.String()
+func parseKindAnnotation(value string) (schema.GroupVersionKind, error) {
+	groupVersion, kind, found := strings.Cut(value, ", Kind=")
+	if !found || kind == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("%q is not a valid group version kind", value)
+	}
+
+	gv, err := schema.ParseGroupVersion(groupVersion)
+	if err != nil || gv.Version == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("%q is not a valid group version kind", value)
+	}
+
+	return gv.WithKind(kind), nil
+}
+
+// GetOwnerReference returns the owner reference to the package level Owner. It is only a fallback for callers
+// without a SyncContext, syncers should use GetOwnerReferenceFor with the owner of their context, see OwnerFor.
 func GetOwnerReference(object client.Object) []metav1.OwnerReference {
-	if Owner == nil || Owner.GetName() == "" || Owner.GetUID() == "" {
+	return GetOwnerReferenceFor(Owner, object)
+}
+
+// GetOwnerReferenceFor returns the owner reference to the given owner for the given host object
+func GetOwnerReferenceFor(owner client.Object, object client.Object) []metav1.OwnerReference {
+	return GetOwnerReferenceWithOptions(owner, object, OwnerReferenceOptions{})
+}
+
+// OwnerReferenceOptions configure GetOwnerReferenceWithOptions
+type OwnerReferenceOptions struct {
+	// BlockOwnerDeletion sets BlockOwnerDeletion on controller references, so the owner is not deleted
+	// by the foreground garbage collection before the owned host object
+	BlockOwnerDeletion bool
+}
+
+// GetOwnerReferenceWithOptions is like GetOwnerReferenceFor, but allows to block the deletion of the owner
+func GetOwnerReferenceWithOptions(owner client.Object, object client.Object, opts OwnerReferenceOptions) []metav1.OwnerReference {
+	if owner == nil || owner.GetName() == "" || owner.GetUID() == "" {
 		return nil
 	}
 
-	typeAccessor, err := meta.TypeAccessor(Owner)
+	typeAccessor, err := meta.TypeAccessor(owner)
 	if err != nil || typeAccessor.GetAPIVersion() == "" || typeAccessor.GetKind() == "" {
 		return nil
 	}
@@ -195,24 +747,182 @@ func GetOwnerReference(object client.Object) []metav1.OwnerReference {
 		ctrl := metav1.GetControllerOf(object)
 		isController = ctrl != nil
 	}
-	return []metav1.OwnerReference{
-		{
-			APIVersion: typeAccessor.GetAPIVersion(),
-			Kind:       typeAccessor.GetKind(),
-			Name:       Owner.GetName(),
-			UID:        Owner.GetUID(),
-			Controller: &isController,
-		},
+	ownerReference := metav1.OwnerReference{
+		APIVersion: typeAccessor.GetAPIVersion(),
+		Kind:       typeAccessor.GetKind(),
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+		Controller: &isController,
+	}
+	if opts.BlockOwnerDeletion && isController {
+		ownerReference.BlockOwnerDeletion = ptr.To(true)
 	}
+
+	return []metav1.OwnerReference{ownerReference}
 }
 
+// TranslateOwnerReferencesToVirtual translates the owner references of the given host object to the virtual cluster.
+// Names are translated via the mapper of the owner kind and the uid is taken from the virtual owner. Owners without
+// a virtual counterpart, such as the vCluster owner itself, are dropped. Errors other than a missing virtual owner
+// are returned, so that owner references are not stripped from the virtual object because of a transient error.
+func TranslateOwnerReferencesToVirtual(ctx *synccontext.SyncContext, pObj client.Object) ([]metav1.OwnerReference, error) {
+	if len(pObj.GetOwnerReferences()) == 0 {
+		return nil, nil
+	} else if ctx == nil || ctx.Mappings == nil || ctx.VirtualClient == nil {
+		return nil, fmt.Errorf("translate owner references of %s: sync context has no mappings or virtual client", pObj.GetName())
+	}
+
+	owner := OwnerFor(ctx)
+	var vOwnerReferences []metav1.OwnerReference
+	for _, ownerReference := range pObj.GetOwnerReferences() {
+		if owner != nil && ownerReference.UID == owner.GetUID() {
+			continue
+		}
+
+		gvk := schema.FromAPIVersionAndKind(ownerReference.APIVersion, ownerReference.Kind)
+		mapper, err := ctx.Mappings.ByGVK(gvk)
+		if err != nil {
+			continue
+		}
+
+		// owners of cluster scoped kinds have no namespace
+		pName := types.NamespacedName{Name: ownerReference.Name, Namespace: pObj.GetNamespace()}
+		if namespaced, err := apiutil.IsGVKNamespaced(gvk, ctx.VirtualClient.RESTMapper()); err == nil && !namespaced {
+			pName.Namespace = ""
+		}
+
+		vName := mapper.HostToVirtual(ctx, pName, nil)
+		if vName.Name == "" {
+			continue
+		}
+
+		vOwner := &metav1.PartialObjectMetadata{}
+		vOwner.SetGroupVersionKind(gvk)
+		if err := ctx.VirtualClient.Get(ctx, vName, vOwner); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("get virtual owner %s %s: %w", gvk.Kind, vName.String(), err)
+		}
+
+		ownerReference.Name = vOwner.GetName()
+		ownerReference.UID = vOwner.GetUID()
+		vOwnerReferences = append(vOwnerReferences, ownerReference)
+	}
+
+	return vOwnerReferences, nil
+}
+
+// DefaultNameHashLength is the number of hex characters of the hash SafeConcatName appends to truncated names
+const DefaultNameHashLength = 10
+
+// maxNameLength is the maximum length of a DNS-1123 label
+const maxNameLength = 63
+
+// scopeLabelHashLength is the number of hex characters of the ScopeLabel value. Every virtual namespace of every
+// vCluster in a host namespace gets its own value, so it is longer than the hash of truncated names.
+const scopeLabelHashLength = 32
+
+// SafeConcatName joins the names with "-". Names longer than 63 characters are truncated to 52 characters
+// and suffixed with a hash of the full name, so names that only differ after the 52nd character stay unique.
+// The result never starts or ends with a non-alphanumeric character.
 func SafeConcatName(name ...string) string {
-	fullPath := strings.Join(name, "-")
-	if len(fullPath) > 63 {
-		digest := sha256.Sum256([]byte(fullPath))
-		return strings.ReplaceAll(fullPath[0:52]+"-"+hex.EncodeToString(digest[0:])[0:10], ".-", "-")
+	return SafeConcatNameWithHashLength(DefaultNameHashLength, name...)
+}
+
+// SafeConcatNameWithHashLength is like SafeConcatName, but appends hashLength hex characters of the hash to truncated
+// names. Every hex character adds 4 bits, so among n truncated names sharing the same prefix the probability of a
+// collision is roughly n^2 / 2^(4*hashLength+1), e.g. about 1e-4 for 10,000 names with the default of 10 characters
+// and about 1e-10 for 15 characters. hashLength is capped to 62, so the name stays within 63 characters, and values
+// below 1 use DefaultNameHashLength.
+func SafeConcatNameWithHashLength(hashLength int, name ...string) string {
+	return safeConcatName(strings.Join(name, "-"), "", hashLength)
+}
+
+// ValidateCompatibilityMode checks that mode is a translation algorithm version that is implemented.
+func ValidateCompatibilityMode(mode int) error {
+	if mode < TranslateVersionUnsalted || mode > CurrentTranslateVersion {
+		return fmt.Errorf("unsupported translate compatibility mode %d, must be between %d and %d", mode, TranslateVersionUnsalted, CurrentTranslateVersion)
+	}
+
+	return nil
+}
+
+// appliedTranslateVersion returns the version of the translation algorithm that is actually applied to host
+// names. Without a HashSalt the salted algorithm produces the same names as the unsalted one, so it is recorded
+// as TranslateVersionUnsalted.
+func appliedTranslateVersion() int {
+	if hashSalt() == "" {
+		return TranslateVersionUnsalted
+	}
+
+	return CompatibilityMode
+}
+
+// hashSalt returns the HashSalt if the CompatibilityMode mixes it into host names.
+func hashSalt() string {
+	return compatibleHashSalt(CompatibilityMode, HashSalt)
+}
+
+// compatibleHashSalt returns the salt if the given compatibility mode mixes it into host names.
+func compatibleHashSalt(mode int, salt string) string {
+	if mode < TranslateVersionSalted {
+		return ""
+	}
+
+	return salt
+}
+
+// safeConcatSaltedName is like SafeConcatName, but mixes the HashSalt into the hash of names that are too long.
+// It is only used for host names of synced objects, as other names such as the cluster marker label are also
+// calculated outside of the vCluster.
+func safeConcatSaltedName(name ...string) string {
+	return safeConcatName(strings.Join(name, "-"), hashSalt(), DefaultNameHashLength)
+}
+
+func safeConcatName(fullPath, salt string, hashLength int) string {
+	if hashLength < 1 {
+		hashLength = DefaultNameHashLength
+	}
+	hashLength = min(hashLength, maxNameLength-1)
+
+	if len(fullPath) > maxNameLength {
+		// sanitize the prefix before the hash is appended, so a dot at the cut doesn't end up next to the separator
+		prefix := strings.TrimRight(strings.ReplaceAll(fullPath[0:maxNameLength-1-hashLength], ".-", "-"), ".")
+		fullPath = prefix + "-" + shortHash(salt+fullPath, hashLength)
+	}
+
+	return strings.TrimFunc(fullPath, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	})
+}
+
+// hashCache caches the hex encoded sha256 digests by input. Long names and label keys are hashed again on
+// every reconcile, but there are only a few distinct ones per namespace.
+var hashCache, _ = lru.New[string, string](4096)
+
+// ResetHashCache clears the cached hashes of names and label keys
+func ResetHashCache() {
+	hashCache.Purge()
+}
+
+// shortHash returns the first length characters of the hex encoded sha256 digest of input.
+// All name and label key hashing should go through this function so host names stay stable.
+func shortHash(input string, length int) string {
+	digest, ok := hashCache.Get(input)
+	if !ok {
+		digest = sha256Hex(input)
+		hashCache.Add(input, digest)
 	}
-	return fullPath
+
+	return digest[0:length]
+}
+
+// sha256Hex returns the hex encoded sha256 digest of input
+func sha256Hex(input string) string {
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[0:])
 }
 
 func Split(s, sep string) (string, string) {
@@ -253,20 +963,42 @@ func ResetObjectMetadata(obj metav1.Object) {
 }
 
 func ApplyMetadata(fromAnnotations map[string]string, toAnnotations map[string]string, fromLabels map[string]string, toLabels map[string]string, excludeAnnotations ...string) (labels map[string]string, annotations map[string]string) {
-	mergedAnnotations := applyAnnotations(fromAnnotations, toAnnotations, excludeAnnotations...)
-	return applyLabels(fromLabels, toLabels, mergedAnnotations)
+	mergedAnnotations, _ := applyAnnotations(fromAnnotations, toAnnotations, false, excludeAnnotations...)
+	labels, annotations, _ = applyLabels(fromLabels, toLabels, mergedAnnotations, false)
+	return labels, annotations
+}
+
+// MetadataConflicts are the keys of the to side that were not managed by vCluster, but were overwritten by ApplyMetadataStrict
+type MetadataConflicts struct {
+	Labels      []string
+	Annotations []string
+}
+
+// Empty returns true if there are no conflicting keys
+func (m MetadataConflicts) Empty() bool {
+	return len(m.Labels) == 0 && len(m.Annotations) == 0
 }
 
-func applyAnnotations(fromAnnotations map[string]string, toAnnotations map[string]string, excludeAnnotations ...string) map[string]string {
+// ApplyMetadataStrict works like ApplyMetadata, but additionally returns the keys that were set on the to side by someone
+// else with a different value and are overwritten, so callers can warn about them.
+func ApplyMetadataStrict(fromAnnotations map[string]string, toAnnotations map[string]string, fromLabels map[string]string, toLabels map[string]string, excludeAnnotations ...string) (labels map[string]string, annotations map[string]string, conflicts MetadataConflicts) {
+	var mergedAnnotations map[string]string
+	mergedAnnotations, conflicts.Annotations = applyAnnotations(fromAnnotations, toAnnotations, true, excludeAnnotations...)
+	labels, annotations, conflicts.Labels = applyLabels(fromLabels, toLabels, mergedAnnotations, true)
+	return labels, annotations, conflicts
+}
+
+func applyAnnotations(fromAnnotations map[string]string, toAnnotations map[string]string, strict bool, excludeAnnotations ...string) (map[string]string, []string) {
 	if toAnnotations == nil {
 		toAnnotations = map[string]string{}
 	}
 
 	excludedKeys := []string{ManagedAnnotationsAnnotation, ManagedLabelsAnnotation}
 	excludedKeys = append(excludedKeys, excludeAnnotations...)
-	mergedAnnotations, managedKeys := applyMaps(fromAnnotations, toAnnotations, ApplyMapsOptions{
+	mergedAnnotations, managedKeys, conflicts := applyMaps(fromAnnotations, toAnnotations, ApplyMapsOptions{
 		ManagedKeys: strings.Split(toAnnotations[ManagedAnnotationsAnnotation], "\n"),
 		ExcludeKeys: excludedKeys,
+		Strict:      strict,
 	})
 	if managedKeys == "" {
 		delete(mergedAnnotations, ManagedAnnotationsAnnotation)
@@ -274,17 +1006,18 @@ func applyAnnotations(fromAnnotations map[string]string, toAnnotations map[strin
 		mergedAnnotations[ManagedAnnotationsAnnotation] = managedKeys
 	}
 
-	return mergedAnnotations
+	return mergedAnnotations, conflicts
 }
 
-func applyLabels(fromLabels map[string]string, toLabels map[string]string, toAnnotations map[string]string) (labels map[string]string, annotations map[string]string) {
+func applyLabels(fromLabels map[string]string, toLabels map[string]string, toAnnotations map[string]string, strict bool) (labels map[string]string, annotations map[string]string, conflicts []string) {
 	if toAnnotations == nil {
 		toAnnotations = map[string]string{}
 	}
 
-	mergedLabels, managedKeys := applyMaps(fromLabels, toLabels, ApplyMapsOptions{
+	mergedLabels, managedKeys, conflicts := applyMaps(fromLabels, toLabels, ApplyMapsOptions{
 		ManagedKeys: strings.Split(toAnnotations[ManagedLabelsAnnotation], "\n"),
 		ExcludeKeys: []string{ManagedAnnotationsAnnotation, ManagedLabelsAnnotation},
+		Strict:      strict,
 	})
 	mergedAnnotations := map[string]string{}
 	for k, v := range toAnnotations {
@@ -296,21 +1029,31 @@ func applyLabels(fromLabels map[string]string, toLabels map[string]string, toAnn
 		mergedAnnotations[ManagedLabelsAnnotation] = managedKeys
 	}
 
-	return mergedLabels, mergedAnnotations
+	return mergedLabels, mergedAnnotations, conflicts
 }
 
 type ApplyMapsOptions struct {
 	ManagedKeys []string
 	ExcludeKeys []string
+
+	// Strict returns the keys of the to map that were not managed before and are overwritten with a different value
+	Strict bool
 }
 
-func applyMaps(fromMap, toMap map[string]string, opts ApplyMapsOptions) (map[string]string, string) {
-	retMap := map[string]string{}
-	managedKeys := []string{}
+// applyMaps copies fromMap over toMap and returns the merged map, the newline separated managed keys and the conflicting
+// keys in strict mode. The managed keys and conflicts are sorted, so they are byte-stable for identical inputs regardless
+// of the map iteration order and do not cause spurious updates of synced objects.
+func applyMaps(fromMap, toMap map[string]string, opts ApplyMapsOptions) (map[string]string, string, []string) {
+	retMap := make(map[string]string, max(len(fromMap), len(toMap)))
+	managedKeys := make([]string, 0, len(fromMap))
+	var conflicts []string
 	for k, v := range fromMap {
 		if exists(opts.ExcludeKeys, k) {
 			continue
 		}
+		if toValue, ok := toMap[k]; opts.Strict && ok && toValue != v && !exists(opts.ManagedKeys, k) {
+			conflicts = append(conflicts, k)
+		}
 
 		retMap[k] = v
 		managedKeys = append(managedKeys, k)
@@ -328,14 +1071,54 @@ func applyMaps(fromMap, toMap map[string]string, opts ApplyMapsOptions) (map[str
 	}
 
 	sort.Strings(managedKeys)
+	sort.Strings(conflicts)
 	managedKeysStr := strings.Join(managedKeys, "\n")
-	return retMap, managedKeysStr
+	return retMap, managedKeysStr, conflicts
+}
+
+// MergeManagedKeyLists unions two newline separated managed keys lists as stored in the
+// ManagedAnnotationsAnnotation and ManagedLabelsAnnotation and returns them sorted.
+func MergeManagedKeyLists(a, b string) string {
+	keys := map[string]bool{}
+	for _, key := range append(strings.Split(a, "\n"), strings.Split(b, "\n")...) {
+		if key != "" {
+			keys[key] = true
+		}
+	}
+
+	managedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		managedKeys = append(managedKeys, key)
+	}
+	sort.Strings(managedKeys)
+	return strings.Join(managedKeys, "\n")
 }
 
 func hasStatus(version apiextensionsv1.CustomResourceDefinitionVersion) bool {
 	return version.Subresources != nil && version.Subresources.Status != nil
 }
 
+// FilterCRDVersions returns the served versions of a CRD without the versions named in
+// excludeVersions. It errors if none of the remaining versions is the storage version, as
+// the CRD could not be created in the virtual cluster otherwise.
+func FilterCRDVersions(versions []apiextensionsv1.CustomResourceDefinitionVersion, excludeVersions []string) ([]apiextensionsv1.CustomResourceDefinitionVersion, error) {
+	newVersions := []apiextensionsv1.CustomResourceDefinitionVersion{}
+	hasStorageVersion := false
+	for _, version := range versions {
+		if !version.Served || slices.Contains(excludeVersions, version.Name) {
+			continue
+		}
+
+		hasStorageVersion = hasStorageVersion || version.Storage
+		newVersions = append(newVersions, version)
+	}
+	if !hasStorageVersion {
+		return nil, fmt.Errorf("no served storage version left after excluding versions %v", excludeVersions)
+	}
+
+	return newVersions, nil
+}
+
 func getCrdVersionByName(crdVersions []apiextensionsv1.CustomResourceDefinitionVersion, versionName string) *apiextensionsv1.CustomResourceDefinitionVersion {
 	for _, version := range crdVersions {
 		if version.Name == versionName {
@@ -376,7 +1159,7 @@ func checkSubresourceStatus(ctx context.Context, vClient *apiextensionsv1clients
 	return isClusterScoped, hasStatusSubresource, err
 }
 
-func crdUpdateWithNewVersion(ctx context.Context, vClient *apiextensionsv1clientset.Clientset, pCrdDefinition, vCrdDefinition *apiextensionsv1.CustomResourceDefinition, groupVersionKind schema.GroupVersionKind) (bool, bool, error) {
+func crdUpdateWithNewVersion(ctx context.Context, vClient *apiextensionsv1clientset.Clientset, pCrdDefinition, vCrdDefinition *apiextensionsv1.CustomResourceDefinition, groupVersionKind schema.GroupVersionKind, options EnsureCRDOptions) (bool, bool, error) {
 	var err error
 	isClusterScoped := vCrdDefinition.Spec.Scope == apiextensionsv1.ClusterScoped
 	hasStatusSubresource := false
@@ -384,25 +1167,13 @@ func crdUpdateWithNewVersion(ctx context.Context, vClient *apiextensionsv1client
 	// CRD exists but with different version. Need to add the new version to it, and set as storage version if it is not already set.
 	klog.FromContext(ctx).Info("CRD found in virtual cluster, checking versions", "crd", vCrdDefinition.Name, "groupVersionKind", groupVersionKind)
 
-	newVersions := []apiextensionsv1.CustomResourceDefinitionVersion{}
-	for _, version := range vCrdDefinition.Spec.Versions {
-		if version.Name == groupVersionKind.Version {
-			continue
-		}
-		version.Storage = false
-		newVersions = append(newVersions, version)
-	}
-
 	// Version not found, we need to add it
 	klog.FromContext(ctx).Info("CRD version not found in virtual cluster, adding it", "version", groupVersionKind.Version, "crd", vCrdDefinition.Name)
-	newVersion := getCrdVersionByName(pCrdDefinition.Spec.Versions, groupVersionKind.Version)
-	if newVersion == nil {
-		err = fmt.Errorf("could not find version %q in physical CRD %q", groupVersionKind.Version, pCrdDefinition.Name)
+	newVersion, err := virtualCRDWithNewVersion(pCrdDefinition, vCrdDefinition, groupVersionKind, options)
+	if err != nil {
 		return isClusterScoped, hasStatusSubresource, err
 	}
-	newVersion.Storage = true
-	newVersions = append(newVersions, *newVersion)
-	vCrdDefinition.Spec.Versions = newVersions
+
 	// Update the CRD in the virtual cluster
 	klog.FromContext(ctx).Info("Updating CRD in virtual cluster with new version", "crd", vCrdDefinition.Name, "version", groupVersionKind.Version)
 	_, err = vClient.ApiextensionsV1().CustomResourceDefinitions().Update(ctx, vCrdDefinition, metav1.UpdateOptions{})
@@ -416,36 +1187,121 @@ func crdUpdateWithNewVersion(ctx context.Context, vClient *apiextensionsv1client
 	return isClusterScoped, hasStatusSubresource, err
 }
 
-func createCrdFromPhysicalCluster(ctx context.Context, vClient *apiextensionsv1clientset.Clientset, pCrdDefinition *apiextensionsv1.CustomResourceDefinition, groupVersionResource schema.GroupVersionResource, groupVersionKind schema.GroupVersionKind) (bool, bool, error) {
-	var err error
-	isClusterScoped := pCrdDefinition.Spec.Scope == apiextensionsv1.ClusterScoped
-	hasStatusSubresource := false
-
-	pCrdDefinition.UID = ""
-	pCrdDefinition.ResourceVersion = ""
-	pCrdDefinition.ManagedFields = nil
-	pCrdDefinition.OwnerReferences = nil
-	pCrdDefinition.Status = apiextensionsv1.CustomResourceDefinitionStatus{}
-	pCrdDefinition.Spec.PreserveUnknownFields = false
-	pCrdDefinition.Spec.Conversion = nil
+// virtualCRDWithNewVersion adds the requested version of the host crd to the given virtual crd and returns the added version
+func virtualCRDWithNewVersion(pCrdDefinition, vCrdDefinition *apiextensionsv1.CustomResourceDefinition, groupVersionKind schema.GroupVersionKind, options EnsureCRDOptions) (*apiextensionsv1.CustomResourceDefinitionVersion, error) {
+	newVersion := getCrdVersionByName(pCrdDefinition.Spec.Versions, groupVersionKind.Version)
+	if newVersion == nil {
+		return nil, fmt.Errorf("could not find version %q in physical CRD %q: %w", groupVersionKind.Version, pCrdDefinition.Name, ErrResourceNotInHost)
+	}
 
-	// make sure we only store the version we care about
-	newVersions := []apiextensionsv1.CustomResourceDefinitionVersion{}
-	for _, version := range pCrdDefinition.Spec.Versions {
-		if version.Name == groupVersionKind.Version {
-			version.Served = true
-			version.Storage = true
+	if options.AllVersions {
+		// take over the served versions and the storage version of the host, but keep versions only known to the virtual cluster
+		newVersions, err := virtualCRDVersions(pCrdDefinition, groupVersionKind.Version, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range vCrdDefinition.Spec.Versions {
+			if getCrdVersionByName(newVersions, version.Name) != nil {
+				continue
+			}
+			version.Storage = false
+			newVersions = append(newVersions, version)
+		}
+		vCrdDefinition.Spec.Versions = newVersions
+		vCrdDefinition.Spec.Conversion, err = virtualCRDConversion(pCrdDefinition, newVersions)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		newVersions := []apiextensionsv1.CustomResourceDefinitionVersion{}
+		for _, version := range vCrdDefinition.Spec.Versions {
+			if version.Name == groupVersionKind.Version {
+				continue
+			}
+			version.Storage = false
 			newVersions = append(newVersions, version)
-
-			hasStatusSubresource = hasStatus(version)
-			break
 		}
+		newVersion.Storage = true
+		newVersions = append(newVersions, *newVersion)
+		vCrdDefinition.Spec.Versions = newVersions
+	}
+
+	return newVersion, nil
+}
+
+var (
+	// ErrResourceNotInHost is returned by EnsureCRDFromPhysicalCluster if the requested resource or version is not
+	// available in the host cluster. This is usually a configuration error that is not fixed by retrying.
+	ErrResourceNotInHost = errors.New("resource is not available in the host cluster")
+
+	// ErrCRDNotEstablished is matched by a CRDNotEstablishedError
+	ErrCRDNotEstablished = errors.New("crd is not established")
+)
+
+// CRDNotEstablishedError is returned by EnsureCRDFromPhysicalCluster if the crd was created in the virtual cluster,
+// but did not become established in time.
+type CRDNotEstablishedError struct {
+	GroupVersionKind schema.GroupVersionKind
+
+	// Message is the last established condition of the crd
+	Message string
+
+	Err error
+}
+
+func (e *CRDNotEstablishedError) Error() string {
+	message := fmt.Sprintf("failed to wait for CRD %s to become ready: %v", e.GroupVersionKind.String(), e.Err)
+	if e.Message != "" {
+		message += fmt.Sprintf(" (last condition: %s)", e.Message)
+	}
+
+	return message
+}
+
+func (e *CRDNotEstablishedError) Is(target error) bool {
+	return target == ErrCRDNotEstablished
+}
+
+func (e *CRDNotEstablishedError) Unwrap() error {
+	return e.Err
+}
+
+// createCrdBackoff is the backoff used by createCrdWithRetry. Webhooks in the virtual cluster
+// usually take a few seconds to become available, so this retries for about 15 seconds.
+var createCrdBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Steps:    6,
+}
+
+// createCrdWithRetry retries the given crd creation if it failed because a webhook in the
+// virtual cluster is not available yet or because of a conflict. Other errors such as
+// validation errors are returned immediately.
+func createCrdWithRetry(create func() error) error {
+	return retry.OnError(createCrdBackoff, func(err error) bool {
+		return kerrors.IsServiceUnavailable(err) || isWebhookCallError(err) || kerrors.IsConflict(err)
+	}, create)
+}
+
+// isWebhookCallError returns true if the api server failed to call an admission webhook,
+// which it reports as an internal error
+func isWebhookCallError(err error) bool {
+	return kerrors.IsInternalError(err) && strings.Contains(err.Error(), "failed calling webhook")
+}
+
+func createCrdFromPhysicalCluster(ctx context.Context, vClient *apiextensionsv1clientset.Clientset, pCrdDefinition *apiextensionsv1.CustomResourceDefinition, groupVersionResource schema.GroupVersionResource, groupVersionKind schema.GroupVersionKind, options EnsureCRDOptions) (bool, bool, error) {
+	isClusterScoped := pCrdDefinition.Spec.Scope == apiextensionsv1.ClusterScoped
+	hasStatusSubresource, err := virtualCRDFromPhysicalCluster(pCrdDefinition, groupVersionKind, options)
+	if err != nil {
+		return isClusterScoped, hasStatusSubresource, err
 	}
-	pCrdDefinition.Spec.Versions = newVersions
 
 	// apply the crd
 	klog.FromContext(ctx).Info("Create crd in virtual cluster", "crd", groupVersionKind.String())
-	_, err = vClient.ApiextensionsV1().CustomResourceDefinitions().Create(ctx, pCrdDefinition, metav1.CreateOptions{})
+	err = createCrdWithRetry(func() error {
+		_, err := vClient.ApiextensionsV1().CustomResourceDefinitions().Create(ctx, pCrdDefinition, metav1.CreateOptions{})
+		return err
+	})
 	if err != nil && !kerrors.IsAlreadyExists(err) {
 		err = errors.Wrap(err, "create crd in virtual cluster")
 		return isClusterScoped, hasStatusSubresource, err
@@ -453,12 +1309,19 @@ func createCrdFromPhysicalCluster(ctx context.Context, vClient *apiextensionsv1c
 
 	// wait for crd to become ready
 	klog.FromContext(ctx).Info("Wait for crd to become ready in virtual cluster", "crd", groupVersionKind.String())
-	err = wait.ExponentialBackoffWithContext(ctx, wait.Backoff{Duration: time.Second, Factor: 1.5, Cap: time.Minute, Steps: math.MaxInt32}, func(ctx context.Context) (bool, error) {
+	waitCtx := ctx
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	message := ""
+	err = wait.ExponentialBackoffWithContext(waitCtx, wait.Backoff{Duration: time.Second, Factor: 1.5, Cap: time.Minute, Steps: math.MaxInt32}, func(ctx context.Context) (bool, error) {
 		crdDefinition, err := vClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, groupVersionResource.GroupResource().String(), metav1.GetOptions{})
 		if err != nil {
 			return false, errors.Wrap(err, "retrieve crd in virtual cluster")
 		}
-		message := ""
 		for _, cond := range crdDefinition.Status.Conditions {
 			if cond.Type == apiextensionsv1.Established && cond.Status == apiextensionsv1.ConditionTrue {
 				return true, nil
@@ -469,58 +1332,361 @@ func createCrdFromPhysicalCluster(ctx context.Context, vClient *apiextensionsv1c
 		klog.FromContext(ctx).Info("CRD is not ready yet", "crd", groupVersionKind.String(), "message", message)
 		return false, nil
 	})
-	if err != nil {
+	if wait.Interrupted(err) {
+		err = &CRDNotEstablishedError{GroupVersionKind: groupVersionKind, Message: message, Err: err}
+	} else if err != nil {
 		err = fmt.Errorf("failed to wait for CRD %s to become ready: %w", groupVersionKind.String(), err)
 	}
 	return isClusterScoped, hasStatusSubresource, err
 }
 
+// virtualCRDFromPhysicalCluster turns the given host crd into the crd that is created in the virtual cluster and
+// returns true if the requested version has a status subresource
+func virtualCRDFromPhysicalCluster(pCrdDefinition *apiextensionsv1.CustomResourceDefinition, groupVersionKind schema.GroupVersionKind, options EnsureCRDOptions) (bool, error) {
+	hasStatusSubresource := false
+
+	pCrdDefinition.UID = ""
+	pCrdDefinition.ResourceVersion = ""
+	pCrdDefinition.ManagedFields = nil
+	pCrdDefinition.OwnerReferences = nil
+	pCrdDefinition.Status = apiextensionsv1.CustomResourceDefinitionStatus{}
+	pCrdDefinition.Spec.PreserveUnknownFields = false
+	if pCrdDefinition.Annotations == nil {
+		pCrdDefinition.Annotations = map[string]string{}
+	}
+	pCrdDefinition.Annotations[ImportedFromHostAnnotation] = "true"
+
+	newVersions, err := virtualCRDVersions(pCrdDefinition, groupVersionKind.Version, options)
+	if err != nil {
+		return hasStatusSubresource, err
+	}
+	if newVersion := getCrdVersionByName(newVersions, groupVersionKind.Version); newVersion != nil {
+		hasStatusSubresource = hasStatus(*newVersion)
+	}
+	pCrdDefinition.Spec.Conversion, err = virtualCRDConversion(pCrdDefinition, newVersions)
+	if err != nil {
+		return hasStatusSubresource, err
+	}
+	pCrdDefinition.Spec.Versions = newVersions
+
+	return hasStatusSubresource, nil
+}
+
+// virtualCRDVersions returns the versions of the host crd that are created in the virtual cluster. By default only the
+// requested version is kept and marked as served and storage version. With AllVersions all served versions and the
+// storage version of the host crd are kept, except for the versions in ExcludeVersions, see FilterCRDVersions.
+func virtualCRDVersions(pCrdDefinition *apiextensionsv1.CustomResourceDefinition, versionName string, options EnsureCRDOptions) ([]apiextensionsv1.CustomResourceDefinitionVersion, error) {
+	if slices.Contains(options.ExcludeVersions, versionName) {
+		return nil, fmt.Errorf("version %q of physical CRD %q is excluded", versionName, pCrdDefinition.Name)
+	}
+	if !options.AllVersions {
+		newVersions := []apiextensionsv1.CustomResourceDefinitionVersion{}
+		if version := getCrdVersionByName(pCrdDefinition.Spec.Versions, versionName); version != nil {
+			version.Served = true
+			version.Storage = true
+			newVersions = append(newVersions, *version)
+		}
+
+		return newVersions, nil
+	}
+
+	newVersions := []apiextensionsv1.CustomResourceDefinitionVersion{}
+	for _, version := range pCrdDefinition.Spec.Versions {
+		if version.Served || version.Storage {
+			newVersions = append(newVersions, version)
+		}
+	}
+	if version := getCrdVersionByName(newVersions, versionName); version == nil || !version.Served {
+		return nil, fmt.Errorf("version %q is not served by physical CRD %q: %w", versionName, pCrdDefinition.Name, ErrResourceNotInHost)
+	}
+	if len(options.ExcludeVersions) > 0 {
+		return FilterCRDVersions(newVersions, options.ExcludeVersions)
+	}
+
+	return newVersions, nil
+}
+
+// virtualCRDConversion returns the conversion for the given versions of the host crd in the virtual cluster. A single
+// version needs no conversion, so the None strategy is used. Conversion webhooks that are referenced by a service are
+// rewritten to the url of the service in the host cluster, which the virtual cluster api server can reach.
+func virtualCRDConversion(pCrdDefinition *apiextensionsv1.CustomResourceDefinition, versions []apiextensionsv1.CustomResourceDefinitionVersion) (*apiextensionsv1.CustomResourceConversion, error) {
+	conversion := pCrdDefinition.Spec.Conversion
+	if len(versions) <= 1 || conversion == nil || conversion.Strategy == apiextensionsv1.NoneConverter {
+		return &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}, nil
+	} else if conversion.Strategy != apiextensionsv1.WebhookConverter {
+		return nil, fmt.Errorf("conversion strategy %q of physical CRD %q is not supported in the virtual cluster", conversion.Strategy, pCrdDefinition.Name)
+	} else if conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+		return nil, fmt.Errorf("conversion webhook of physical CRD %q has no client config", pCrdDefinition.Name)
+	}
+
+	webhook := conversion.Webhook.DeepCopy()
+	if service := webhook.ClientConfig.Service; service != nil {
+		port := int32(443)
+		if service.Port != nil {
+			port = *service.Port
+		}
+		path := ""
+		if service.Path != nil {
+			path = *service.Path
+		}
+
+		url := fmt.Sprintf("https://%s.%s.svc:%d%s", service.Name, service.Namespace, port, path)
+		webhook.ClientConfig.Service = nil
+		webhook.ClientConfig.URL = &url
+	}
+
+	return &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.WebhookConverter, Webhook: webhook}, nil
+}
+
+// EnsureCRDFromPhysicalCluster makes sure the crd of the given group version kind exists in the virtual cluster.
+// Only the requested version is copied from the host crd, see EnsureCRDFromPhysicalClusterAllVersions to copy all
+// served versions.
 func EnsureCRDFromPhysicalCluster(ctx context.Context, pConfig *rest.Config, vConfig *rest.Config, groupVersionKind schema.GroupVersionKind) (bool, bool, error) {
-	var isClusterScoped, hasStatusSubresource bool
+	return EnsureCRDFromPhysicalClusterWithOptions(ctx, pConfig, vConfig, groupVersionKind, EnsureCRDOptions{})
+}
+
+// EnsureCRDFromPhysicalClusterAllVersions makes sure the crd of the given group version kind exists in the virtual
+// cluster with all versions that are served in the host cluster. The storage version and the conversion of the host
+// crd are kept, see virtualCRDConversion.
+func EnsureCRDFromPhysicalClusterAllVersions(ctx context.Context, pConfig *rest.Config, vConfig *rest.Config, groupVersionKind schema.GroupVersionKind) (bool, bool, error) {
+	return EnsureCRDFromPhysicalClusterWithOptions(ctx, pConfig, vConfig, groupVersionKind, EnsureCRDOptions{AllVersions: true})
+}
+
+// EnsureCRDOptions configure EnsureCRDFromPhysicalClusterWithOptions
+type EnsureCRDOptions struct {
+	// AllVersions copies all served versions of the host crd, see EnsureCRDFromPhysicalClusterAllVersions
+	AllVersions bool
+
+	// ExcludeVersions are versions of the host crd that are not copied with AllVersions. The requested version and
+	// the storage version of the host crd can't be excluded. Versions that already exist in the virtual crd are kept.
+	ExcludeVersions []string
+
+	// Timeout bounds the wait for a created crd to become established. A CRDNotEstablishedError is returned
+	// if it is exceeded. Zero waits until the context is done.
+	Timeout time.Duration
+}
+
+// EnsureCRDFromPhysicalClusterWithOptions makes sure the crd of the given group version kind exists in the virtual cluster
+func EnsureCRDFromPhysicalClusterWithOptions(ctx context.Context, pConfig *rest.Config, vConfig *rest.Config, groupVersionKind schema.GroupVersionKind, options EnsureCRDOptions) (bool, bool, error) {
+	lookup, err := lookupCRD(ctx, pConfig, vConfig, groupVersionKind)
+	if err != nil {
+		return false, false, err
+	}
+
+	switch lookup.action() {
+	case CRDPlanNone: // CRD exists in the physical cluster and in the virtual cluster with the same GVK
+		return checkSubresourceStatus(ctx, lookup.vClient, lookup.apiResource, groupVersionKind)
+	case CRDPlanUpdate: // CRD exists in the virtual cluster but needs an update to add the new version
+		return crdUpdateWithNewVersion(ctx, lookup.vClient, lookup.pCrdDefinition, lookup.vCrdDefinition, groupVersionKind, options)
+	default: // CRD does not exist in the virtual cluster, need to create it
+		return createCrdFromPhysicalCluster(ctx, lookup.vClient, lookup.pCrdDefinition, lookup.groupVersionResource, groupVersionKind, options)
+	}
+}
+
+const (
+	// CRDPlanCreate means the crd does not exist in the virtual cluster and would be created
+	CRDPlanCreate = "create"
+	// CRDPlanUpdate means the crd exists in the virtual cluster and the requested version would be added
+	CRDPlanUpdate = "update"
+	// CRDPlanNone means the crd already exists in the virtual cluster with the requested version
+	CRDPlanNone = "none"
+)
+
+// PlanCRDFromPhysicalCluster returns what EnsureCRDFromPhysicalCluster would do for the given group version kind without
+// changing the virtual cluster. The action is one of CRDPlanCreate, CRDPlanUpdate or CRDPlanNone and the crd is the object
+// that would be created or updated in the virtual cluster. For CRDPlanNone the existing crd is returned, which is nil
+// if the kind is not served by a crd in the virtual cluster.
+func PlanCRDFromPhysicalCluster(ctx context.Context, pConfig, vConfig *rest.Config, groupVersionKind schema.GroupVersionKind) (string, *apiextensionsv1.CustomResourceDefinition, error) {
+	lookup, err := lookupCRD(ctx, pConfig, vConfig, groupVersionKind)
+	if err != nil {
+		return "", nil, err
+	}
+
+	action := lookup.action()
+	switch action {
+	case CRDPlanNone:
+		return action, lookup.vCrdDefinition, nil
+	case CRDPlanUpdate:
+		_, err = virtualCRDWithNewVersion(lookup.pCrdDefinition, lookup.vCrdDefinition, groupVersionKind, EnsureCRDOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+
+		return action, lookup.vCrdDefinition, nil
+	default:
+		_, err = virtualCRDFromPhysicalCluster(lookup.pCrdDefinition, groupVersionKind, EnsureCRDOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+
+		return action, lookup.pCrdDefinition, nil
+	}
+}
+
+// crdLookup holds the state of a crd in the host and the virtual cluster
+type crdLookup struct {
+	vClient *apiextensionsv1clientset.Clientset
+
+	groupVersionResource schema.GroupVersionResource
+	apiResource          metav1.APIResource
+
+	pCrdDefinition *apiextensionsv1.CustomResourceDefinition
+
+	// vCrdDefinition is nil if the crd does not exist in the virtual cluster
+	vCrdDefinition *apiextensionsv1.CustomResourceDefinition
+
+	// exactMatchInVCluster is true if the virtual cluster already serves the group version kind
+	exactMatchInVCluster bool
+}
+
+func (l *crdLookup) action() string {
+	switch {
+	case l.exactMatchInVCluster:
+		return CRDPlanNone
+	case l.vCrdDefinition != nil:
+		return CRDPlanUpdate
+	default:
+		return CRDPlanCreate
+	}
+}
 
+// lookupCRD retrieves the crd of the given group version kind from the host and the virtual cluster
+func lookupCRD(ctx context.Context, pConfig *rest.Config, vConfig *rest.Config, groupVersionKind schema.GroupVersionKind) (*crdLookup, error) {
 	vClient, err := apiextensionsv1clientset.NewForConfig(vConfig)
 	if err != nil {
-		return isClusterScoped, hasStatusSubresource, err
+		return nil, err
 	}
 	pClient, err := apiextensionsv1clientset.NewForConfig(pConfig)
 	if err != nil {
-		return isClusterScoped, hasStatusSubresource, err
+		return nil, err
 	}
 
 	// get resource from kind name in physical cluster
 	groupVersionResource, err := ConvertKindToResource(pConfig, groupVersionKind)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			return isClusterScoped, hasStatusSubresource, fmt.Errorf("seems like resource %s is not available in the physical cluster or vcluster has no access to it", groupVersionKind.String())
+			return nil, fmt.Errorf("seems like resource %s is not available in the physical cluster or vcluster has no access to it: %w", groupVersionKind.String(), ErrResourceNotInHost)
 		}
-		return isClusterScoped, hasStatusSubresource, err
+		return nil, err
 	}
 
 	pCrdDefinition, err := pClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, groupVersionResource.GroupResource().String(), metav1.GetOptions{})
 	if err != nil {
-		return isClusterScoped, hasStatusSubresource, errors.Wrap(err, "retrieve crd in host cluster")
+		return nil, errors.Wrap(err, "retrieve crd in host cluster")
 	}
 
 	vCrdDefinition, err := vClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, groupVersionResource.GroupResource().String(), metav1.GetOptions{})
 	if err != nil && !kerrors.IsNotFound(err) {
-		return isClusterScoped, hasStatusSubresource, fmt.Errorf("retrieve crd in virtual cluster: %w", err)
+		return nil, fmt.Errorf("retrieve crd in virtual cluster: %w", err)
+	} else if err != nil {
+		vCrdDefinition = nil
 	}
-	vCrdExists := err == nil
 
 	apiResource, err := KindExists(vConfig, groupVersionKind)
 	if err != nil && !kerrors.IsNotFound(err) { // If the kind does not exist, we will create it in the virtual cluster
-		return isClusterScoped, hasStatusSubresource, fmt.Errorf("check virtual cluster kind: %w", err)
+		return nil, fmt.Errorf("check virtual cluster kind: %w", err)
 	}
-	exactMatchInVCluster := err == nil
 
-	switch {
-	case exactMatchInVCluster: // CRD exists in the physical cluster and in the virtual cluster with the same GVK
-		return checkSubresourceStatus(ctx, vClient, apiResource, groupVersionKind)
-	case vCrdExists: // CRD exists in the virtual cluster but needs an update to add the new version
-		return crdUpdateWithNewVersion(ctx, vClient, pCrdDefinition, vCrdDefinition, groupVersionKind)
-	default: // CRD does not exist in the virtual cluster, need to create it
-		return createCrdFromPhysicalCluster(ctx, vClient, pCrdDefinition, groupVersionResource, groupVersionKind)
+	return &crdLookup{
+		vClient:              vClient,
+		groupVersionResource: groupVersionResource,
+		apiResource:          apiResource,
+		pCrdDefinition:       pCrdDefinition,
+		vCrdDefinition:       vCrdDefinition,
+		exactMatchInVCluster: err == nil,
+	}, nil
+}
+
+// ListImportedCRDs returns the group version kinds of all crds in the virtual cluster that were
+// imported from the host cluster by EnsureCRDFromPhysicalCluster.
+func ListImportedCRDs(ctx context.Context, vConfig *rest.Config) ([]schema.GroupVersionKind, error) {
+	vClient, err := apiextensionsv1clientset.NewForConfig(vConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	crdList, err := vClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list crds in virtual cluster: %w", err)
+	}
+
+	return importedCRDGroupVersionKinds(crdList.Items), nil
+}
+
+// RemoveCRDFromVirtualCluster deletes the crd of the given group version kind from the virtual cluster and waits until it
+// is gone. Only crds that were imported by EnsureCRDFromPhysicalCluster are deleted, and crds that do not exist are ignored.
+func RemoveCRDFromVirtualCluster(ctx context.Context, vConfig *rest.Config, groupVersionKind schema.GroupVersionKind) error {
+	vClient, err := apiextensionsv1clientset.NewForConfig(vConfig)
+	if err != nil {
+		return err
 	}
+
+	// get resource from kind name in virtual cluster
+	groupVersionResource, err := ConvertKindToResource(vConfig, groupVersionKind)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	crdName := groupVersionResource.GroupResource().String()
+	vCrdDefinition, err := vClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crdName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("retrieve crd in virtual cluster: %w", err)
+	} else if vCrdDefinition.Annotations[ImportedFromHostAnnotation] != "true" {
+		return fmt.Errorf("crd %s was not imported from the host cluster", crdName)
+	}
+
+	klog.FromContext(ctx).Info("Delete crd in virtual cluster", "crd", groupVersionKind.String())
+	err = vClient.ApiextensionsV1().CustomResourceDefinitions().Delete(ctx, crdName, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &vCrdDefinition.UID}})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("delete crd in virtual cluster: %w", err)
+	}
+
+	// wait for crd to be deleted
+	err = wait.ExponentialBackoffWithContext(ctx, wait.Backoff{Duration: time.Second, Factor: 1.5, Cap: time.Minute, Steps: math.MaxInt32}, func(ctx context.Context) (bool, error) {
+		_, err := vClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crdName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		} else if err != nil {
+			return false, fmt.Errorf("retrieve crd in virtual cluster: %w", err)
+		}
+
+		klog.FromContext(ctx).Info("CRD is not deleted yet", "crd", groupVersionKind.String())
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for CRD %s to be deleted: %w", groupVersionKind.String(), err)
+	}
+
+	return nil
+}
+
+func importedCRDGroupVersionKinds(crds []apiextensionsv1.CustomResourceDefinition) []schema.GroupVersionKind {
+	groupVersionKinds := []schema.GroupVersionKind{}
+	for _, crd := range crds {
+		if crd.Annotations[ImportedFromHostAnnotation] != "true" {
+			continue
+		}
+
+		for _, version := range crd.Spec.Versions {
+			if !version.Served {
+				continue
+			}
+
+			groupVersionKinds = append(groupVersionKinds, schema.GroupVersionKind{
+				Group:   crd.Spec.Group,
+				Version: version.Name,
+				Kind:    crd.Spec.Names.Kind,
+			})
+		}
+	}
+
+	return groupVersionKinds
 }
 
 func ConvertKindToResource(config *rest.Config, groupVersionKind schema.GroupVersionKind) (schema.GroupVersionResource, error) {
@@ -1,13 +1,44 @@
 package translate
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/mappings/store"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1clientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestVirtualLabels(t *testing.T) {
@@ -24,6 +55,46 @@ func TestVirtualLabels(t *testing.T) {
 	}, vMap)
 }
 
+func TestVirtualLabelsDroppedKeys(t *testing.T) {
+	// the release label was added directly on the host object and can't be translated back
+	vMap, droppedKeys := VirtualLabelsMapWithDroppedKeys(map[string]string{
+		"test":    "test",
+		"release": "host-release",
+	}, nil, LabelsOptions{})
+	assert.DeepEqual(t, map[string]string{
+		"test": "test",
+	}, vMap)
+	assert.DeepEqual(t, []string{"release"}, droppedKeys)
+
+	// a release label that is already on the virtual object is preserved and not dropped
+	vMap, droppedKeys = VirtualLabelsMapWithDroppedKeys(map[string]string{
+		"release": "host-release",
+	}, map[string]string{
+		"release": "virtual-release",
+	}, LabelsOptions{})
+	assert.DeepEqual(t, map[string]string{
+		"release": "virtual-release",
+	}, vMap)
+	assert.Equal(t, 0, len(droppedKeys))
+}
+
+func TestLabelsRoundTripHostOnlyLabels(t *testing.T) {
+	pLabels := map[string]string{
+		"test":    "test",
+		"release": "host-release",
+	}
+
+	// host -> virtual -> host must not add the host only release label under its translated key
+	vLabels := VirtualLabelsMap(pLabels, nil)
+	newPLabels := HostLabelsMap(vLabels, pLabels, "test", false)
+	_, ok := newPLabels[HostLabel("release")]
+	assert.Assert(t, !ok, "host only label %q was translated back to the host", "release")
+	assert.Equal(t, "test", newPLabels["test"])
+
+	// and the labels are stable on the next round trip
+	assert.DeepEqual(t, vLabels, VirtualLabelsMap(newPLabels, vLabels))
+}
+
 func TestNotRewritingLabels(t *testing.T) {
 	pMap := map[string]string{
 		"abc": "abc",
@@ -80,6 +151,7 @@ func TestAnnotations(t *testing.T) {
 		"test":                       "test",
 		ManagedAnnotationsAnnotation: "test",
 		KindAnnotation:               corev1.SchemeGroupVersion.WithKind("Secret").String(),
+		TranslateVersionAnnotation:   "1",
 		NameAnnotation:               "",
 		HostNameAnnotation:           "",
 		UIDAnnotation:                "",
@@ -98,12 +170,169 @@ func TestAnnotations(t *testing.T) {
 		"other":                      "other",
 		ManagedAnnotationsAnnotation: "other\ntest",
 		KindAnnotation:               corev1.SchemeGroupVersion.WithKind("Secret").String(),
+		TranslateVersionAnnotation:   "1",
 		NameAnnotation:               "",
 		HostNameAnnotation:           "",
 		UIDAnnotation:                "",
 	}, pObj.Annotations)
 }
 
+func TestHostAnnotationsWithOptions(t *testing.T) {
+	// the kind is not registered in the scheme and the object carries no kind
+	vObj := &unstructured.Unstructured{}
+	vObj.SetName("test")
+	vObj.SetNamespace("test")
+	vObj.SetAnnotations(map[string]string{"test": "test"})
+	_, ok := HostAnnotations(vObj, nil)[KindAnnotation]
+	assert.Assert(t, !ok)
+
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	annotations := HostAnnotationsWithOptions(vObj, nil, HostAnnotationsOptions{GroupVersionKind: gvk})
+	assert.Equal(t, annotations[KindAnnotation], gvk.String())
+	assert.Equal(t, annotations["test"], "test")
+	assert.Equal(t, annotations[NameAnnotation], "test")
+
+	// the kind annotation can be omitted entirely, even if the host object still has it
+	pObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-x-test", Namespace: "host", Annotations: map[string]string{KindAnnotation: gvk.String()}}}
+	annotations = HostAnnotationsWithOptions(vObj, pObj, HostAnnotationsOptions{GroupVersionKind: gvk, SkipKindAnnotation: true, Excluded: []string{"test"}})
+	_, ok = annotations[KindAnnotation]
+	assert.Assert(t, !ok)
+	_, ok = annotations["test"]
+	assert.Assert(t, !ok)
+
+	// without options the kind is resolved from the scheme
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	assert.DeepEqual(t, HostAnnotationsWithOptions(secret, nil, HostAnnotationsOptions{}), HostAnnotations(secret, nil))
+}
+
+func TestHostMetadataWithContextOwners(t *testing.T) {
+	newOwner := func(name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "host", UID: types.UID(name + "-uid")},
+		}
+	}
+
+	// both syncers translate concurrently with their own owners
+	wg := sync.WaitGroup{}
+	for _, owner := range []*corev1.Service{newOwner("owner-a"), newOwner("owner-b")} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx := &synccontext.SyncContext{Context: context.TODO(), Owner: owner}
+			for i := range 100 {
+				vObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-%d", i), Namespace: "test"}}
+				hostName := types.NamespacedName{Name: vObj.Name + "-x-test", Namespace: "host"}
+				for _, pObj := range []*corev1.ConfigMap{
+					HostMetadataWithContext(ctx, vObj, hostName),
+					CopyObjectWithContext(ctx, vObj, hostName, true),
+				} {
+					assert.Equal(t, len(pObj.OwnerReferences), 1)
+					assert.Equal(t, pObj.OwnerReferences[0].Name, owner.Name)
+					assert.Equal(t, pObj.OwnerReferences[0].UID, owner.UID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// without an owner in the context the package level owner is used
+	Owner = newOwner("global")
+	defer func() { Owner = nil }()
+	vObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	pObj := HostMetadataWithContext(&synccontext.SyncContext{}, vObj, types.NamespacedName{Name: "test-x-test", Namespace: "host"})
+	assert.Equal(t, pObj.OwnerReferences[0].Name, "global")
+	assert.DeepEqual(t, pObj.OwnerReferences, HostMetadataWithContext(nil, vObj, types.NamespacedName{Name: "test-x-test", Namespace: "host"}).OwnerReferences)
+
+	// the deprecated functions always use the package level owner
+	assert.DeepEqual(t, pObj.OwnerReferences, HostMetadata(vObj, types.NamespacedName{Name: "test-x-test", Namespace: "host"}).OwnerReferences)
+	assert.DeepEqual(t, pObj.OwnerReferences, CopyObjectWithName(vObj, types.NamespacedName{Name: "test-x-test", Namespace: "host"}, true).OwnerReferences)
+}
+
+func TestGetOwnerReferenceWithOptions(t *testing.T) {
+	owner := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "vcluster", Namespace: "host", UID: "owner-uid"},
+	}
+	controlled := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Name: "rs", UID: "rs-uid", Controller: ptr.To(true)}}}}
+	uncontrolled := &corev1.ConfigMap{}
+
+	// only controller references block the deletion of the owner
+	ownerReferences := GetOwnerReferenceWithOptions(owner, controlled, OwnerReferenceOptions{BlockOwnerDeletion: true})
+	assert.Equal(t, len(ownerReferences), 1)
+	assert.Assert(t, *ownerReferences[0].Controller)
+	assert.DeepEqual(t, ownerReferences[0].BlockOwnerDeletion, ptr.To(true))
+
+	ownerReferences = GetOwnerReferenceWithOptions(owner, uncontrolled, OwnerReferenceOptions{BlockOwnerDeletion: true})
+	assert.Assert(t, !*ownerReferences[0].Controller)
+	assert.Assert(t, ownerReferences[0].BlockOwnerDeletion == nil)
+
+	// the default is unchanged
+	assert.Assert(t, GetOwnerReferenceFor(owner, controlled)[0].BlockOwnerDeletion == nil)
+}
+
+func TestHostObjectMeta(t *testing.T) {
+	vObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test",
+		Namespace:   "test",
+		UID:         "uid",
+		Labels:      map[string]string{"app": "nginx"},
+		Annotations: map[string]string{"note": "synced", "excluded": "true"},
+	}}
+
+	meta := HostObjectMeta(nil, vObj, types.NamespacedName{Name: "test-x-test", Namespace: "host"}, "excluded")
+	pObj := HostMetadataWithContext(nil, vObj.DeepCopy(), types.NamespacedName{Name: "test-x-test", Namespace: "host"}, "excluded")
+	assert.DeepEqual(t, meta, pObj.ObjectMeta)
+	assert.Equal(t, meta.Labels[NamespaceLabel], "test")
+	assert.Equal(t, vObj.Annotations["excluded"], "true")
+
+	// the virtual metadata translates back
+	pObj.Annotations["host"] = "annotation"
+	vMeta := VirtualObjectMeta(nil, pObj, types.NamespacedName{Name: "test", Namespace: "test"})
+	assert.DeepEqual(t, vMeta, metav1.ObjectMeta{
+		Name:        "test",
+		Namespace:   "test",
+		Labels:      map[string]string{"app": "nginx"},
+		Annotations: map[string]string{"note": "synced", "host": "annotation"},
+	})
+
+	// cluster scoped objects have no namespace
+	vRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "role", Labels: map[string]string{"app": "nginx"}}}
+	meta = HostObjectMeta(nil, vRole, types.NamespacedName{Name: Default.HostNameCluster("role")})
+	assert.Equal(t, meta.Name, Default.HostNameCluster("role"))
+	assert.Equal(t, meta.Namespace, "")
+	assert.Equal(t, meta.Labels[MarkerLabel], Default.MarkerLabelCluster())
+	_, ok := meta.Annotations[NamespaceAnnotation]
+	assert.Assert(t, !ok)
+
+	vMeta = VirtualObjectMeta(nil, &rbacv1.ClusterRole{ObjectMeta: meta}, types.NamespacedName{Name: "role"})
+	assert.Equal(t, vMeta.Name, "role")
+	assert.Equal(t, vMeta.Namespace, "")
+	assert.DeepEqual(t, vMeta.Labels, map[string]string{"app": "nginx"})
+}
+
+func TestVirtualAnnotationsMap(t *testing.T) {
+	vObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Annotations: map[string]string{"virtual": "only", "excluded": "virtual"}}}
+	pObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-x-test", Namespace: "host"}}
+	pObj.Annotations = HostAnnotations(vObj, pObj)
+	pObj.Annotations["host"] = "annotation"
+	pObj.Annotations["excluded"] = "host"
+
+	// the map level translation matches the object level translation
+	vAnnotations := VirtualAnnotationsMap(pObj.Annotations, vObj.Annotations, "excluded")
+	assert.DeepEqual(t, vAnnotations, VirtualAnnotations(pObj, vObj, "excluded"))
+	assert.DeepEqual(t, vAnnotations, map[string]string{
+		"virtual":  "only",
+		"host":     "annotation",
+		"excluded": "virtual",
+	})
+
+	// without virtual annotations, e.g. for a new pod template
+	assert.DeepEqual(t, VirtualAnnotationsMap(map[string]string{"host": "annotation", NameAnnotation: "test"}, nil), map[string]string{"host": "annotation"})
+	assert.DeepEqual(t, VirtualAnnotationsMap(nil, nil), map[string]string{})
+}
+
 func TestRecursiveLabelsMap(t *testing.T) {
 	vMap := map[string]string{
 		NamespaceLabel: "test",
@@ -213,3 +442,1423 @@ func TestLabelSelector(t *testing.T) {
 		},
 	}, pMap)
 }
+
+func TestShortHashGolden(t *testing.T) {
+	assert.Equal(t, shortHash("", 10), "e3b0c44298")
+	assert.Equal(t, shortHash("vcluster", 16), "f689954fb7af1363")
+}
+
+func TestSafeConcatNameGolden(t *testing.T) {
+	for _, tt := range []struct {
+		name     []string
+		expected string
+	}{
+		{
+			name:     []string{"short", "x", "default", "x", "suffix"},
+			expected: "short-x-default-x-suffix",
+		},
+		{
+			name:     []string{"a-very-long-pod-name-that-is-way-too-long-for-kubernetes", "x", "default", "x", "suffix"},
+			expected: "a-very-long-pod-name-that-is-way-too-long-for-kubern-5bbaf707da",
+		},
+		{
+			name:     []string{"vcluster", "my-cluster-role-with.a.very.long.name.that.exceeds.limits", "x", "vcluster", "x", "suffix"},
+			expected: "vcluster-my-cluster-role-with.a.very.long.name.that-8bc471bdc9",
+		},
+	} {
+		assert.Equal(t, SafeConcatName(tt.name...), tt.expected)
+	}
+}
+
+func TestSafeConcatNameWithHashLength(t *testing.T) {
+	longName := strings.Repeat("a-very-long-name.", 10)
+	assert.Equal(t, SafeConcatNameWithHashLength(DefaultNameHashLength, longName, "x", "default"), SafeConcatName(longName, "x", "default"))
+	assert.Equal(t, SafeConcatNameWithHashLength(0, longName), SafeConcatName(longName))
+	assert.Equal(t, SafeConcatNameWithHashLength(16, "short", "name"), "short-name")
+
+	name := SafeConcatNameWithHashLength(16, longName)
+	assert.Equal(t, name, longName[0:46]+"-"+shortHash(longName, 16))
+
+	for hashLength := -1; hashLength <= 70; hashLength++ {
+		for _, input := range []string{longName, strings.Repeat("a", 64), strings.Repeat(".", 64)} {
+			name := SafeConcatNameWithHashLength(hashLength, input)
+			assert.Assert(t, len(name) <= 63, "hash length %d produced %s", hashLength, name)
+			assert.Assert(t, len(name) > 0, "hash length %d produced an empty name", hashLength)
+		}
+	}
+}
+
+func TestSafeConcatNameDNSLabel(t *testing.T) {
+	prefix50 := strings.Repeat("a", 50)
+	for _, tt := range []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "dot at position 52",
+			input:    []string{prefix50 + "b.cdefghijklmnop", "x", "default"},
+			expected: prefix50 + "b-" + shortHash(prefix50+"b.cdefghijklmnop-x-default", 10),
+		},
+		{
+			name:     "dots at position 51 and 52",
+			input:    []string{prefix50 + "..cdefghijklmnop", "x", "default"},
+			expected: prefix50 + "-" + shortHash(prefix50+"..cdefghijklmnop-x-default", 10),
+		},
+		{
+			// the double dash is kept, so existing host names don't change
+			name:     "dot and dash at position 51 and 52",
+			input:    []string{prefix50 + ".-cdefghijklmnop", "x", "default"},
+			expected: prefix50 + "--" + shortHash(prefix50+".-cdefghijklmnop-x-default", 10),
+		},
+		{
+			name:     "leading dash",
+			input:    []string{"-" + prefix50 + "bcdefghijklmnop", "x", "default"},
+			expected: prefix50 + "b-" + shortHash("-"+prefix50+"bcdefghijklmnop-x-default", 10),
+		},
+		{
+			name:     "short name with trailing dash",
+			input:    []string{"name", ""},
+			expected: "name",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			name := SafeConcatName(tt.input...)
+			assert.Equal(t, name, tt.expected)
+			assert.Assert(t, len(name) <= 63)
+			assert.Assert(t, len(validation.IsDNS1123Label(strings.ReplaceAll(name, ".", "-"))) == 0, name)
+		})
+	}
+}
+
+func TestHashSalt(t *testing.T) {
+	defer func() { HashSalt = "" }()
+
+	translator := NewSingleNamespaceTranslator("test")
+	longName := "a-very-long-pod-name-that-is-way-too-long-for-kubernetes"
+
+	// an empty salt keeps the existing host names
+	assert.Equal(t, translator.HostName(nil, longName, "default").Name, SafeConcatName(longName, "x", "default", "x", VClusterName))
+	assert.Equal(t, translator.HostName(nil, "short", "default").Name, "short-x-default-x-"+VClusterName)
+
+	hostNames := map[string]bool{}
+	shortHostNames := map[string]bool{}
+	for _, salt := range []string{"", "salt-a", "salt-b"} {
+		HashSalt = salt
+		hostNames[translator.HostName(nil, longName, "default").Name] = true
+		shortHostNames[translator.HostNameShort(nil, "short", "default").Name] = true
+
+		// host names are stable for the same salt
+		assert.Equal(t, translator.HostNameShort(nil, "short", "default"), translator.HostNameShort(nil, "short", "default"))
+		// names that are not hashed are not affected
+		assert.Equal(t, translator.HostName(nil, "short", "default").Name, "short-x-default-x-"+VClusterName)
+		// the cluster marker is not salted, as it's also calculated outside of the vCluster
+		assert.Equal(t, translator.MarkerLabelCluster(), SafeConcatName("test", "x", VClusterName))
+	}
+	assert.Equal(t, len(hostNames), 3)
+	assert.Equal(t, len(shortHostNames), 3)
+}
+
+func TestConvertLabelKeyWithPrefixGolden(t *testing.T) {
+	assert.Equal(t, convertLabelKeyWithPrefix(LabelPrefix, "release"), "vcluster.loft.sh/label-suffix-x-a4d451ec23")
+	assert.Equal(t, convertLabelKeyWithPrefix(NamespaceLabelPrefix, "kubernetes.io/metadata.name"), "vcluster.loft.sh/ns-label-suffix-x-cf1227b7b2")
+}
+
+func TestValidateHostObjectAnnotations(t *testing.T) {
+	defer func(translator Translator) { Default = translator }(Default)
+	Default = NewSingleNamespaceTranslator("host-namespace")
+
+	validAnnotations := func() map[string]string {
+		return map[string]string{
+			NameAnnotation:             "my-secret",
+			NamespaceAnnotation:        "default",
+			UIDAnnotation:              "1234",
+			KindAnnotation:             corev1.SchemeGroupVersion.WithKind("Secret").String(),
+			TranslateVersionAnnotation: "1",
+			HostNameAnnotation:         "my-secret-x-default-x-suffix",
+			HostNamespaceAnnotation:    "host-namespace",
+		}
+	}
+
+	for _, tt := range []struct {
+		name          string
+		modify        func(obj *corev1.Secret)
+		expectedError string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "missing name",
+			modify: func(obj *corev1.Secret) {
+				delete(obj.Annotations, NameAnnotation)
+			},
+			expectedError: "host object my-secret-x-default-x-suffix is missing annotation " + NameAnnotation,
+		},
+		{
+			name: "missing uid",
+			modify: func(obj *corev1.Secret) {
+				delete(obj.Annotations, UIDAnnotation)
+			},
+			expectedError: "host object my-secret-x-default-x-suffix is missing annotation " + UIDAnnotation,
+		},
+		{
+			name: "missing namespace",
+			modify: func(obj *corev1.Secret) {
+				delete(obj.Annotations, NamespaceAnnotation)
+			},
+			expectedError: "host object host-namespace/my-secret-x-default-x-suffix is missing annotation " + NamespaceAnnotation,
+		},
+		{
+			name: "host namespace mismatch",
+			modify: func(obj *corev1.Secret) {
+				obj.Namespace = "other"
+				obj.Annotations[HostNamespaceAnnotation] = "other"
+			},
+			expectedError: "host object other/my-secret-x-default-x-suffix has annotation " + NamespaceAnnotation + "=default that translates to host namespace host-namespace",
+		},
+		{
+			name: "host name mismatch",
+			modify: func(obj *corev1.Secret) {
+				obj.Annotations[HostNameAnnotation] = "other"
+			},
+			expectedError: "host object my-secret-x-default-x-suffix has annotation " + HostNameAnnotation + " with unexpected value other",
+		},
+		{
+			name: "invalid kind",
+			modify: func(obj *corev1.Secret) {
+				obj.Annotations[KindAnnotation] = "Secret"
+			},
+			expectedError: "host object my-secret-x-default-x-suffix has invalid annotation " + KindAnnotation + ": \"Secret\" is not a valid group version kind",
+		},
+		{
+			name: "kind mismatch",
+			modify: func(obj *corev1.Secret) {
+				obj.Annotations[KindAnnotation] = corev1.SchemeGroupVersion.WithKind("ConfigMap").String()
+			},
+			expectedError: "host object my-secret-x-default-x-suffix has annotation " + KindAnnotation + "=/v1, Kind=ConfigMap, but is of kind /v1, Kind=Secret",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-secret-x-default-x-suffix",
+					Namespace:   "host-namespace",
+					Annotations: validAnnotations(),
+				},
+			}
+			if tt.modify != nil {
+				tt.modify(obj)
+			}
+
+			err := ValidateHostObjectAnnotations(obj)
+			if tt.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.expectedError)
+			}
+		})
+	}
+
+	// cluster scoped objects must not have a namespace annotation
+	err := ValidateHostObjectAnnotations(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-namespace",
+			Annotations: map[string]string{
+				NameAnnotation:      "my-namespace",
+				UIDAnnotation:       "1234",
+				NamespaceAnnotation: "default",
+			},
+		},
+	})
+	assert.Error(t, err, "cluster scoped host object my-namespace has unexpected annotation "+NamespaceAnnotation)
+}
+
+func TestApplyMetadataStrict(t *testing.T) {
+	fromLabels := map[string]string{"app": "nginx", "team": "a", "tier": "web"}
+	toLabels := map[string]string{"app": "nginx", "team": "b", "tier": "api", "user": "label"}
+	toAnnotations := map[string]string{ManagedLabelsAnnotation: "tier", "note": "user"}
+
+	labels, annotations, conflicts := ApplyMetadataStrict(map[string]string{"note": "synced"}, toAnnotations, fromLabels, toLabels)
+	assert.DeepEqual(t, labels, map[string]string{"app": "nginx", "team": "a", "tier": "web", "user": "label"})
+	assert.Equal(t, annotations["note"], "synced")
+
+	// team was set on the to side with a different value and was not managed before, tier was managed and app is equal
+	assert.DeepEqual(t, conflicts, MetadataConflicts{Labels: []string{"team"}, Annotations: []string{"note"}})
+	assert.Assert(t, !conflicts.Empty())
+
+	// the default behavior is unchanged
+	defaultLabels, defaultAnnotations := ApplyMetadata(map[string]string{"note": "synced"}, toAnnotations, fromLabels, toLabels)
+	assert.DeepEqual(t, defaultLabels, labels)
+	assert.DeepEqual(t, defaultAnnotations, annotations)
+
+	_, _, conflicts = ApplyMetadataStrict(nil, nil, fromLabels, nil)
+	assert.Assert(t, conflicts.Empty())
+}
+
+func TestApplyMapsStable(t *testing.T) {
+	fromMap := map[string]string{}
+	toMap := map[string]string{}
+	for i := range 50 {
+		fromMap[fmt.Sprintf("example.com/from-%d", i)] = fmt.Sprintf("value-%d", i)
+		toMap[fmt.Sprintf("example.com/to-%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	toMap["example.com/from-1"] = "conflict"
+	opts := ApplyMapsOptions{ManagedKeys: []string{"example.com/to-1", "example.com/to-2"}, ExcludeKeys: []string{"example.com/from-3"}, Strict: true}
+
+	// rebuild the inputs in a random order, so the map iteration order differs between runs
+	shuffled := func(in map[string]string) map[string]string {
+		keys := slices.Collect(maps.Keys(in))
+		rand.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+		out := map[string]string{}
+		for _, key := range keys {
+			out[key] = in[key]
+		}
+		return out
+	}
+
+	expectedMap, expectedManagedKeys, expectedConflicts := applyMaps(fromMap, toMap, opts)
+	for range 1000 {
+		retMap, managedKeys, conflicts := applyMaps(shuffled(fromMap), shuffled(toMap), opts)
+		assert.Equal(t, managedKeys, expectedManagedKeys)
+		assert.DeepEqual(t, conflicts, expectedConflicts)
+		assert.DeepEqual(t, retMap, expectedMap)
+	}
+}
+
+func TestMergeManagedKeyLists(t *testing.T) {
+	assert.Equal(t, MergeManagedKeyLists("", ""), "")
+	assert.Equal(t, MergeManagedKeyLists("a\nb", ""), "a\nb")
+	assert.Equal(t, MergeManagedKeyLists("", "b\na"), "a\nb")
+	assert.Equal(t, MergeManagedKeyLists("c\na", "d\nb"), "a\nb\nc\nd")
+	assert.Equal(t, MergeManagedKeyLists("a\nb\nc", "c\nb\nd"), "a\nb\nc\nd")
+	assert.Equal(t, MergeManagedKeyLists("b\na", "a\nb"), MergeManagedKeyLists("a\nb", "b\na"))
+}
+
+func newTestSecrets(count int) []*corev1.Secret {
+	secrets := make([]*corev1.Secret, 0, count)
+	for i := range count {
+		secrets = append(secrets, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("secret-%d", i),
+				Namespace: fmt.Sprintf("namespace-%d", i%3),
+				Labels: map[string]string{
+					"app": "test",
+				},
+				Annotations: map[string]string{
+					"test": "test",
+				},
+			},
+		})
+	}
+
+	return secrets
+}
+
+func hostSecretName(vObj *corev1.Secret) types.NamespacedName {
+	return Default.HostName(nil, vObj.Name, vObj.Namespace)
+}
+
+func TestTranslateList(t *testing.T) {
+	defer func(translator Translator) { Default = translator }(Default)
+	Default = NewSingleNamespaceTranslator("host-namespace")
+
+	vObjs := newTestSecrets(5)
+	pObjs := TranslateListToHost(nil, vObjs, hostSecretName)
+	assert.Equal(t, len(pObjs), len(vObjs))
+	for i := range vObjs {
+		assert.DeepEqual(t, pObjs[i], HostMetadataWithContext(nil, vObjs[i], hostSecretName(vObjs[i])))
+	}
+
+	virtualName := func(pObj *corev1.Secret) types.NamespacedName {
+		return types.NamespacedName{Name: pObj.Annotations[NameAnnotation], Namespace: pObj.Annotations[NamespaceAnnotation]}
+	}
+	translatedBack := TranslateListToVirtual(pObjs, virtualName)
+	assert.Equal(t, len(translatedBack), len(vObjs))
+	for i := range pObjs {
+		assert.Equal(t, translatedBack[i].Name, vObjs[i].Name)
+		assert.Equal(t, translatedBack[i].Namespace, vObjs[i].Namespace)
+		assert.DeepEqual(t, translatedBack[i].Labels, vObjs[i].Labels)
+		assert.DeepEqual(t, translatedBack[i].Annotations, vObjs[i].Annotations)
+	}
+
+	// objects without a namespace yet, which the context knows are namespaced, get the namespaced marker label
+	syncCtx := &synccontext.SyncContext{Context: context.TODO(), VirtualClient: testingutil.NewFakeClient(scheme.Scheme)}
+	vObjs = []*corev1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "a"}}, {ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "test"}}}
+	pObjs = TranslateListToHost(syncCtx, vObjs, hostSecretName)
+	for i := range vObjs {
+		assert.DeepEqual(t, pObjs[i], HostMetadataWithContext(syncCtx, vObjs[i], hostSecretName(vObjs[i])))
+	}
+	assert.Equal(t, pObjs[0].Labels[MarkerLabel], VClusterName)
+	assert.Equal(t, pObjs[1].Labels[NamespaceLabel], "test")
+
+	assert.Assert(t, TranslateListToHost(nil, []*corev1.Secret{}, hostSecretName) == nil)
+}
+
+func BenchmarkTranslateListToHost(b *testing.B) {
+	defer func(translator Translator) { Default = translator }(Default)
+	Default = NewSingleNamespaceTranslator("host-namespace")
+
+	// the context looks up the scope of the objects through the rest mapper of the virtual client
+	ctx := &synccontext.SyncContext{Context: context.TODO(), VirtualClient: testingutil.NewFakeClient(scheme.Scheme)}
+	vObjs := newTestSecrets(1000)
+	b.Run("per-item", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			for _, vObj := range vObjs {
+				_ = HostMetadataWithContext(ctx, vObj, hostSecretName(vObj))
+			}
+		}
+	})
+	b.Run("batched", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_ = TranslateListToHost(ctx, vObjs, hostSecretName)
+		}
+	})
+}
+
+func newTestNames(count int) []types.NamespacedName {
+	names := make([]types.NamespacedName, 0, count)
+	for i := range count {
+		names = append(names, types.NamespacedName{
+			Name:      fmt.Sprintf("a-very-long-deployment-name-that-is-too-long-for-kubernetes-%d", i%1000),
+			Namespace: fmt.Sprintf("namespace-%d", i%50),
+		})
+	}
+
+	return names
+}
+
+func TestHostNames(t *testing.T) {
+	defer func(translator Translator) { Default = translator }(Default)
+	Default = NewSingleNamespaceTranslator("host-namespace")
+
+	vNames := newTestNames(200)
+	vNames = append(vNames, types.NamespacedName{Namespace: "empty"})
+	pNames := HostNames(nil, vNames)
+	assert.Equal(t, len(pNames), len(vNames))
+	for i := range vNames {
+		assert.Equal(t, pNames[i], Default.HostName(nil, vNames[i].Name, vNames[i].Namespace))
+	}
+
+	assert.Assert(t, HostNames(nil, nil) == nil)
+}
+
+func BenchmarkHostNames(b *testing.B) {
+	defer func(translator Translator) { Default = translator }(Default)
+	Default = NewSingleNamespaceTranslator("host-namespace")
+
+	vNames := newTestNames(5000)
+	b.Run("per-item", func(b *testing.B) {
+		for range b.N {
+			for _, vName := range vNames {
+				_ = Default.HostName(nil, vName.Name, vName.Namespace)
+			}
+		}
+	})
+	b.Run("batched", func(b *testing.B) {
+		for range b.N {
+			_ = HostNames(nil, vNames)
+		}
+	})
+}
+
+func BenchmarkShortHashCache(b *testing.B) {
+	// the hash inputs of 10k objects with long names across 50 namespaces, which only have 200 distinct names
+	inputs := make([]string, 0, 10000)
+	for i := range 10000 {
+		inputs = append(inputs, fmt.Sprintf("a-very-long-deployment-name-that-is-too-long-for-kubernetes-%d-x-namespace-%d-x-suffix", i%200, i%50))
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		ResetHashCache()
+		b.ReportAllocs()
+		for range b.N {
+			for _, input := range inputs {
+				_ = shortHash(input, 10)
+			}
+		}
+	})
+	b.Run("sha256", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			for _, input := range inputs {
+				_ = sha256Hex(input)[0:10]
+			}
+		}
+	})
+	b.Run("cached-parallel", func(b *testing.B) {
+		ResetHashCache()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				_ = shortHash(inputs[i%len(inputs)], 10)
+			}
+		})
+	})
+	b.Run("sha256-parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				_ = sha256Hex(inputs[i%len(inputs)])[0:10]
+			}
+		})
+	})
+}
+
+func TestShortHashCache(t *testing.T) {
+	defer ResetHashCache()
+
+	ResetHashCache()
+	assert.Equal(t, shortHash("vcluster", 16), "f689954fb7af1363")
+	assert.Equal(t, hashCache.Len(), 1)
+
+	// different lengths share the cached digest
+	assert.Equal(t, shortHash("vcluster", 10), "f689954fb7")
+	assert.Equal(t, hashCache.Len(), 1)
+
+	ResetHashCache()
+	assert.Equal(t, hashCache.Len(), 0)
+}
+
+func BenchmarkAnnotations(b *testing.B) {
+	vObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test",
+		Namespace: "test",
+		UID:       "123",
+		Annotations: map[string]string{
+			"app.kubernetes.io/name":    "test",
+			"app.kubernetes.io/version": "1.0.0",
+			"example.com/owner":         "team-a",
+			"example.com/revision":      "42",
+		},
+	}}
+	pObj := HostMetadataWithContext(nil, vObj, types.NamespacedName{Name: "test-x-test-x-suffix", Namespace: "host"})
+	pObjUnmanaged := vObj.DeepCopy()
+
+	b.Run("virtual", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_ = VirtualAnnotations(pObjUnmanaged, vObj)
+		}
+	})
+	b.Run("host", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_ = HostAnnotations(vObj, pObj)
+		}
+	})
+}
+
+func TestFilterCRDVersions(t *testing.T) {
+	versions := []apiextensionsv1.CustomResourceDefinitionVersion{
+		{Name: "v1alpha1", Served: true},
+		{Name: "v1beta1", Served: false},
+		{Name: "v1beta2", Served: true},
+		{Name: "v1", Served: true, Storage: true},
+	}
+
+	filtered, err := FilterCRDVersions(versions, []string{"v1alpha1"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, filtered, []apiextensionsv1.CustomResourceDefinitionVersion{
+		{Name: "v1beta2", Served: true},
+		{Name: "v1", Served: true, Storage: true},
+	})
+
+	// nothing excluded keeps all served versions
+	filtered, err = FilterCRDVersions(versions, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(filtered), 3)
+
+	// excluding the storage version is not allowed
+	_, err = FilterCRDVersions(versions, []string{"v1alpha1", "v1"})
+	assert.ErrorContains(t, err, "no served storage version")
+}
+
+func TestVirtualCRDVersions(t *testing.T) {
+	pCrd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: false},
+				{Name: "v1beta1", Served: true, Subresources: &apiextensionsv1.CustomResourceSubresources{Status: &apiextensionsv1.CustomResourceSubresourceStatus{}}},
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+
+	// by default only the requested version is kept and becomes the storage version
+	versions, err := virtualCRDVersions(pCrd, "v1beta1", EnsureCRDOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(versions), 1)
+	assert.Equal(t, versions[0].Name, "v1beta1")
+	assert.Assert(t, versions[0].Served && versions[0].Storage)
+
+	// all served versions keep the storage version of the host
+	versions, err = virtualCRDVersions(pCrd, "v1beta1", EnsureCRDOptions{AllVersions: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, versions, pCrd.Spec.Versions[1:])
+
+	// the requested version needs to be served
+	_, err = virtualCRDVersions(pCrd, "v1alpha1", EnsureCRDOptions{AllVersions: true})
+	assert.ErrorContains(t, err, `version "v1alpha1" is not served by physical CRD "widgets.example.com"`)
+	assert.Assert(t, errors.Is(err, ErrResourceNotInHost))
+
+	// the host crd is not modified
+	assert.Assert(t, !pCrd.Spec.Versions[1].Storage)
+}
+
+func TestVirtualCRDConversion(t *testing.T) {
+	none := &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+	versions := []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1beta1", Served: true}, {Name: "v1", Served: true, Storage: true}}
+	newCRD := func(conversion *apiextensionsv1.CustomResourceConversion) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "certificates.cert-manager.io"},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Conversion: conversion, Versions: versions},
+		}
+	}
+
+	conversion, err := virtualCRDConversion(newCRD(nil), versions)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, conversion, none)
+	conversion, err = virtualCRDConversion(newCRD(none), versions)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, conversion, none)
+
+	// webhooks referencing a service are rewritten to the url of the host service
+	port := int32(9443)
+	path := "/convert"
+	webhook := &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service:  &apiextensionsv1.ServiceReference{Namespace: "cert-manager", Name: "cert-manager-webhook", Port: &port, Path: &path},
+				CABundle: []byte("ca"),
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	conversion, err = virtualCRDConversion(newCRD(webhook), versions)
+	assert.NilError(t, err)
+	assert.Equal(t, conversion.Strategy, apiextensionsv1.WebhookConverter)
+	assert.Assert(t, conversion.Webhook.ClientConfig.Service == nil)
+	assert.Equal(t, *conversion.Webhook.ClientConfig.URL, "https://cert-manager-webhook.cert-manager.svc:9443/convert")
+	assert.DeepEqual(t, conversion.Webhook.ClientConfig.CABundle, []byte("ca"))
+	assert.DeepEqual(t, conversion.Webhook.ConversionReviewVersions, []string{"v1"})
+	assert.Assert(t, webhook.Webhook.ClientConfig.Service != nil)
+
+	// a single version needs no conversion
+	conversion, err = virtualCRDConversion(newCRD(webhook), versions[1:])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, conversion, none)
+
+	// webhooks without a client config cannot be used
+	_, err = virtualCRDConversion(newCRD(&apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.WebhookConverter}), versions)
+	assert.ErrorContains(t, err, `conversion webhook of physical CRD "certificates.cert-manager.io" has no client config`)
+}
+
+func TestVirtualNamespaceFromHostObject(t *testing.T) {
+	fromLabel := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Labels:      map[string]string{NamespaceLabel: "from-label"},
+		Annotations: map[string]string{NamespaceAnnotation: "from-annotation"},
+	}}
+	vNamespace, ok := VirtualNamespaceFromHostObject(fromLabel)
+	assert.Assert(t, ok)
+	assert.Equal(t, vNamespace, "from-label")
+
+	fromAnnotation := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{NamespaceAnnotation: "from-annotation"},
+	}}
+	vNamespace, ok = VirtualNamespaceFromHostObject(fromAnnotation)
+	assert.Assert(t, ok)
+	assert.Equal(t, vNamespace, "from-annotation")
+
+	_, ok = VirtualNamespaceFromHostObject(&corev1.ConfigMap{})
+	assert.Assert(t, !ok)
+}
+
+func TestCreateCrdWithRetry(t *testing.T) {
+	defer func(backoff wait.Backoff) {
+		createCrdBackoff = backoff
+	}(createCrdBackoff)
+	createCrdBackoff.Duration = time.Millisecond
+
+	// a webhook that is not available yet is retried
+	calls := 0
+	err := createCrdWithRetry(func() error {
+		calls++
+		if calls == 1 {
+			return kerrors.NewServiceUnavailable("webhook not ready")
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 2)
+
+	// a webhook that can't be called yet is retried
+	calls = 0
+	err = createCrdWithRetry(func() error {
+		calls++
+		if calls < 3 {
+			return kerrors.NewInternalError(fmt.Errorf(`failed calling webhook "validate.example.com": dial tcp 10.96.0.1:443: connect: connection refused`))
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 3)
+
+	// other internal errors are returned immediately
+	calls = 0
+	err = createCrdWithRetry(func() error {
+		calls++
+		return kerrors.NewInternalError(fmt.Errorf("etcdserver: request timed out"))
+	})
+	assert.Assert(t, kerrors.IsInternalError(err))
+	assert.Equal(t, calls, 1)
+
+	// retries are bounded
+	calls = 0
+	err = createCrdWithRetry(func() error {
+		calls++
+		return kerrors.NewServiceUnavailable("webhook not ready")
+	})
+	assert.Assert(t, kerrors.IsServiceUnavailable(err))
+	assert.Equal(t, calls, createCrdBackoff.Steps)
+
+	// validation errors are returned immediately
+	calls = 0
+	err = createCrdWithRetry(func() error {
+		calls++
+		return kerrors.NewInvalid(schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}, "test", nil)
+	})
+	assert.Assert(t, kerrors.IsInvalid(err))
+	assert.Equal(t, calls, 1)
+}
+
+func TestListImportedCRDs(t *testing.T) {
+	newCRD := func(name, group, kind string, annotations map[string]string, versions ...string) apiextensionsv1.CustomResourceDefinition {
+		crd := apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind},
+			},
+		}
+		for _, version := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: version, Served: true})
+		}
+		return crd
+	}
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinitionList"},
+		Items: []apiextensionsv1.CustomResourceDefinition{
+			newCRD("certificates.cert-manager.io", "cert-manager.io", "Certificate", map[string]string{ImportedFromHostAnnotation: "true"}, "v1"),
+			newCRD("widgets.example.com", "example.com", "Widget", nil, "v1"),
+			newCRD("issuers.cert-manager.io", "cert-manager.io", "Issuer", map[string]string{ImportedFromHostAnnotation: "true"}, "v1", "v1beta1"),
+			newCRD("gadgets.example.com", "example.com", "Gadget", map[string]string{"other": "true"}, "v1"),
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/apiextensions.k8s.io/v1/customresourcedefinitions" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(crdList)
+	}))
+	defer server.Close()
+
+	groupVersionKinds, err := ListImportedCRDs(context.Background(), &rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, groupVersionKinds, []schema.GroupVersionKind{
+		{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"},
+		{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"},
+		{Group: "cert-manager.io", Version: "v1beta1", Kind: "Issuer"},
+	})
+}
+
+func TestCRDNotEstablishedError(t *testing.T) {
+	var err error = &CRDNotEstablishedError{
+		GroupVersionKind: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+		Message:          "&CustomResourceDefinitionCondition{Type:Established,Status:False,Reason:Installing}",
+		Err:              context.DeadlineExceeded,
+	}
+	err = fmt.Errorf("ensure crd: %w", err)
+
+	assert.Assert(t, errors.Is(err, ErrCRDNotEstablished))
+	assert.Assert(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Assert(t, !errors.Is(err, ErrResourceNotInHost))
+	assert.ErrorContains(t, err, "failed to wait for CRD example.com/v1, Kind=Widget to become ready: context deadline exceeded (last condition: &CustomResourceDefinitionCondition{Type:Established,Status:False,Reason:Installing})")
+
+	notEstablished := &CRDNotEstablishedError{}
+	assert.Assert(t, errors.As(err, &notEstablished))
+	assert.Equal(t, notEstablished.GroupVersionKind.Kind, "Widget")
+}
+
+func TestCreateCrdTimeout(t *testing.T) {
+	pCrd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    "example.com",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+		},
+	}
+
+	// the crd never becomes established
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_ = json.NewEncoder(w).Encode(pCrd)
+	}))
+	defer server.Close()
+
+	vClient, err := apiextensionsv1clientset.NewForConfig(&rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+
+	groupVersionKind := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	start := time.Now()
+	_, _, err = createCrdFromPhysicalCluster(context.Background(), vClient, pCrd.DeepCopy(), groupVersionKind.GroupVersion().WithResource("widgets"), groupVersionKind, EnsureCRDOptions{Timeout: 100 * time.Millisecond})
+	assert.Assert(t, errors.Is(err, ErrCRDNotEstablished))
+	assert.Assert(t, time.Since(start) < 10*time.Second)
+}
+
+// newCRDServer serves the given crds and api resources and fails the test on any request that is not a get
+func newCRDServer(t *testing.T, crds map[string]*apiextensionsv1.CustomResourceDefinition, resources map[string]*metav1.APIResourceList) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+		}
+
+		notFound := func(resource, name string) {
+			status := kerrors.NewNotFound(schema.GroupResource{Resource: resource}, name).ErrStatus
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&status)
+		}
+		if name, ok := strings.CutPrefix(r.URL.Path, "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/"); ok {
+			if crds[name] == nil {
+				notFound("customresourcedefinitions", name)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(crds[name])
+			return
+		}
+
+		groupVersion := strings.TrimPrefix(r.URL.Path, "/apis/")
+		if resources[groupVersion] == nil {
+			notFound("", groupVersion)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resources[groupVersion])
+	}))
+}
+
+// newWritableCRDServer serves the given crds and stores created and updated crds, which become established right away
+func newWritableCRDServer(t *testing.T, crds map[string]*apiextensionsv1.CustomResourceDefinition) *httptest.Server {
+	lock := sync.Mutex{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		name, ok := strings.CutPrefix(r.URL.Path, "/apis/apiextensions.k8s.io/v1/customresourcedefinitions")
+		if !ok {
+			status := kerrors.NewNotFound(schema.GroupResource{}, r.URL.Path).ErrStatus
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&status)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost, http.MethodPut:
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := json.NewDecoder(r.Body).Decode(crd); err != nil {
+				t.Errorf("decode crd: %v", err)
+			}
+			crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}}
+			crds[crd.Name] = crd
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+			}
+			_ = json.NewEncoder(w).Encode(crd)
+		default:
+			crd := crds[strings.TrimPrefix(name, "/")]
+			if crd == nil {
+				status := kerrors.NewNotFound(schema.GroupResource{Resource: "customresourcedefinitions"}, name).ErrStatus
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(&status)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(crd)
+		}
+	}))
+}
+
+func TestEnsureCRDFromPhysicalClusterExcludeVersions(t *testing.T) {
+	newCRD := func(versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "example.com", Scope: apiextensionsv1.NamespaceScoped, Versions: versions},
+		}
+	}
+	versionNames := func(crd *apiextensionsv1.CustomResourceDefinition) []string {
+		names := []string{}
+		for _, version := range crd.Spec.Versions {
+			names = append(names, version.Name)
+		}
+		return names
+	}
+
+	hostServer := newCRDServer(t, map[string]*apiextensionsv1.CustomResourceDefinition{
+		"widgets.example.com": newCRD(
+			apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+			apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true},
+			apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+		),
+	}, map[string]*metav1.APIResourceList{
+		"example.com/v1": {
+			TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "APIResourceList"},
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true}},
+		},
+	})
+	defer hostServer.Close()
+	pConfig := &rest.Config{Host: hostServer.URL}
+	groupVersionKind := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	options := EnsureCRDOptions{AllVersions: true, ExcludeVersions: []string{"v1alpha1"}}
+	vConfig := func(server *httptest.Server) *rest.Config {
+		return &rest.Config{Host: server.URL, ContentConfig: rest.ContentConfig{ContentType: "application/json"}}
+	}
+
+	// the excluded version is not created
+	vCRDs := map[string]*apiextensionsv1.CustomResourceDefinition{}
+	virtualServer := newWritableCRDServer(t, vCRDs)
+	_, _, err := EnsureCRDFromPhysicalClusterWithOptions(context.Background(), pConfig, vConfig(virtualServer), groupVersionKind, options)
+	virtualServer.Close()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, versionNames(vCRDs["widgets.example.com"]), []string{"v1beta1", "v1"})
+
+	// the excluded version is not added on update, but versions that already exist in the virtual cluster are kept
+	vCRDs = map[string]*apiextensionsv1.CustomResourceDefinition{
+		"widgets.example.com": newCRD(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: true}),
+	}
+	virtualServer = newWritableCRDServer(t, vCRDs)
+	_, _, err = EnsureCRDFromPhysicalClusterWithOptions(context.Background(), pConfig, vConfig(virtualServer), groupVersionKind, EnsureCRDOptions{AllVersions: true, ExcludeVersions: []string{"v1alpha1", "v1beta1"}})
+	virtualServer.Close()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, versionNames(vCRDs["widgets.example.com"]), []string{"v1", "v1beta1"})
+	assert.Assert(t, vCRDs["widgets.example.com"].Spec.Versions[0].Storage)
+
+	// the requested version can't be excluded
+	virtualServer = newWritableCRDServer(t, map[string]*apiextensionsv1.CustomResourceDefinition{})
+	defer virtualServer.Close()
+	_, _, err = EnsureCRDFromPhysicalClusterWithOptions(context.Background(), pConfig, vConfig(virtualServer), groupVersionKind, EnsureCRDOptions{AllVersions: true, ExcludeVersions: []string{"v1"}})
+	assert.ErrorContains(t, err, `version "v1" of physical CRD "widgets.example.com" is excluded`)
+}
+
+func TestPlanCRDFromPhysicalCluster(t *testing.T) {
+	newCRD := func(versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com", UID: "uid", ResourceVersion: "1"},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "example.com", Scope: apiextensionsv1.NamespaceScoped},
+		}
+		for _, version := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: version, Served: true})
+		}
+
+		return crd
+	}
+	newResources := func(version string) *metav1.APIResourceList {
+		return &metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "APIResourceList"},
+			GroupVersion: "example.com/" + version,
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true}},
+		}
+	}
+
+	hostServer := newCRDServer(t, map[string]*apiextensionsv1.CustomResourceDefinition{
+		"widgets.example.com": newCRD("v1alpha1", "v1"),
+	}, map[string]*metav1.APIResourceList{
+		"example.com/v1alpha1": newResources("v1alpha1"),
+		"example.com/v1":       newResources("v1"),
+	})
+	defer hostServer.Close()
+	pConfig := &rest.Config{Host: hostServer.URL}
+
+	// the crd does not exist in the virtual cluster
+	virtualServer := newCRDServer(t, nil, nil)
+	action, crd, err := PlanCRDFromPhysicalCluster(context.Background(), pConfig, &rest.Config{Host: virtualServer.URL}, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	virtualServer.Close()
+	assert.NilError(t, err)
+	assert.Equal(t, action, CRDPlanCreate)
+	assert.Equal(t, crd.Annotations[ImportedFromHostAnnotation], "true")
+	assert.Equal(t, string(crd.UID), "")
+	assert.Equal(t, len(crd.Spec.Versions), 1)
+	assert.Equal(t, crd.Spec.Versions[0].Name, "v1")
+	assert.Assert(t, crd.Spec.Versions[0].Storage)
+
+	// the crd exists in the virtual cluster with another version
+	virtualServer = newCRDServer(t, map[string]*apiextensionsv1.CustomResourceDefinition{
+		"widgets.example.com": newCRD("v1alpha1"),
+	}, map[string]*metav1.APIResourceList{
+		"example.com/v1alpha1": newResources("v1alpha1"),
+	})
+	action, crd, err = PlanCRDFromPhysicalCluster(context.Background(), pConfig, &rest.Config{Host: virtualServer.URL}, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	assert.NilError(t, err)
+	assert.Equal(t, action, CRDPlanUpdate)
+	assert.Equal(t, string(crd.UID), "uid")
+	assert.Equal(t, len(crd.Spec.Versions), 2)
+	assert.Equal(t, crd.Spec.Versions[0].Name, "v1alpha1")
+	assert.Assert(t, !crd.Spec.Versions[0].Storage)
+	assert.Equal(t, crd.Spec.Versions[1].Name, "v1")
+	assert.Assert(t, crd.Spec.Versions[1].Storage)
+
+	// the version is already served in the virtual cluster
+	action, crd, err = PlanCRDFromPhysicalCluster(context.Background(), pConfig, &rest.Config{Host: virtualServer.URL}, schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Widget"})
+	virtualServer.Close()
+	assert.NilError(t, err)
+	assert.Equal(t, action, CRDPlanNone)
+	assert.DeepEqual(t, crd.Spec, newCRD("v1alpha1").Spec)
+
+	// the kind does not exist in the host cluster
+	_, _, err = PlanCRDFromPhysicalCluster(context.Background(), pConfig, pConfig, schema.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Widget"})
+	assert.Assert(t, errors.Is(err, ErrResourceNotInHost))
+}
+
+func TestHostFieldSelector(t *testing.T) {
+	defer func(translator Translator) { Default = translator }(Default)
+	Default = NewSingleNamespaceTranslator("host-namespace")
+	ctx := &synccontext.SyncContext{Context: context.TODO()}
+
+	selector := HostFieldSelector(ctx, fields.ParseSelectorOrDie("metadata.name=foo,metadata.namespace=bar"), "")
+	assert.DeepEqual(t, selector.Requirements(), fields.Requirements{
+		{Operator: selection.Equals, Field: "metadata.name", Value: Default.HostName(ctx, "foo", "bar").Name},
+		{Operator: selection.Equals, Field: "metadata.namespace", Value: "host-namespace"},
+	})
+	assert.Assert(t, selector.Matches(fields.Set{"metadata.name": Default.HostName(ctx, "foo", "bar").Name, "metadata.namespace": "host-namespace"}))
+
+	// names are translated within the given namespace, unknown fields are kept
+	selector = HostFieldSelector(ctx, fields.ParseSelectorOrDie("metadata.name!=foo,spec.nodeName=node"), "test")
+	assert.DeepEqual(t, selector.Requirements(), fields.Requirements{
+		{Operator: selection.NotEquals, Field: "metadata.name", Value: Default.HostName(ctx, "foo", "test").Name},
+		{Operator: selection.Equals, Field: "spec.nodeName", Value: "node"},
+	})
+
+	// cluster scoped names
+	selector = HostFieldSelector(ctx, fields.OneTermEqualSelector("metadata.name", "foo"), "")
+	assert.Equal(t, selector.String(), "metadata.name="+Default.HostNameCluster("foo"))
+
+	assert.Assert(t, HostFieldSelector(ctx, fields.Everything(), "test").Empty())
+	assert.Assert(t, HostFieldSelector(ctx, nil, "test") == nil)
+}
+
+func TestRemoveCRDFromVirtualCluster(t *testing.T) {
+	crds := map[string]*apiextensionsv1.CustomResourceDefinition{
+		"widgets.example.com": {
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com", UID: "widgets", Annotations: map[string]string{ImportedFromHostAnnotation: "true"}},
+		},
+		"gadgets.example.com": {
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "gadgets.example.com", UID: "gadgets"},
+		},
+	}
+	resources := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "APIResourceList"},
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: true},
+			{Name: "gadgets", Kind: "Gadget", Namespaced: true},
+		},
+	}
+
+	deletes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/apis/example.com/v1" {
+			_ = json.NewEncoder(w).Encode(resources)
+			return
+		}
+
+		name, ok := strings.CutPrefix(r.URL.Path, "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/")
+		crd := crds[name]
+		if !ok || crd == nil {
+			status := kerrors.NewNotFound(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, name).ErrStatus
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&status)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			deletes++
+			delete(crds, name)
+		}
+		_ = json.NewEncoder(w).Encode(crd)
+	}))
+	defer server.Close()
+
+	vConfig := &rest.Config{Host: server.URL}
+	assert.NilError(t, RemoveCRDFromVirtualCluster(context.Background(), vConfig, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}))
+	assert.Equal(t, deletes, 1)
+	assert.Assert(t, crds["widgets.example.com"] == nil)
+
+	// removing the crd again or an unknown kind is a no-op
+	assert.NilError(t, RemoveCRDFromVirtualCluster(context.Background(), vConfig, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}))
+	assert.NilError(t, RemoveCRDFromVirtualCluster(context.Background(), vConfig, schema.GroupVersionKind{Group: "other.example.com", Version: "v1", Kind: "Widget"}))
+	assert.Equal(t, deletes, 1)
+
+	// crds that were not imported from the host are kept
+	err := RemoveCRDFromVirtualCluster(context.Background(), vConfig, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"})
+	assert.ErrorContains(t, err, "crd gadgets.example.com was not imported from the host cluster")
+	assert.Equal(t, deletes, 1)
+}
+
+func TestTranslateVersion(t *testing.T) {
+	defer func(mode int, salt string) { CompatibilityMode, HashSalt = mode, salt }(CompatibilityMode, HashSalt)
+	CompatibilityMode, HashSalt = CurrentTranslateVersion, ""
+
+	// without a salt host names are the same as with the unsalted algorithm
+	vObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	pObj := HostMetadataWithContext(nil, vObj, types.NamespacedName{Name: "test-x-test", Namespace: "host"})
+	version, ok := TranslateVersion(pObj)
+	assert.Assert(t, ok)
+	assert.Equal(t, version, TranslateVersionUnsalted)
+
+	HashSalt = "salt"
+	pObj = HostMetadataWithContext(nil, vObj, types.NamespacedName{Name: "test-x-test", Namespace: "host"})
+	version, ok = TranslateVersion(pObj)
+	assert.Assert(t, ok)
+	assert.Equal(t, version, CurrentTranslateVersion)
+
+	// the version is never synced back to the virtual object
+	_, ok = VirtualAnnotations(pObj, vObj)[TranslateVersionAnnotation]
+	assert.Assert(t, !ok)
+
+	// objects synced before the version was recorded
+	_, ok = TranslateVersion(&corev1.Secret{})
+	assert.Assert(t, !ok)
+}
+
+// mappedNamespaceTranslator maps some virtual namespaces to dedicated host namespaces
+type mappedNamespaceTranslator struct {
+	Translator
+
+	mappings map[string]string
+}
+
+func (m *mappedNamespaceTranslator) HostNamespace(ctx *synccontext.SyncContext, vNamespace string) string {
+	if pNamespace, ok := m.mappings[vNamespace]; ok {
+		return pNamespace
+	}
+
+	return m.Translator.HostNamespace(ctx, vNamespace)
+}
+
+func TestValidateHostNamespace(t *testing.T) {
+	ctx := &synccontext.SyncContext{CurrentNamespace: "vcluster"}
+
+	assert.NilError(t, ValidateHostNamespace(ctx, "team-a", "host-team-a"))
+	assert.ErrorContains(t, ValidateHostNamespace(ctx, "control-plane", "vcluster"), "virtual namespace control-plane is mapped to host namespace vcluster")
+
+	// the check can be disabled
+	AllowHostNamespaceInCurrentNamespace = true
+	defer func() { AllowHostNamespaceInCurrentNamespace = false }()
+	assert.NilError(t, ValidateHostNamespace(ctx, "control-plane", "vcluster"))
+}
+
+func TestValidateHostNamespaceOwner(t *testing.T) {
+	ctx := &synccontext.SyncContext{
+		Context: context.TODO(),
+		HostClient: testingutil.NewFakeClient(scheme.Scheme,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "host-team-a", Annotations: map[string]string{NameAnnotation: "team-a"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "host-unmanaged"}},
+		),
+	}
+
+	// the host namespace was synced from the same virtual namespace or does not exist yet
+	assert.NilError(t, ValidateHostNamespaceOwner(ctx, "team-a", "host-team-a"))
+	assert.NilError(t, ValidateHostNamespaceOwner(ctx, "team-c", "host-team-c"))
+	assert.NilError(t, ValidateHostNamespaceOwner(ctx, "unmanaged", "host-unmanaged"))
+
+	// the host namespace belongs to another virtual namespace
+	err := ValidateHostNamespaceOwner(ctx, "team-b", "host-team-a")
+	assert.ErrorContains(t, err, "virtual namespace team-b is mapped to host namespace host-team-a, which is already used by virtual namespace team-a")
+}
+
+func TestInformerNamespaces(t *testing.T) {
+	ctx := &synccontext.SyncContext{CurrentNamespace: "vcluster"}
+	translator := NewSingleNamespaceTranslator("host-namespace")
+
+	assert.DeepEqual(t, InformerNamespaces(ctx, translator, []string{"b", "a", "", "default"}), []string{"host-namespace", "vcluster"})
+	assert.DeepEqual(t, InformerNamespaces(ctx, translator, nil), []string{"vcluster"})
+
+	mapped := &mappedNamespaceTranslator{
+		Translator: translator,
+		mappings:   map[string]string{"mapped": "host-mapped", "own": "vcluster"},
+	}
+	assert.DeepEqual(t, InformerNamespaces(ctx, mapped, []string{"mapped", "own", "default"}), []string{"host-mapped", "host-namespace", "vcluster"})
+}
+
+func TestHostAnnotationsWithUID(t *testing.T) {
+	vObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", UID: "live-uid"}}
+	assert.Equal(t, HostAnnotations(vObj, nil)[UIDAnnotation], "live-uid")
+
+	// a recreated object gets a new uid, but keeps the deterministic uid
+	recreated := vObj.DeepCopy()
+	recreated.UID = "recreated-uid"
+	assert.Equal(t, HostAnnotations(recreated, nil)[UIDAnnotation], "recreated-uid")
+	assert.Equal(t, DeterministicUID(recreated), DeterministicUID(vObj))
+
+	annotations := HostAnnotationsWithUID(recreated, nil, DeterministicUID(recreated))
+	assert.Equal(t, annotations[UIDAnnotation], DeterministicUID(vObj))
+	assert.Equal(t, annotations[NameAnnotation], "test")
+
+	// objects with a different kind, namespace or name get a different uid
+	assert.Assert(t, DeterministicUID(&corev1.ConfigMap{ObjectMeta: vObj.ObjectMeta}) != DeterministicUID(vObj))
+	assert.Assert(t, DeterministicUID(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "other"}}) != DeterministicUID(vObj))
+}
+
+func TestPreserveHostAnnotationKeys(t *testing.T) {
+	meshAnnotation := "sidecar.istio.io/status"
+	PreserveHostAnnotationKeys = []string{meshAnnotation}
+	defer func() { PreserveHostAnnotationKeys = nil }()
+
+	vObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test",
+		Namespace:   "test",
+		Annotations: map[string]string{"app": "test", meshAnnotation: "virtual"},
+	}}
+	pObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test-x-test",
+		Namespace: "host",
+		Annotations: map[string]string{
+			"app":                        "test",
+			meshAnnotation:               "injected",
+			ManagedAnnotationsAnnotation: "app\n" + meshAnnotation,
+		},
+	}}
+
+	// the host owned annotation survives the re-sync and is not managed by vCluster anymore
+	annotations := HostAnnotations(vObj, pObj)
+	assert.Equal(t, annotations[meshAnnotation], "injected")
+	assert.Equal(t, annotations[ManagedAnnotationsAnnotation], "app")
+
+	// host changes of the annotation are not synced back
+	newVirtual, newHost := AnnotationsBidirectionalUpdate(synccontext.NewSyncEventWithOld(
+		pObj,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"app": "test", meshAnnotation: "changed"}}},
+		vObj,
+		vObj,
+	))
+	assert.Equal(t, newVirtual[meshAnnotation], "virtual")
+	assert.Equal(t, newHost[meshAnnotation], "changed")
+}
+
+// nameMapper maps host names to virtual names with a fixed mapping
+type nameMapper struct {
+	synccontext.Mapper
+
+	hostToVirtual map[types.NamespacedName]types.NamespacedName
+}
+
+func (n *nameMapper) HostToVirtual(_ *synccontext.SyncContext, req types.NamespacedName, _ client.Object) types.NamespacedName {
+	return n.hostToVirtual[req]
+}
+
+// failingGetClient fails every Get with err
+type failingGetClient struct {
+	client.Client
+
+	err error
+}
+
+func (f *failingGetClient) Get(_ context.Context, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	return f.err
+}
+
+func TestTranslateOwnerReferencesToVirtual(t *testing.T) {
+	replicaSetGvk := appsv1.SchemeGroupVersion.WithKind("ReplicaSet")
+	nodeGvk := corev1.SchemeGroupVersion.WithKind("Node")
+	mappingsStore, err := store.NewStore(context.TODO(), nil, nil, store.NewMemoryBackend())
+	assert.NilError(t, err)
+	mappingsRegistry := mappings.NewMappingsRegistry(mappingsStore)
+	assert.NilError(t, mappingsRegistry.AddMapper(&nameMapper{
+		Mapper: testingutil.NewFakeMapper(replicaSetGvk),
+		hostToVirtual: map[types.NamespacedName]types.NamespacedName{
+			{Name: "web-x-test-x-suffix", Namespace: "host"}:     {Name: "web", Namespace: "test"},
+			{Name: "deleted-x-test-x-suffix", Namespace: "host"}: {Name: "deleted", Namespace: "test"},
+		},
+	}))
+	assert.NilError(t, mappingsRegistry.AddMapper(&nameMapper{
+		Mapper:        testingutil.NewFakeMapper(nodeGvk),
+		hostToVirtual: map[types.NamespacedName]types.NamespacedName{{Name: "node-1"}: {Name: "node-1"}},
+	}))
+	vClient := testingutil.NewFakeClient(scheme.Scheme,
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test", UID: "virtual-web-uid"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", UID: "virtual-node-uid"}},
+	)
+	vClient.AddMapping(nodeGvk, meta.RESTScopeRoot)
+	ctx := &synccontext.SyncContext{
+		Context:       context.TODO(),
+		VirtualClient: vClient,
+		Mappings:      mappingsRegistry,
+		Owner:         &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "vcluster", UID: "vcluster-uid"}},
+	}
+
+	controller := true
+	pObj := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "web-abc-x-test-x-suffix",
+		Namespace: "host",
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-x-test-x-suffix", UID: "host-web-uid", Controller: &controller},
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "deleted-x-test-x-suffix", UID: "host-deleted-uid"},
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "unmapped", UID: "host-unmapped-uid"},
+			{APIVersion: "apps/v1", Kind: "Deployment", Name: "no-mapper", UID: "host-deployment-uid"},
+			{APIVersion: "v1", Kind: "Service", Name: "vcluster", UID: "vcluster-uid"},
+			{APIVersion: "v1", Kind: "Node", Name: "node-1", UID: "host-node-uid"},
+		},
+	}}
+
+	vOwnerReferences, err := TranslateOwnerReferencesToVirtual(ctx, pObj)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vOwnerReferences, []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web", UID: "virtual-web-uid", Controller: &controller},
+		{APIVersion: "v1", Kind: "Node", Name: "node-1", UID: "virtual-node-uid"},
+	})
+
+	// transient errors are returned instead of dropping the owner reference
+	ctx.VirtualClient = &failingGetClient{Client: vClient, err: kerrors.NewServiceUnavailable("unavailable")}
+	_, err = TranslateOwnerReferencesToVirtual(ctx, pObj)
+	assert.ErrorContains(t, err, "unavailable")
+
+	// sync contexts without mappings are rejected
+	_, err = TranslateOwnerReferencesToVirtual(&synccontext.SyncContext{Context: context.TODO()}, pObj)
+	assert.ErrorContains(t, err, "sync context has no mappings or virtual client")
+}
+
+func TestCompatibilityMode(t *testing.T) {
+	defer func(mode int, salt string) { CompatibilityMode, HashSalt = mode, salt }(CompatibilityMode, HashSalt)
+	HashSalt = "salt"
+
+	translator := NewSingleNamespaceTranslator("test")
+	longName := "a-very-long-pod-name-that-is-way-too-long-for-kubernetes"
+	for _, tt := range []struct {
+		mode              int
+		expectedName      string
+		expectedShortName string
+	}{
+		{
+			// the salt is ignored, so host names are the same as without salt
+			mode:              TranslateVersionUnsalted,
+			expectedName:      "a-very-long-pod-name-that-is-way-too-long-for-kubern-5bbaf707da",
+			expectedShortName: "v2vmsa6raog6jq",
+		},
+		{
+			mode:              TranslateVersionSalted,
+			expectedName:      "a-very-long-pod-name-that-is-way-too-long-for-kubern-89df8d89b2",
+			expectedShortName: "v5i194qsrissjf",
+		},
+	} {
+		CompatibilityMode = tt.mode
+		assert.NilError(t, ValidateCompatibilityMode(tt.mode))
+		assert.Equal(t, translator.HostName(nil, longName, "default").Name, tt.expectedName)
+		assert.Equal(t, translator.HostNameShort(nil, "short", "default").Name, tt.expectedShortName)
+
+		// namespaces and label keys are the same in all versions
+		assert.Equal(t, translator.HostName(nil, "short", "default"), types.NamespacedName{Name: "short-x-default-x-" + VClusterName, Namespace: "test"})
+		assert.Equal(t, convertLabelKeyWithPrefix(LabelPrefix, "release"), "vcluster.loft.sh/label-suffix-x-a4d451ec23")
+
+		// host objects record the version they were written with
+		pObj := HostMetadataWithContext(nil, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "short", Namespace: "default"}}, translator.HostName(nil, "short", "default"))
+		version, ok := TranslateVersion(pObj)
+		assert.Assert(t, ok)
+		assert.Equal(t, version, tt.mode)
+	}
+
+	assert.ErrorContains(t, ValidateCompatibilityMode(0), "unsupported translate compatibility mode 0")
+	assert.ErrorContains(t, ValidateCompatibilityMode(CurrentTranslateVersion+1), "unsupported translate compatibility mode")
+}
+
+type virtualNameMapper struct {
+	synccontext.Mapper
+
+	virtualToHost map[types.NamespacedName]types.NamespacedName
+}
+
+func (v *virtualNameMapper) VirtualToHost(_ *synccontext.SyncContext, req types.NamespacedName, _ client.Object) types.NamespacedName {
+	return v.virtualToHost[req]
+}
+
+func TestRoundTripName(t *testing.T) {
+	hostName, vName, lossless := RoundTripName(nil, "my-pod", "default")
+	assert.Equal(t, hostName, "my-pod-x-default-x-"+VClusterName)
+	assert.Equal(t, vName, "my-pod")
+	assert.Assert(t, lossless)
+
+	// names containing the separator can't be split unambiguously
+	hostName, vName, lossless = RoundTripName(nil, "a-x-b", "default")
+	assert.Equal(t, hostName, "a-x-b-x-default-x-"+VClusterName)
+	assert.Equal(t, vName, "")
+	assert.Assert(t, !lossless)
+
+	// hashed names can't be reversed
+	hostName, vName, lossless = RoundTripName(nil, strings.Repeat("a", 63), "default")
+	assert.Assert(t, len(hostName) <= 63)
+	assert.Equal(t, vName, "")
+	assert.Assert(t, !lossless)
+}
+
+func TestResolveHostName(t *testing.T) {
+	longName := "a-very-long-config-map-name-that-is-way-too-long-for-kubernetes"
+	hostName := SafeConcatName(longName, "x", "test", "x", "suffix")
+
+	// without mappings the annotations are returned as is
+	pObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:        hostName,
+		Namespace:   "host",
+		Annotations: map[string]string{NameAnnotation: longName, NamespaceAnnotation: "test"},
+	}}
+	vName, ok := ResolveHostName(nil, pObj)
+	assert.Assert(t, ok)
+	assert.Equal(t, vName, types.NamespacedName{Name: longName, Namespace: "test"})
+
+	// the namespace falls back to the namespace label
+	pObj.Annotations = map[string]string{NameAnnotation: longName}
+	pObj.Labels = map[string]string{NamespaceLabel: "test"}
+	vName, ok = ResolveHostName(nil, pObj)
+	assert.Assert(t, ok)
+	assert.Equal(t, vName, types.NamespacedName{Name: longName, Namespace: "test"})
+
+	// objects without annotations can't be resolved
+	_, ok = ResolveHostName(nil, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: hostName, Namespace: "host"}})
+	assert.Assert(t, !ok)
+
+	// annotations that don't match the host name are rejected if there is a mapper
+	configMapGvk := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	mappingsStore, err := store.NewStore(context.TODO(), nil, nil, store.NewMemoryBackend())
+	assert.NilError(t, err)
+	mappingsRegistry := mappings.NewMappingsRegistry(mappingsStore)
+	assert.NilError(t, mappingsRegistry.AddMapper(&virtualNameMapper{
+		Mapper: testingutil.NewFakeMapper(configMapGvk),
+		virtualToHost: map[types.NamespacedName]types.NamespacedName{
+			{Name: longName, Namespace: "test"}: {Name: hostName, Namespace: "host"},
+		},
+	}))
+	ctx := &synccontext.SyncContext{Context: context.TODO(), Mappings: mappingsRegistry}
+
+	pObj.Annotations = map[string]string{NameAnnotation: longName, NamespaceAnnotation: "test"}
+	vName, ok = ResolveHostName(ctx, pObj)
+	assert.Assert(t, ok)
+	assert.Equal(t, vName, types.NamespacedName{Name: longName, Namespace: "test"})
+
+	copied := pObj.DeepCopy()
+	copied.Name = "other"
+	_, ok = ResolveHostName(ctx, copied)
+	assert.Assert(t, !ok)
+}
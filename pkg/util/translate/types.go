@@ -7,27 +7,76 @@ import (
 )
 
 var (
-	NamespaceAnnotation      = "vcluster.loft.sh/object-namespace"
-	NameAnnotation           = "vcluster.loft.sh/object-name"
-	UIDAnnotation            = "vcluster.loft.sh/object-uid"
-	KindAnnotation           = "vcluster.loft.sh/object-kind"
-	HostNameAnnotation       = "vcluster.loft.sh/object-host-name"
-	HostNamespaceAnnotation  = "vcluster.loft.sh/object-host-namespace"
-	ImportedMarkerAnnotation = "vcluster.loft.sh/object-imported"
+	NamespaceAnnotation        = "vcluster.loft.sh/object-namespace"
+	NameAnnotation             = "vcluster.loft.sh/object-name"
+	UIDAnnotation              = "vcluster.loft.sh/object-uid"
+	KindAnnotation             = "vcluster.loft.sh/object-kind"
+	HostNameAnnotation         = "vcluster.loft.sh/object-host-name"
+	HostNamespaceAnnotation    = "vcluster.loft.sh/object-host-namespace"
+	ImportedMarkerAnnotation   = "vcluster.loft.sh/object-imported"
+	MarkerAnnotation           = "vcluster.loft.sh/managed-by"
+	TranslateVersionAnnotation = "vcluster.loft.sh/translate-version"
+
+	// ImportedFromHostAnnotation marks crds in the virtual cluster that were imported from the host cluster
+	ImportedFromHostAnnotation = "vcluster.loft.sh/imported-from-host"
 )
 
+const (
+	// TranslateVersionUnsalted is the translation algorithm that hashes host names without the HashSalt
+	TranslateVersionUnsalted = 1
+	// TranslateVersionSalted is the translation algorithm that mixes the HashSalt into hashed host names
+	TranslateVersionSalted = 2
+)
+
+// CurrentTranslateVersion is the latest version of the name and label translation algorithm. The version
+// a host object was written with is recorded in its TranslateVersionAnnotation. It needs to be increased
+// whenever the translation of names or labels changes, so syncers can migrate objects written by older versions.
+const CurrentTranslateVersion = TranslateVersionSalted
+
 var (
 	VClusterReleaseLabel = "release"
 	NamespaceLabel       = "vcluster.loft.sh/namespace"
 	MarkerLabel          = "vcluster.loft.sh/managed-by"
 	ControllerLabel      = "vcluster.loft.sh/controlled-by"
 
+	// ScopeLabel replaces the MarkerLabel and NamespaceLabel on namespaced host objects if UseAnnotationsForTopology
+	// is set. Its value is a hash of the vCluster name and virtual namespace, see ScopeLabelValue, so selectors of
+	// synced objects still only match objects of the same virtual namespace and vCluster.
+	ScopeLabel = "vcluster.loft.sh/scope"
+
+	// LabelPrefix is the prefix of translated label keys. It can be overridden at start time
+	// to avoid collisions with other products that translate labels on the same host.
 	LabelPrefix          = "vcluster.loft.sh/label"
 	NamespaceLabelPrefix = "vcluster.loft.sh/ns-label"
 
 	// VClusterName is the vcluster name, usually set at start time
 	VClusterName = "suffix"
 
+	// UseAnnotationsForTopology stores the marker of host objects in the MarkerAnnotation instead of the
+	// MarkerLabel and omits the NamespaceLabel. Namespaced host objects only get the ScopeLabel instead,
+	// usually set at start time
+	UseAnnotationsForTopology = false
+
+	// PreserveHostAnnotationKeys are annotations that are owned by the host cluster, e.g. injected by a service mesh.
+	// They are kept on host objects during updates and are never synced from or to the virtual object, usually set at start time
+	PreserveHostAnnotationKeys []string
+
+	// SyncLabelKeys is an allowlist of virtual label keys that are synced to host objects. If empty, all labels are synced,
+	// usually set at start time
+	SyncLabelKeys []string
+
+	// AllowHostNamespaceInCurrentNamespace allows virtual namespaces to be mapped to the namespace vCluster is running in
+	// when namespaces are synced to the host, see ValidateHostNamespace
+	AllowHostNamespaceInCurrentNamespace = false
+
+	// HashSalt is a secret per vCluster salt that is mixed into the hashes of host names, so that hashed host names
+	// cannot be guessed from the virtual names alone. It must not change during the lifetime of a vCluster, usually set at start time
+	HashSalt = ""
+
+	// CompatibilityMode is the version of the translation algorithm used for host names, see ValidateCompatibilityMode.
+	// It can be pinned to an older version during upgrades until host objects were migrated, usually set at start time
+	CompatibilityMode = CurrentTranslateVersion
+
 	ManagedAnnotationsAnnotation = "vcluster.loft.sh/managed-annotations"
 	ManagedLabelsAnnotation      = "vcluster.loft.sh/managed-labels"
 
@@ -64,4 +113,11 @@ type Translator interface {
 
 	// LabelsToTranslate are the labels that should be translated
 	LabelsToTranslate() map[string]bool
+
+	// HostLabel returns the host label key for a virtual label key
+	HostLabel(vLabel string) string
+
+	// Snapshot returns the effective translation configuration, which can be used to rebuild
+	// an equivalent translator with NewTranslatorFromSnapshot
+	Snapshot() TranslatorConfig
 }